@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pksep/comments/internal/db"
+	"github.com/pksep/comments/internal/modules/apikeys/model"
+	apikeyRepoPkg "github.com/pksep/comments/internal/modules/apikeys/repository"
+	apikeysSvc "github.com/pksep/comments/internal/modules/apikeys/service"
+	"github.com/pksep/comments/internal/modules/shared/auth"
+)
+
+const apikeysUsage = `usage:
+  server apikeys issue -name <name> -scopes read,write,moderate,admin [-tenant id] [-ttl 720h]
+  server apikeys rotate [-grace 24h] <id>
+  server apikeys revoke <id>
+  server apikeys list`
+
+// runAPIKeys — подкоманда управления API-ключами. Нужна прежде всего для выпуска
+// первого ключа с правом admin, остальными можно управлять через /api/admin/api-keys.
+// Результат печатается в stdout в JSON; секрет ключа показывается только при выпуске и ротации
+func runAPIKeys(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, apikeysUsage)
+		os.Exit(2)
+	}
+
+	pool, err := db.NewPostgresPool()
+	if err != nil {
+		log.Fatalf("Ошибка подключения к БД: %v", err)
+	}
+	defer pool.Close()
+
+	db.RunMigrations()
+
+	ctx := context.Background()
+	keys := apikeysSvc.NewKeyService(apikeyRepoPkg.NewKeyRepo(pool))
+
+	var result any
+	switch cmd, args := args[0], args[1:]; cmd {
+	case "issue":
+		fs := flag.NewFlagSet("apikeys issue", flag.ExitOnError)
+		name := fs.String("name", "", "имя клиента (обязательно)")
+		scopes := fs.String("scopes", "", "права через запятую: read, write, moderate, admin (обязательно)")
+		tenantID := fs.String("tenant", "", "тенант, в котором работает ключ (по умолчанию любой)")
+		ttl := fs.Duration("ttl", 0, "срок действия ключа (по умолчанию бессрочный)")
+		_ = fs.Parse(args)
+		if *name == "" || *scopes == "" {
+			fmt.Fprintln(os.Stderr, apikeysUsage)
+			os.Exit(2)
+		}
+
+		in := model.IssueInput{Name: *name}
+		for _, s := range strings.Split(*scopes, ",") {
+			in.Scopes = append(in.Scopes, auth.Scope(strings.TrimSpace(s)))
+		}
+		if *tenantID != "" {
+			in.TenantID = tenantID
+		}
+		if *ttl > 0 {
+			expiresAt := time.Now().Add(*ttl)
+			in.ExpiresAt = &expiresAt
+		}
+		result, err = keys.Issue(ctx, in)
+	case "rotate":
+		fs := flag.NewFlagSet("apikeys rotate", flag.ExitOnError)
+		grace := fs.Duration("grace", 24*time.Hour, "сколько старый ключ продолжает работать")
+		_ = fs.Parse(args)
+		if fs.NArg() != 1 {
+			fmt.Fprintln(os.Stderr, apikeysUsage)
+			os.Exit(2)
+		}
+		result, err = keys.Rotate(ctx, fs.Arg(0), *grace)
+	case "revoke":
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, apikeysUsage)
+			os.Exit(2)
+		}
+		result, err = keys.Revoke(ctx, args[0])
+	case "list":
+		result, err = keys.List(ctx)
+	default:
+		fmt.Fprintln(os.Stderr, apikeysUsage)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatalf("Ошибка: %v", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(result)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/pksep/comments/internal/cache"
+	"github.com/pksep/comments/internal/config"
+	"github.com/pksep/comments/internal/db"
+	auditRepoPkg "github.com/pksep/comments/internal/modules/audit/repository"
+	commentRepoPkg "github.com/pksep/comments/internal/modules/comments/repository"
+	"github.com/pksep/comments/internal/modules/imports/model"
+	importsSvc "github.com/pksep/comments/internal/modules/imports/service"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+	tenantRepoPkg "github.com/pksep/comments/internal/modules/tenants/repository"
+)
+
+// runImport — подкоманда импорта комментариев из файла JSONL или CSV:
+//
+//	server import -source legacy [-tenant default] [-format jsonl|csv] [-batch 500] <file|->
+//
+// Отчёт печатается в stdout в JSON. Код выхода 1, если хотя бы одна строка не импортирована
+func runImport(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	source := fs.String("source", "", "имя исходной системы (обязательно)")
+	tenantID := fs.String("tenant", tenant.Default, "тенант, в который импортируются треды и комментарии")
+	format := fs.String("format", "", "формат файла: jsonl или csv (по умолчанию по расширению)")
+	batch := fs.Int("batch", importsSvc.DefaultBatchSize, "сколько комментариев вставлять одной транзакцией")
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 || *source == "" {
+		fmt.Fprintln(os.Stderr, "usage: server import -source <name> [-tenant id] [-format jsonl|csv] [-batch N] <file|->")
+		os.Exit(2)
+	}
+
+	path := fs.Arg(0)
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			log.Fatalf("Ошибка открытия файла: %v", err)
+		}
+		defer f.Close()
+		in = f
+	}
+	if *format == "" {
+		*format = string(model.FormatJSONL)
+		if filepath.Ext(path) == ".csv" {
+			*format = string(model.FormatCSV)
+		}
+	}
+
+	pool, err := db.NewPostgresPool()
+	if err != nil {
+		log.Fatalf("Ошибка подключения к БД: %v", err)
+	}
+	defer pool.Close()
+
+	db.RunMigrations()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	t, err := tenantRepoPkg.NewTenantRepo(pool).Get(ctx, *tenantID)
+	if err != nil {
+		log.Fatalf("Ошибка чтения тенанта: %v", err)
+	}
+	if t == nil {
+		log.Fatalf("Тенант %q не найден", *tenantID)
+	}
+	ctx = tenant.WithTenant(ctx, *t)
+
+	var commentRepo commentRepoPkg.CommentRepoInterface = commentRepoPkg.NewCommentRepo(pool, auditRepoPkg.NewAuditRepo(pool))
+	// С общим кэшем (Redis) импорт сбрасывает закэшированные треды работающего сервиса
+	cfg := config.GetConfig()
+	cacheStore, err := cache.NewFromConfig(cfg.Cache)
+	if err != nil {
+		log.Fatalf("Ошибка настройки кэша: %v", err)
+	}
+	if cacheStore != nil {
+		commentRepo = commentRepoPkg.NewCachedCommentRepo(commentRepo, cacheStore, cfg.Cache.TTL, &cache.Metrics{})
+	}
+	report, err := importsSvc.NewImportService(commentRepo).Import(ctx, in, model.Options{
+		Format:    model.Format(*format),
+		Source:    *source,
+		BatchSize: *batch,
+	})
+	if err != nil {
+		log.Fatalf("Ошибка импорта: %v", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(report)
+
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
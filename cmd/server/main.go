@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/joho/godotenv"
 	"github.com/pksep/comments/internal/app"
@@ -16,6 +17,16 @@ func init() {
 }
 
 func main() {
+	// Подкоманды
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		runImport(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "apikeys" {
+		runAPIKeys(os.Args[2:])
+		return
+	}
+
 	cfg := config.GetConfig()
 
 	// Создаём пул подключений к Postgres
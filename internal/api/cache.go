@@ -0,0 +1,25 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pksep/comments/internal/cache"
+)
+
+type CacheHandler struct {
+	metrics *cache.Metrics
+}
+
+func NewCacheHandler(metrics *cache.Metrics) *CacheHandler {
+	return &CacheHandler{metrics: metrics}
+}
+
+// Stats отдаёт счётчики попаданий и промахов кэша чтения тредов
+func (h *CacheHandler) Stats(c *gin.Context) {
+	if h.metrics == nil {
+		c.JSON(http.StatusOK, cache.Stats{})
+		return
+	}
+	c.JSON(http.StatusOK, h.metrics.Snapshot())
+}
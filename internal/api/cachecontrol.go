@@ -0,0 +1,25 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pksep/comments/internal/config"
+)
+
+// CacheControl выставляет Cache-Control ответам на GET и HEAD: значение для маршрута
+// из cfg.Routes, иначе cfg.CacheControl. Пустое значение заголовок не выставляет
+func CacheControl(cfg config.HTTPCacheConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			value, ok := cfg.Routes[c.FullPath()]
+			if !ok {
+				value = cfg.CacheControl
+			}
+			if value != "" {
+				c.Header("Cache-Control", value)
+			}
+		}
+		c.Next()
+	}
+}
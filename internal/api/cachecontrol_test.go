@@ -0,0 +1,40 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pksep/comments/internal/config"
+)
+
+func TestCacheControl(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(CacheControl(config.HTTPCacheConfig{
+		CacheControl: "private, no-cache",
+		Routes:       map[string]string{"/threads/:id": "public, max-age=60", "/raw": ""},
+	}))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/threads/:id", ok)
+	r.GET("/comments", ok)
+	r.GET("/raw", ok)
+	r.POST("/comments", ok)
+
+	cases := []struct {
+		method, path, want string
+	}{
+		{http.MethodGet, "/threads/t1", "public, max-age=60"},
+		{http.MethodGet, "/comments", "private, no-cache"},
+		{http.MethodGet, "/raw", ""},
+		{http.MethodPost, "/comments", ""},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+		if got := w.Header().Get("Cache-Control"); got != tc.want {
+			t.Errorf("%s %s: Cache-Control = %q, want %q", tc.method, tc.path, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pksep/comments/internal/modules/shared/requestmeta"
+)
+
+// RequestIDHeader — заголовок с идентификатором запроса
+const RequestIDHeader = "X-Request-ID"
+
+// RequestMeta присваивает запросу идентификатор (или берёт его из заголовка)
+// и кладёт IP и User-Agent в контекст запроса
+func RequestMeta() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		ctx := requestmeta.WithMeta(c.Request.Context(), requestmeta.Meta{
+			RequestID: requestID,
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		})
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -3,11 +3,30 @@ package api
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/pksep/comments/internal/services"
+	"github.com/pksep/comments/internal/cache"
+	"github.com/pksep/comments/internal/config"
+	apikeysApi "github.com/pksep/comments/internal/modules/apikeys/api"
+	auditApi "github.com/pksep/comments/internal/modules/audit/api"
 	commentsApi "github.com/pksep/comments/internal/modules/comments/api"
+	digestApi "github.com/pksep/comments/internal/modules/digest/api"
+	draftsApi "github.com/pksep/comments/internal/modules/drafts/api"
+	gdprApi "github.com/pksep/comments/internal/modules/gdpr/api"
+	idempotencyApi "github.com/pksep/comments/internal/modules/idempotency/api"
+	importsApi "github.com/pksep/comments/internal/modules/imports/api"
+	"github.com/pksep/comments/internal/modules/shared/auth"
+	subscriptionsApi "github.com/pksep/comments/internal/modules/subscriptions/api"
+	tenantsApi "github.com/pksep/comments/internal/modules/tenants/api"
+	threadsApi "github.com/pksep/comments/internal/modules/threads/api"
+	"github.com/pksep/comments/internal/services"
 )
 
 type RouterDeps struct {
+	// Запросы к /api без API-ключа отклоняются
+	APIKeysRequired bool
+	// Счётчики кэша чтения тредов
+	CacheMetrics *cache.Metrics
+	// Заголовки Cache-Control ответов на чтение
+	HTTPCache config.HTTPCacheConfig
 }
 
 func RegisterRoutes(r *gin.Engine, deps *RouterDeps, services *services.Services, dbPool *pgxpool.Pool) {
@@ -16,9 +35,72 @@ func RegisterRoutes(r *gin.Engine, deps *RouterDeps, services *services.Services
 	r.GET("/health", healthHandler.Health)
 	r.GET("/ready", healthHandler.Ready)
 
-	api := r.Group("/api")
+	// Все маршруты API проверяют API-ключ и работают в тенанте запроса
+	api := r.Group("/api",
+		CacheControl(deps.HTTPCache),
+		apikeysApi.Middleware(services.KeyService, deps.APIKeysRequired, requiredScope),
+		tenantsApi.Middleware(services.TenantService),
+	)
 
 	// Роуты комментариев
-	commentHandler := commentsApi.NewCommentHandler(services.CommentService)
+	commentHandler := commentsApi.NewCommentHandler(
+		services.CommentService,
+		idempotencyApi.Middleware(services.IdempotencyService),
+	)
 	commentHandler.RegisterRoutes(api)
+
+	// Модерация всегда требует ключ с правом moderate, даже если API_KEYS_REQUIRED=false:
+	// без ключа любой мог бы скрывать комментарии от имени любого модератора
+	moderation := api.Group("/moderation", apikeysApi.RequireScope(auth.ScopeModerate))
+	commentHandler.RegisterModerationRoutes(moderation)
+
+	// Роуты тредов
+	threadHandler := threadsApi.NewThreadHandler(services.ThreadService)
+	threadHandler.RegisterRoutes(api)
+
+	// Черновики комментариев
+	draftHandler := draftsApi.NewDraftHandler(services.DraftService)
+	draftHandler.RegisterRoutes(api)
+
+	// Подписки на треды
+	subscriptionHandler := subscriptionsApi.NewSubscriptionHandler(services.SubscriptionService)
+	subscriptionHandler.RegisterRoutes(api)
+
+	// Ресурсные маршруты v2
+	v2 := api.Group("/v2")
+	commentHandler.RegisterRoutesV2(v2)
+	draftHandler.RegisterRoutesV2(v2)
+	subscriptionHandler.RegisterRoutesV2(v2)
+
+	// Служебные маршруты всегда требуют ключ с правом admin, даже если API_KEYS_REQUIRED=false
+	admin := api.Group("/admin", apikeysApi.RequireScope(auth.ScopeAdmin))
+
+	// Журнал аудита
+	auditHandler := auditApi.NewAuditHandler(services.AuditService)
+	auditHandler.RegisterRoutes(admin)
+
+	// Выгрузка и удаление данных автора
+	gdprHandler := gdprApi.NewGDPRHandler(services.GDPRService)
+	gdprHandler.RegisterRoutes(admin)
+
+	// Импорт комментариев из других систем
+	importHandler := importsApi.NewImportHandler(services.ImportService)
+	importHandler.RegisterRoutes(admin)
+
+	// Сводки ответов и упоминаний
+	digestHandler := digestApi.NewDigestHandler(services.DigestService)
+	digestHandler.RegisterRoutes(admin)
+	subscriptionHandler.RegisterAdminRoutes(admin)
+
+	// Тенанты и их настройки
+	tenantHandler := tenantsApi.NewTenantHandler(services.TenantService)
+	tenantHandler.RegisterRoutes(admin)
+
+	// API-ключи сервисов-клиентов
+	keyHandler := apikeysApi.NewKeyHandler(services.KeyService)
+	keyHandler.RegisterRoutes(admin)
+
+	// Счётчики кэша чтения тредов
+	cacheHandler := NewCacheHandler(deps.CacheMetrics)
+	admin.GET("/cache/stats", cacheHandler.Stats)
 }
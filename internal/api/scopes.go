@@ -0,0 +1,34 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pksep/comments/internal/modules/shared/auth"
+)
+
+// requiredScope — право API-ключа, которого требует маршрут: служебные маршруты —
+// admin, модерация и выгрузка со скрытыми комментариями — moderate, чтение — read,
+// остальное — write
+func requiredScope(c *gin.Context) auth.Scope {
+	path := c.FullPath()
+	switch {
+	case strings.HasPrefix(path, "/api/admin/"):
+		return auth.ScopeAdmin
+	case strings.HasPrefix(path, "/api/moderation/"):
+		return auth.ScopeModerate
+	case path == "/api/threads/:id/export":
+		if hidden, _ := strconv.ParseBool(c.Query("include_hidden")); hidden {
+			return auth.ScopeModerate
+		}
+		return auth.ScopeRead
+	case c.Request.Method == http.MethodGet, c.Request.Method == http.MethodHead,
+		// Сводки по тредам читаются POST-ом из-за длинного списка id
+		path == "/api/threads/summaries":
+		return auth.ScopeRead
+	default:
+		return auth.ScopeWrite
+	}
+}
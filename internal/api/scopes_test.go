@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pksep/comments/internal/modules/shared/auth"
+)
+
+func TestRequiredScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	var got auth.Scope
+	record := func(c *gin.Context) { got = requiredScope(c) }
+	r.GET("/api/admin/keys", record)
+	r.POST("/api/moderation/:id/approve", record)
+	r.GET("/api/threads/:id/export", record)
+	r.GET("/api/comments/by-thread/:threadId", record)
+	r.POST("/api/threads/summaries", record)
+	r.POST("/api/comments", record)
+
+	cases := []struct {
+		method, path string
+		want         auth.Scope
+	}{
+		{http.MethodGet, "/api/admin/keys", auth.ScopeAdmin},
+		{http.MethodPost, "/api/moderation/c1/approve", auth.ScopeModerate},
+		{http.MethodGet, "/api/threads/t1/export", auth.ScopeRead},
+		{http.MethodGet, "/api/threads/t1/export?include_hidden=true", auth.ScopeModerate},
+		{http.MethodGet, "/api/comments/by-thread/t1", auth.ScopeRead},
+		{http.MethodPost, "/api/threads/summaries", auth.ScopeRead},
+		{http.MethodPost, "/api/comments", auth.ScopeWrite},
+	}
+	for _, tc := range cases {
+		got = ""
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tc.method, tc.path, nil))
+		if got != tc.want {
+			t.Errorf("%s %s: scope = %q, want %q", tc.method, tc.path, got, tc.want)
+		}
+	}
+}
@@ -1,11 +1,26 @@
 package app
 
 import (
+	"context"
+	"log"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/pksep/comments/internal/api"
+	"github.com/pksep/comments/internal/cache"
 	"github.com/pksep/comments/internal/config"
+	apikeyRepoPkg "github.com/pksep/comments/internal/modules/apikeys/repository"
+	auditRepoPkg "github.com/pksep/comments/internal/modules/audit/repository"
+	"github.com/pksep/comments/internal/modules/comments/moderation"
 	commentRepoPkg "github.com/pksep/comments/internal/modules/comments/repository"
+	"github.com/pksep/comments/internal/modules/digest/notifier"
+	digestRepoPkg "github.com/pksep/comments/internal/modules/digest/repository"
+	draftRepoPkg "github.com/pksep/comments/internal/modules/drafts/repository"
+	gdprRepoPkg "github.com/pksep/comments/internal/modules/gdpr/repository"
+	idempotencyRepoPkg "github.com/pksep/comments/internal/modules/idempotency/repository"
+	subscriptionRepoPkg "github.com/pksep/comments/internal/modules/subscriptions/repository"
+	tenantRepoPkg "github.com/pksep/comments/internal/modules/tenants/repository"
 	threadRepoPkg "github.com/pksep/comments/internal/modules/threads/repository"
 	"github.com/pksep/comments/internal/services"
 )
@@ -13,17 +28,65 @@ import (
 func Init(pool *pgxpool.Pool) *gin.Engine {
 
 	// Инициализация репозиториев
-	commentRepo := commentRepoPkg.NewCommentRepo(pool)
+	auditRepo := auditRepoPkg.NewAuditRepo(pool)
+	var commentRepo commentRepoPkg.CommentRepoInterface = commentRepoPkg.NewCommentRepo(pool, auditRepo)
+	idempotencyRepo := idempotencyRepoPkg.NewKeyRepo(pool)
 	threadRepo := threadRepoPkg.NewThreadRepo(pool)
+	gdprJobRepo := gdprRepoPkg.NewJobRepo(pool)
+	draftRepo := draftRepoPkg.NewDraftRepo(pool)
+	digestRepo := digestRepoPkg.NewDigestRepo(pool)
+	subscriptionRepo := subscriptionRepoPkg.NewSubscriptionRepo(pool)
+	tenantRepo := tenantRepoPkg.NewTenantRepo(pool)
+	keyRepo := apikeyRepoPkg.NewKeyRepo(pool)
+
+	cfg := config.GetConfig()
+
+	// Кэш чтения тредов; nil — кэш отключён
+	cacheStore, err := cache.NewFromConfig(cfg.Cache)
+	if err != nil {
+		log.Fatalf("Ошибка настройки кэша: %v", err)
+	}
+	cacheMetrics := &cache.Metrics{}
+	if cacheStore != nil {
+		commentRepo = commentRepoPkg.NewCachedCommentRepo(commentRepo, cacheStore, cfg.Cache.TTL, cacheMetrics)
+	}
+
+	// Конвейер модерации комментариев
+	moderationPipeline, err := moderation.NewPipelineFromConfig(cfg.Moderation, commentRepo)
+	if err != nil {
+		log.Fatalf("Ошибка настройки модерации: %v", err)
+	}
+
+	// Доставка сводок ответов и упоминаний; nil — рассылка отключена
+	digestNotifier, err := notifier.NewNotifierFromConfig(cfg.Digest)
+	if err != nil {
+		log.Fatalf("Ошибка настройки рассылки сводок: %v", err)
+	}
 
 	// Инициализация сервисов
-	services := services.NewServices(commentRepo, threadRepo)
+	services := services.NewServices(cfg, commentRepo, threadRepo, auditRepo, idempotencyRepo, gdprJobRepo, draftRepo, digestRepo, subscriptionRepo, tenantRepo, keyRepo, moderationPipeline, digestNotifier)
+
+	// Фоновые задачи
+	go services.IdempotencyService.RunSweeper(context.Background(), time.Hour)
+	go services.GDPRService.RunWorker(context.Background(), 10*time.Second)
+	go services.DraftService.RunSweeper(context.Background(), time.Hour)
+	go services.CommentService.RunScheduler(context.Background(), 15*time.Second)
+	if digestNotifier != nil {
+		go services.DigestService.RunWorker(context.Background(), 15*time.Minute)
+	}
 
 	// Инициализация зависимостей для хэндлеров
-	deps := &api.RouterDeps{}
+	deps := &api.RouterDeps{
+		APIKeysRequired: cfg.APIKeysRequired,
+		CacheMetrics:    cacheMetrics,
+		HTTPCache:       cfg.HTTPCache,
+	}
 
 	// Инициализация Gin
 	r := gin.Default()
+	// Значения из c.Request.Context() доступны через gin.Context, который передаётся в сервисы
+	r.ContextWithFallback = true
+	r.Use(api.RequestMeta())
 
 	// Swagger
 	swaggerCfg := &config.SwaggerConfig{
@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pksep/comments/internal/config"
+)
+
+// Cache — хранилище байтовых значений с ограниченным сроком жизни.
+// Промах не считается ошибкой: Get возвращает ok = false, GetMulti — nil на месте ключа
+type Cache interface {
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	GetMulti(ctx context.Context, keys []string) ([][]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Add записывает значение, только если ключа ещё нет, и сообщает, записано ли оно
+	Add(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// NewFromConfig создаёт кэш по настройкам: memory или redis; пустой backend — кэш
+// отключён (nil без ошибки)
+func NewFromConfig(cfg config.CacheConfig) (Cache, error) {
+	switch cfg.Backend {
+	case "":
+		return nil, nil
+	case "memory":
+		return NewMemory(cfg.MemoryMaxEntries), nil
+	case "redis":
+		if cfg.Redis.Addr == "" {
+			return nil, fmt.Errorf("REDIS_ADDR is required for CACHE_BACKEND=redis")
+		}
+		return NewRedis(cfg.Redis), nil
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q", cfg.Backend)
+	}
+}
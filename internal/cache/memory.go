@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// Memory — кэш в памяти процесса. Подходит для одной реплики: инвалидация
+// не доходит до кэшей других реплик
+type Memory struct {
+	mu         sync.Mutex
+	entries    map[string]memoryEntry
+	maxEntries int
+}
+
+// NewMemory создаёт кэш в памяти; maxEntries ограничивает число записей (0 — без ограничений)
+func NewMemory(maxEntries int) *Memory {
+	return &Memory{entries: map[string]memoryEntry{}, maxEntries: maxEntries}
+}
+
+func (m *Memory) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.get(key, time.Now())
+	return v, ok, nil
+}
+
+func (m *Memory) GetMulti(_ context.Context, keys []string) ([][]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		values[i], _ = m.get(key, now)
+	}
+	return values, nil
+}
+
+func (m *Memory) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.set(key, value, ttl, time.Now())
+	return nil
+}
+
+// Add проверяет и записывает ключ под одной блокировкой: из параллельных
+// вызовов true получает только один
+func (m *Memory) Add(_ context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	if _, ok := m.get(key, now); ok {
+		return false, nil
+	}
+	m.set(key, value, ttl, now)
+	return true, nil
+}
+
+func (m *Memory) Delete(_ context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		delete(m.entries, key)
+	}
+	return nil
+}
+
+// get возвращает живую запись, просроченную удаляет. Вызывается под m.mu
+func (m *Memory) get(key string, now time.Time) ([]byte, bool) {
+	e, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !now.Before(e.expiresAt) {
+		delete(m.entries, key)
+		return nil, false
+	}
+	return e.value, true
+}
+
+// set записывает значение, освобождая место при переполнении. Вызывается под m.mu
+func (m *Memory) set(key string, value []byte, ttl time.Duration, now time.Time) {
+	if _, exists := m.entries[key]; !exists && m.maxEntries > 0 && len(m.entries) >= m.maxEntries {
+		m.evict(now)
+	}
+	m.entries[key] = memoryEntry{value: value, expiresAt: now.Add(ttl)}
+}
+
+// evict освобождает место: удаляет просроченные записи, а если таких нет —
+// произвольную запись. Вызывается под m.mu
+func (m *Memory) evict(now time.Time) {
+	for key, e := range m.entries {
+		if !now.Before(e.expiresAt) {
+			delete(m.entries, key)
+		}
+	}
+	if len(m.entries) < m.maxEntries {
+		return
+	}
+	for key := range m.entries {
+		delete(m.entries, key)
+		return
+	}
+}
@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryAddOnce(t *testing.T) {
+	m := NewMemory(0)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	winners := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			added, err := m.Add(ctx, "gen", []byte(fmt.Sprint(i)), time.Minute)
+			if err != nil {
+				t.Error(err)
+			}
+			if added {
+				mu.Lock()
+				winners++
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	if winners != 1 {
+		t.Fatalf("%d concurrent Add calls succeeded, want 1", winners)
+	}
+
+	// Просроченная запись не мешает новой
+	m.Set(ctx, "old", []byte("v1"), -time.Second)
+	if added, _ := m.Add(ctx, "old", []byte("v2"), time.Minute); !added {
+		t.Fatal("Add over an expired key failed")
+	}
+}
@@ -0,0 +1,44 @@
+package cache
+
+import "sync/atomic"
+
+// Metrics — счётчики обращений к кэшу
+type Metrics struct {
+	hits          atomic.Int64
+	misses        atomic.Int64
+	shared        atomic.Int64
+	errors        atomic.Int64
+	invalidations atomic.Int64
+}
+
+// Stats — снимок счётчиков
+type Stats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+	// Shared — промахи, дождавшиеся загрузки, начатой другим запросом
+	Shared        int64   `json:"shared"`
+	Errors        int64   `json:"errors"`
+	Invalidations int64   `json:"invalidations"`
+	HitRatio      float64 `json:"hit_ratio"`
+}
+
+func (m *Metrics) Hit()          { m.hits.Add(1) }
+func (m *Metrics) Miss()         { m.misses.Add(1) }
+func (m *Metrics) Shared()       { m.shared.Add(1) }
+func (m *Metrics) Error()        { m.errors.Add(1) }
+func (m *Metrics) Invalidation() { m.invalidations.Add(1) }
+
+// Snapshot возвращает текущие значения счётчиков
+func (m *Metrics) Snapshot() Stats {
+	s := Stats{
+		Hits:          m.hits.Load(),
+		Misses:        m.misses.Load(),
+		Shared:        m.shared.Load(),
+		Errors:        m.errors.Load(),
+		Invalidations: m.invalidations.Load(),
+	}
+	if total := s.Hits + s.Misses; total > 0 {
+		s.HitRatio = float64(s.Hits) / float64(total)
+	}
+	return s
+}
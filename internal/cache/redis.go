@@ -0,0 +1,282 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/pksep/comments/internal/config"
+)
+
+const (
+	// redisPoolSize — сколько соединений открывает клиент; остальные команды ждут
+	// освободившегося соединения. Так промахи по многим ключам сразу не открывают
+	// к Redis неограниченное число сокетов
+	redisPoolSize = 16
+	// redisTimeout — предел на команду, если у контекста нет своего дедлайна
+	redisTimeout = time.Second
+)
+
+// Redis — кэш в Redis, общий для всех реплик. Клиент минимальный: RESP2 поверх TCP
+// и только команды, нужные кэшу (AUTH, SELECT, GET, MGET, SET, DEL)
+type Redis struct {
+	cfg  config.RedisConfig
+	idle chan *redisConn
+	// slots — разрешения на открытое соединение, занятое или простаивающее
+	slots chan struct{}
+}
+
+type redisConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+	w    *bufio.Writer
+}
+
+// redisError — ответ-ошибка сервера; соединение после него остаётся годным
+type redisError string
+
+func (e redisError) Error() string { return "redis: " + string(e) }
+
+// NewRedis создаёт клиент; соединения открываются при первой команде
+func NewRedis(cfg config.RedisConfig) *Redis {
+	return newRedis(cfg, redisPoolSize)
+}
+
+func newRedis(cfg config.RedisConfig, poolSize int) *Redis {
+	return &Redis{cfg: cfg, idle: make(chan *redisConn, poolSize), slots: make(chan struct{}, poolSize)}
+}
+
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	reply, err := r.do(ctx, "GET", key)
+	if err != nil {
+		return nil, false, err
+	}
+	v, ok := reply.([]byte)
+	return v, ok, nil
+}
+
+func (r *Redis) GetMulti(ctx context.Context, keys []string) ([][]byte, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	reply, err := r.do(ctx, append([]string{"MGET"}, keys...)...)
+	if err != nil {
+		return nil, err
+	}
+	items, ok := reply.([]any)
+	if !ok || len(items) != len(keys) {
+		return nil, fmt.Errorf("redis: unexpected MGET reply %T", reply)
+	}
+	values := make([][]byte, len(keys))
+	for i, item := range items {
+		values[i], _ = item.([]byte)
+	}
+	return values, nil
+}
+
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	_, err := r.do(ctx, "SET", key, string(value), "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	return err
+}
+
+func (r *Redis) Add(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	reply, err := r.do(ctx, "SET", key, string(value), "NX", "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	if err != nil {
+		return false, err
+	}
+	return reply != nil, nil
+}
+
+func (r *Redis) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := r.do(ctx, append([]string{"DEL"}, keys...)...)
+	return err
+}
+
+// do выполняет команду на соединении из пула. Соединение, на котором случилась
+// сетевая ошибка, ошибка разбора ответа или отмена ctx, закрывается
+func (r *Redis) do(ctx context.Context, args ...string) (any, error) {
+	c, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := c.exchange(ctx, args)
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		r.discard(c)
+		return nil, err
+	}
+	r.release(c)
+	return reply, err
+}
+
+// acquire берёт простаивающее соединение или открывает новое, если пул не исчерпан;
+// иначе ждёт освобождения соединения или отмены ctx
+func (r *Redis) acquire(ctx context.Context) (*redisConn, error) {
+	select {
+	case c := <-r.idle:
+		return c, nil
+	default:
+	}
+	select {
+	case c := <-r.idle:
+		return c, nil
+	case r.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	c, err := r.dial(ctx)
+	if err != nil {
+		<-r.slots
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *Redis) dial(ctx context.Context) (*redisConn, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", r.cfg.Addr)
+	if err != nil {
+		return nil, err
+	}
+	c := &redisConn{conn: conn, r: bufio.NewReader(conn), w: bufio.NewWriter(conn)}
+
+	if r.cfg.Password != "" {
+		if _, err := c.exchange(ctx, []string{"AUTH", r.cfg.Password}); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if r.cfg.DB != 0 {
+		if _, err := c.exchange(ctx, []string{"SELECT", strconv.Itoa(r.cfg.DB)}); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// release возвращает соединение в пул; место в idle есть всегда, потому что
+// соединений не больше, чем разрешений в slots
+func (r *Redis) release(c *redisConn) {
+	select {
+	case r.idle <- c:
+	default:
+		r.discard(c)
+	}
+}
+
+// discard закрывает соединение и освобождает его разрешение
+func (r *Redis) discard(c *redisConn) {
+	c.conn.Close()
+	<-r.slots
+}
+
+// exchange выполняет команду с дедлайном ctx (или redisTimeout). Отмена ctx
+// прерывает ожидание ответа сразу; после неё соединение непригодно, потому что
+// ответ мог быть прочитан не полностью
+func (c *redisConn) exchange(ctx context.Context, args []string) (any, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(redisTimeout)
+	}
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	stop := context.AfterFunc(ctx, func() {
+		_ = c.conn.SetDeadline(time.Now())
+	})
+	reply, err := c.roundTrip(args)
+	if !stop() {
+		return nil, ctx.Err()
+	}
+	return reply, err
+}
+
+// roundTrip отправляет команду массивом bulk-строк и читает один ответ
+func (c *redisConn) roundTrip(args []string) (any, error) {
+	fmt.Fprintf(c.w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(c.w, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if err := c.w.Flush(); err != nil {
+		return nil, err
+	}
+	return c.readReply()
+}
+
+// readReply разбирает ответ RESP2: строку, ошибку, число, bulk-строку ([]byte,
+// nil для отсутствующего значения) или массив ([]any)
+func (c *redisConn) readReply() (any, error) {
+	line, err := c.readLine()
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, errors.New("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return string(line[1:]), nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(string(line[1:]), 10, 64)
+	case '$':
+		n, err := strconv.Atoi(string(line[1:]))
+		if err != nil {
+			return nil, fmt.Errorf("redis: bad bulk length %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, buf); err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	case '*':
+		n, err := strconv.Atoi(string(line[1:]))
+		if err != nil {
+			return nil, fmt.Errorf("redis: bad array length %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]any, n)
+		for i := range items {
+			// Ошибка-элемент массива не портит поток ответа: дочитываем массив
+			item, err := c.readReply()
+			var replyErr redisError
+			if err != nil && !errors.As(err, &replyErr) {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply %q", line)
+	}
+}
+
+func (c *redisConn) readLine() ([]byte, error) {
+	line, err := c.r.ReadSlice('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("redis: malformed line %q", line)
+	}
+	return line[:len(line)-2], nil
+}
@@ -0,0 +1,196 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pksep/comments/internal/config"
+)
+
+// fakeRedis — сервер RESP2 с хранилищем в памяти. Пока hold не закрыт, ответы
+// на GET задерживаются
+type fakeRedis struct {
+	ln    net.Listener
+	conns atomic.Int32
+	hold  chan struct{}
+
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeRedis{ln: ln, hold: make(chan struct{}), data: map[string]string{}}
+	close(s.hold)
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.conns.Add(1)
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		hold := s.hold
+		s.mu.Unlock()
+		var reply string
+		switch strings.ToUpper(args[0]) {
+		case "GET":
+			<-hold
+			s.mu.Lock()
+			v, ok := s.data[args[1]]
+			s.mu.Unlock()
+			reply = "$-1\r\n"
+			if ok {
+				reply = fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+			}
+		case "SET":
+			s.mu.Lock()
+			s.data[args[1]] = args[2]
+			s.mu.Unlock()
+			reply = "+OK\r\n"
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		if _, err := r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args[i] = strings.TrimRight(arg, "\r\n")
+	}
+	return args, nil
+}
+
+func (s *fakeRedis) block() {
+	s.mu.Lock()
+	s.hold = make(chan struct{})
+	s.mu.Unlock()
+}
+
+func (s *fakeRedis) unblock() {
+	s.mu.Lock()
+	close(s.hold)
+	s.mu.Unlock()
+}
+
+func TestRedisSetGet(t *testing.T) {
+	srv := newFakeRedis(t)
+	r := NewRedis(config.RedisConfig{Addr: srv.ln.Addr().String()})
+	ctx := context.Background()
+
+	if err := r.Set(ctx, "k", []byte("v"), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	v, ok, err := r.Get(ctx, "k")
+	if err != nil || !ok || string(v) != "v" {
+		t.Fatalf("Get = %q, %v, %v", v, ok, err)
+	}
+	if _, ok, _ := r.Get(ctx, "missing"); ok {
+		t.Fatal("missing key found")
+	}
+}
+
+func TestRedisPoolIsBounded(t *testing.T) {
+	srv := newFakeRedis(t)
+	r := newRedis(config.RedisConfig{Addr: srv.ln.Addr().String()}, 2)
+	srv.block()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if _, _, err := r.Get(ctx, "k"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	time.Sleep(100 * time.Millisecond)
+	srv.unblock()
+	wg.Wait()
+
+	if n := srv.conns.Load(); n > 2 {
+		t.Fatalf("%d connections opened, want at most the pool size", n)
+	}
+
+	// Команда, не дождавшаяся соединения, завершается по своему контексту
+	srv.block()
+	defer srv.unblock()
+	for i := 0; i < 2; i++ {
+		go r.Get(context.Background(), "k")
+	}
+	time.Sleep(50 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, _, err := r.Get(ctx, "k"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want DeadlineExceeded while the pool is busy", err)
+	}
+}
+
+func TestRedisCancelInterruptsRoundTrip(t *testing.T) {
+	srv := newFakeRedis(t)
+	r := NewRedis(config.RedisConfig{Addr: srv.ln.Addr().String()})
+	srv.block()
+	defer srv.unblock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	_, _, err := r.Get(ctx, "k")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	// Без отслеживания отмены команда ждала бы redisTimeout
+	if elapsed := time.Since(start); elapsed >= redisTimeout/2 {
+		t.Fatalf("cancelled command took %v", elapsed)
+	}
+}
@@ -4,11 +4,91 @@ import (
 	"log"
 	"os"
 	"sync"
+	"time"
 )
 
 type Config struct {
 	DatabaseURL string
 	Port        string
+	Moderation  ModerationConfig
+	// Срок хранения ответов для заголовка Idempotency-Key
+	IdempotencyTTL time.Duration
+	// Максимум закреплённых комментариев в треде (0 — без ограничений)
+	MaxPinnedPerThread int
+	// Срок хранения черновика с последнего сохранения
+	DraftTTL time.Duration
+	Digest   DigestConfig
+	// Включает изоляцию тенантов на уровне строк Postgres в дополнение к фильтрам запросов
+	TenantRLS bool
+	// Сколько настройки тенанта живут в кэше middleware
+	TenantCacheTTL time.Duration
+	// Запросы без API-ключа отклоняются; иначе ключ проверяется, только если передан.
+	// Маршруты /api/admin требуют ключ с правом admin всегда
+	APIKeysRequired bool
+	Cache           CacheConfig
+	HTTPCache       HTTPCacheConfig
+}
+
+// HTTPCacheConfig — заголовок Cache-Control ответов на GET-запросы API
+type HTTPCacheConfig struct {
+	// Значение по умолчанию; пустое — заголовок не выставляется
+	CacheControl string
+	// Значения для отдельных маршрутов по шаблону пути gin,
+	// например /api/comments/by-thread/:threadId
+	Routes map[string]string
+}
+
+// CacheConfig — настройки кэша чтения тредов
+type CacheConfig struct {
+	// Хранилище: memory или redis; пустое значение отключает кэш
+	Backend string
+	// Срок жизни записи: страховка на случай потерянной инвалидации
+	TTL time.Duration
+	// Максимум записей для Backend=memory (0 — без ограничений)
+	MemoryMaxEntries int
+	Redis            RedisConfig
+}
+
+// RedisConfig — параметры подключения к Redis
+type RedisConfig struct {
+	Addr     string
+	Password string
+	DB       int
+}
+
+// ModerationConfig — настройки конвейера модерации комментариев
+type ModerationConfig struct {
+	// Запрещённые слова (без учёта регистра), комментарий отклоняется
+	BlockedWords []string
+	// Запрещённые регулярные выражения, комментарий отклоняется
+	BlockedPatterns []string
+	// Максимум ссылок в комментарии, сверх лимита — на премодерацию (0 — без ограничений)
+	MaxLinks int
+	// Окно поиска дублей одного автора (0 — проверка отключена)
+	DuplicateWindow time.Duration
+	// Число открытых жалоб, после которого комментарий скрывается автоматически (0 — не скрывать)
+	AutoHideReports int
+}
+
+// DigestConfig — настройки рассылки сводок ответов и упоминаний
+type DigestConfig struct {
+	// Способ доставки: log, file или smtp; пустое значение отключает рассылку
+	Notifier string
+	// Период сводки: пользователь получает не больше одной сводки за период
+	Interval time.Duration
+	// Файл для Notifier=file
+	File string
+	SMTP SMTPConfig
+	// Адрес получателя, %s заменяется на id пользователя
+	RecipientFormat string
+}
+
+// SMTPConfig — параметры почтового сервера
+type SMTPConfig struct {
+	Addr     string
+	From     string
+	Username string
+	Password string
 }
 
 var (
@@ -31,6 +111,45 @@ func GetConfig() *Config {
 		instance = &Config{
 			DatabaseURL: dbURL,
 			Port:        port,
+			Moderation: ModerationConfig{
+				BlockedWords:    getEnvList("MODERATION_BLOCKED_WORDS", ","),
+				BlockedPatterns: getEnvList("MODERATION_BLOCKED_PATTERNS", ";"),
+				MaxLinks:        getEnvInt("MODERATION_MAX_LINKS", 0),
+				DuplicateWindow: getEnvDuration("MODERATION_DUPLICATE_WINDOW", 0),
+				AutoHideReports: getEnvInt("MODERATION_AUTO_HIDE_REPORTS", 0),
+			},
+			IdempotencyTTL:     getEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+			MaxPinnedPerThread: getEnvInt("PIN_MAX_PER_THREAD", 3),
+			DraftTTL:           getEnvDuration("DRAFT_TTL", 30*24*time.Hour),
+			Digest: DigestConfig{
+				Notifier: os.Getenv("DIGEST_NOTIFIER"),
+				Interval: getEnvDuration("DIGEST_INTERVAL", 24*time.Hour),
+				File:     os.Getenv("DIGEST_FILE"),
+				SMTP: SMTPConfig{
+					Addr:     os.Getenv("SMTP_ADDR"),
+					From:     os.Getenv("SMTP_FROM"),
+					Username: os.Getenv("SMTP_USERNAME"),
+					Password: os.Getenv("SMTP_PASSWORD"),
+				},
+				RecipientFormat: getEnvDefault("DIGEST_RECIPIENT_FORMAT", "%s@localhost"),
+			},
+			TenantRLS:       getEnvBool("TENANT_RLS", false),
+			TenantCacheTTL:  getEnvDuration("TENANT_CACHE_TTL", time.Minute),
+			APIKeysRequired: getEnvBool("API_KEYS_REQUIRED", false),
+			Cache: CacheConfig{
+				Backend:          os.Getenv("CACHE_BACKEND"),
+				TTL:              getEnvDuration("CACHE_TTL", 5*time.Minute),
+				MemoryMaxEntries: getEnvInt("CACHE_MEMORY_MAX_ENTRIES", 10000),
+				Redis: RedisConfig{
+					Addr:     os.Getenv("REDIS_ADDR"),
+					Password: os.Getenv("REDIS_PASSWORD"),
+					DB:       getEnvInt("REDIS_DB", 0),
+				},
+			},
+			HTTPCache: HTTPCacheConfig{
+				CacheControl: getEnvDefault("HTTP_CACHE_CONTROL", "private, no-cache"),
+				Routes:       getEnvMap("HTTP_CACHE_CONTROL_ROUTES", ";"),
+			},
 		}
 	})
 	return instance
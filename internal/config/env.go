@@ -0,0 +1,90 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// getEnvInt читает целое число из переменной окружения
+func getEnvInt(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Fatalf("%s: ожидается целое число, получено %q", key, v)
+	}
+	return n
+}
+
+// getEnvDuration читает длительность (например 10m, 24h) из переменной окружения
+func getEnvDuration(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Fatalf("%s: ожидается длительность, получено %q", key, v)
+	}
+	return d
+}
+
+// getEnvBool читает логическое значение (true/false, 1/0) из переменной окружения
+func getEnvBool(key string, def bool) bool {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Fatalf("%s: ожидается true или false, получено %q", key, v)
+	}
+	return b
+}
+
+// getEnvList читает список значений, разделённых sep, отбрасывая пустые
+func getEnvList(key, sep string) []string {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	var result []string
+	for _, item := range strings.Split(v, sep) {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// getEnvMap читает пары ключ=значение, разделённые sep. Значение может быть пустым
+// и содержать «=» и запятые
+func getEnvMap(key, sep string) map[string]string {
+	items := getEnvList(key, sep)
+	if items == nil {
+		return nil
+	}
+	result := make(map[string]string, len(items))
+	for _, item := range items {
+		k, v, ok := strings.Cut(item, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			log.Fatalf("%s: ожидается ключ=значение, получено %q", key, item)
+		}
+		result[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return result
+}
+
+// getEnvDefault читает строку из переменной окружения, def — если она не задана
+func getEnvDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
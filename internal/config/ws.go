@@ -6,4 +6,4 @@ import (
 
 // RegisterRoutes registers optional WS routes. Disabled for now due to missing ws module.
 func RegisterRoutes(r *gin.Engine) {
-}
\ No newline at end of file
+}
@@ -3,13 +3,28 @@ package db
 import (
 	"context"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/pksep/comments/internal/config"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
 )
 
 func NewPostgresPool() (*pgxpool.Pool, error) {
 	cfg := config.GetConfig()
 	dsn := cfg.DatabaseURL
-	
-	return pgxpool.New(context.Background(), dsn)
+
+	poolCfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.TenantRLS {
+		// Каждое соединение при выдаче из пула получает тенант запроса для политик
+		// row-level security; вне запроса выставляется пустой тенант (все строки)
+		poolCfg.PrepareConn = func(ctx context.Context, conn *pgx.Conn) (bool, error) {
+			_, err := conn.Exec(ctx, `SELECT set_config('app.tenant_id', $1, false)`, tenant.ID(ctx))
+			return err == nil, err
+		}
+	}
+
+	return pgxpool.NewWithConfig(context.Background(), poolCfg)
 }
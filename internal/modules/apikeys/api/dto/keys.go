@@ -0,0 +1,17 @@
+package dto
+
+import "time"
+
+// IssueKeyDTO — тело POST /admin/api-keys
+type IssueKeyDTO struct {
+	Name      string     `json:"name" binding:"required,max=200"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,oneof=read write moderate admin"`
+	TenantID  *string    `json:"tenant_id,omitempty" binding:"omitempty,max=64"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// RotateKeyDTO — тело POST /admin/api-keys/:id/rotate
+type RotateKeyDTO struct {
+	// Сколько секунд старый ключ продолжает работать; по умолчанию сутки
+	GraceSeconds *int `json:"grace_seconds,omitempty" binding:"omitempty,min=0,max=2592000"`
+}
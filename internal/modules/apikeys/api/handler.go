@@ -0,0 +1,194 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pksep/comments/internal/modules/apikeys/api/dto"
+	"github.com/pksep/comments/internal/modules/apikeys/model"
+	"github.com/pksep/comments/internal/modules/apikeys/repository"
+	"github.com/pksep/comments/internal/modules/apikeys/service"
+	"github.com/pksep/comments/internal/modules/shared/auth"
+)
+
+// Header — заголовок с API-ключом; ключ также принимается как Authorization: Bearer
+const Header = "X-API-Key"
+
+// defaultGrace — сколько старый ключ работает после ротации, если не указано иное
+const defaultGrace = 24 * time.Hour
+
+// Middleware проверяет API-ключ запроса и право, которого требует маршрут (scopeOf),
+// и кладёт клиента в контекст. Без ключа запрос проходит, только если required = false
+func Middleware(s *service.KeyService, required bool, scopeOf func(c *gin.Context) auth.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		secret := c.GetHeader(Header)
+		if secret == "" {
+			if bearer, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok {
+				secret = strings.TrimSpace(bearer)
+			}
+		}
+		if secret == "" {
+			if required {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "api key required"})
+				return
+			}
+			c.Next()
+			return
+		}
+
+		p, err := s.Authenticate(c, secret)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, model.ErrInvalidKey) {
+				status = http.StatusUnauthorized
+			}
+			c.AbortWithStatusJSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		if scope := scopeOf(c); !p.Has(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "api key lacks scope " + string(scope)})
+			return
+		}
+
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), *p))
+		c.Next()
+	}
+}
+
+// RequireScope пропускает запрос, только если он пришёл с API-ключом, у которого есть
+// право scope, — независимо от того, обязательны ли ключи для остального API.
+// Ставится после Middleware
+func RequireScope(scope auth.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p, ok := auth.FromContext(c.Request.Context())
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "api key required"})
+			return
+		}
+		if !p.Has(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "api key lacks scope " + string(scope)})
+			return
+		}
+		c.Next()
+	}
+}
+
+type KeyHandler struct {
+	service *service.KeyService
+}
+
+func NewKeyHandler(service *service.KeyService) *KeyHandler {
+	return &KeyHandler{service: service}
+}
+
+// RegisterRoutes регистрирует управление API-ключами в админской группе
+func (h *KeyHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	keys := rg.Group("/api-keys")
+	{
+		keys.GET("", h.List)
+		keys.POST("", h.Issue)
+		keys.POST("/:id/rotate", h.Rotate)
+		keys.DELETE("/:id", h.Revoke)
+	}
+}
+
+func (h *KeyHandler) List(c *gin.Context) {
+	keys, err := h.service.List(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, keys)
+}
+
+func (h *KeyHandler) Issue(c *gin.Context) {
+	var body dto.IssueKeyDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	scopes := make([]auth.Scope, len(body.Scopes))
+	for i, s := range body.Scopes {
+		scopes[i] = auth.Scope(s)
+	}
+	key, err := h.service.Issue(c, model.IssueInput{
+		Name:      body.Name,
+		Scopes:    scopes,
+		TenantID:  body.TenantID,
+		ExpiresAt: body.ExpiresAt,
+	})
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, key)
+}
+
+func (h *KeyHandler) Rotate(c *gin.Context) {
+	id, ok := parseKeyID(c)
+	if !ok {
+		return
+	}
+	var body dto.RotateKeyDTO
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	grace := defaultGrace
+	if body.GraceSeconds != nil {
+		grace = time.Duration(*body.GraceSeconds) * time.Second
+	}
+
+	key, err := h.service.Rotate(c, id, grace)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, key)
+}
+
+func (h *KeyHandler) Revoke(c *gin.Context) {
+	id, ok := parseKeyID(c)
+	if !ok {
+		return
+	}
+
+	key, err := h.service.Revoke(c, id)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, key)
+}
+
+// errorStatus подбирает HTTP-статус для известных ошибок сервиса ключей
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, model.ErrInvalidScopes), errors.Is(err, model.ErrInvalidExpiry),
+		errors.Is(err, repository.ErrUnknownTenant):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parseKeyID проверяет id ключа из пути: id ключей — UUID
+func parseKeyID(c *gin.Context) (string, bool) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid api key id"})
+		return "", false
+	}
+	return id, true
+}
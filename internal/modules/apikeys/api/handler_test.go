@@ -0,0 +1,44 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pksep/comments/internal/modules/shared/auth"
+)
+
+func TestRequireScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name      string
+		principal *auth.Principal
+		want      int
+	}{
+		{"no key", nil, http.StatusUnauthorized},
+		{"read key", &auth.Principal{Scopes: []auth.Scope{auth.ScopeRead}}, http.StatusForbidden},
+		{"moderate key", &auth.Principal{Scopes: []auth.Scope{auth.ScopeModerate}}, http.StatusForbidden},
+		{"admin key", &auth.Principal{Scopes: []auth.Scope{auth.ScopeAdmin}}, http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(func(c *gin.Context) {
+				if tc.principal != nil {
+					c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), *tc.principal))
+				}
+			})
+			r.POST("/admin/api-keys", RequireScope(auth.ScopeAdmin), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/api-keys", nil))
+			if w.Code != tc.want {
+				t.Fatalf("status = %d, want %d", w.Code, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/pksep/comments/internal/modules/shared/auth"
+)
+
+var (
+	// ErrInvalidScopes — пустой или неизвестный набор прав
+	ErrInvalidScopes = errors.New("scopes must be a non-empty subset of read, write, moderate, admin")
+	// ErrInvalidKey — ключ не найден, отозван или истёк
+	ErrInvalidKey = errors.New("invalid api key")
+	// ErrInvalidExpiry — срок действия нового ключа уже прошёл
+	ErrInvalidExpiry = errors.New("expires_at must be in the future")
+)
+
+// Key — API-ключ сервиса-клиента. Сам ключ не хранится, только его хэш.
+// Ключ ограничивается правами и тенантом; ограничения по типу сущности нет,
+// потому что у тредов и комментариев нет типа сущности, по которому его проверять
+type Key struct {
+	ID       string       `json:"id" db:"id"`
+	Name     string       `json:"name" db:"name"`
+	Prefix   string       `json:"prefix" db:"prefix"`
+	Scopes   []auth.Scope `json:"scopes" db:"scopes"`
+	TenantID *string      `json:"tenant_id,omitempty" db:"tenant_id"`
+	// RotatedFrom — ключ, взамен которого выпущен этот
+	RotatedFrom *string    `json:"rotated_from,omitempty" db:"rotated_from"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+}
+
+// Active сообщает, принимается ли ключ в момент now
+func (k *Key) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// Principal — клиент, от имени которого работает запрос с этим ключом
+func (k *Key) Principal() auth.Principal {
+	return auth.Principal{KeyID: k.ID, Name: k.Name, Scopes: k.Scopes, TenantID: k.TenantID}
+}
+
+// IssuedKey — выпущенный ключ вместе с секретом. Секрет показывается один раз
+type IssuedKey struct {
+	Key
+	Secret string `json:"key"`
+}
+
+// IssueInput — параметры нового ключа
+type IssueInput struct {
+	Name     string
+	Scopes   []auth.Scope
+	TenantID *string
+	// ExpiresAt — окончание срока действия, nil — бессрочный ключ
+	ExpiresAt *time.Time
+}
+
+// Validate проверяет набор прав и срок действия
+func (in IssueInput) Validate() error {
+	if in.ExpiresAt != nil && !in.ExpiresAt.After(time.Now()) {
+		return ErrInvalidExpiry
+	}
+	if len(in.Scopes) == 0 {
+		return ErrInvalidScopes
+	}
+	for _, s := range in.Scopes {
+		if !s.Valid() {
+			return ErrInvalidScopes
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pksep/comments/internal/modules/shared/auth"
+)
+
+func TestKeyActive(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	cases := []struct {
+		name string
+		key  Key
+		want bool
+	}{
+		{"unlimited", Key{}, true},
+		{"not expired", Key{ExpiresAt: &future}, true},
+		{"expired", Key{ExpiresAt: &past}, false},
+		{"revoked", Key{RevokedAt: &past, ExpiresAt: &future}, false},
+	}
+	for _, tc := range cases {
+		if got := tc.key.Active(now); got != tc.want {
+			t.Errorf("%s: Active = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestIssueInputValidate(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+
+	cases := []struct {
+		name string
+		in   IssueInput
+		want error
+	}{
+		{"ok", IssueInput{Name: "digest", Scopes: []auth.Scope{auth.ScopeRead}}, nil},
+		{"no scopes", IssueInput{Name: "digest"}, ErrInvalidScopes},
+		{"unknown scope", IssueInput{Name: "digest", Scopes: []auth.Scope{"root"}}, ErrInvalidScopes},
+		{"expired", IssueInput{Name: "digest", Scopes: []auth.Scope{auth.ScopeRead}, ExpiresAt: &past}, ErrInvalidExpiry},
+	}
+	for _, tc := range cases {
+		if err := tc.in.Validate(); !errors.Is(err, tc.want) {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestPrincipalScopes(t *testing.T) {
+	acme := "acme"
+	p := (&Key{ID: "k1", Scopes: []auth.Scope{auth.ScopeRead}, TenantID: &acme}).Principal()
+	if !p.Has(auth.ScopeRead) || p.Has(auth.ScopeWrite) {
+		t.Fatalf("read key: scopes %v", p.Scopes)
+	}
+	if !p.AllowsTenant("acme") || p.AllowsTenant("other") {
+		t.Fatal("key bound to acme works in another tenant")
+	}
+
+	admin := (&Key{ID: "k2", Scopes: []auth.Scope{auth.ScopeAdmin}}).Principal()
+	if !admin.Has(auth.ScopeModerate) || !admin.AllowsTenant("other") {
+		t.Fatal("admin key without a tenant is restricted")
+	}
+}
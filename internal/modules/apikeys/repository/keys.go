@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pksep/comments/internal/modules/apikeys/model"
+	"github.com/pksep/comments/internal/modules/shared/auth"
+)
+
+var (
+	// ErrNotFound — ключа нет или он недоступен вызывающему
+	ErrNotFound = errors.New("api key not found")
+	// ErrUnknownTenant — ключ выпускается для несуществующего тенанта
+	ErrUnknownTenant = errors.New("tenant not found")
+)
+
+type KeyRepoInterface interface {
+	Create(ctx context.Context, key *model.Key, hash string) (*model.Key, error)
+	Get(ctx context.Context, id string) (*model.Key, error)
+	GetByHash(ctx context.Context, hash string) (*model.Key, error)
+	List(ctx context.Context, tenantID *string) ([]model.Key, error)
+	Rotate(ctx context.Context, id string, successor *model.Key, hash string, oldExpiresAt time.Time) (*model.Key, error)
+	Revoke(ctx context.Context, id string) (*model.Key, error)
+	Touch(ctx context.Context, id string, at time.Time) error
+}
+
+type KeyRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewKeyRepo(db *pgxpool.Pool) *KeyRepo {
+	return &KeyRepo{db: db}
+}
+
+const keyColumns = `id, name, prefix, scopes, tenant_id, rotated_from, created_at, expires_at, revoked_at, last_used_at`
+
+func scanKey(row interface{ Scan(...any) error }) (*model.Key, error) {
+	var k model.Key
+	var scopes []string
+	err := row.Scan(&k.ID, &k.Name, &k.Prefix, &scopes, &k.TenantID, &k.RotatedFrom,
+		&k.CreatedAt, &k.ExpiresAt, &k.RevokedAt, &k.LastUsedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	k.Scopes = make([]auth.Scope, len(scopes))
+	for i, s := range scopes {
+		k.Scopes[i] = auth.Scope(s)
+	}
+	return &k, nil
+}
+
+func scopeArgs(scopes []auth.Scope) []string {
+	args := make([]string, len(scopes))
+	for i, s := range scopes {
+		args[i] = string(s)
+	}
+	return args
+}
+
+// Create сохраняет новый ключ с хэшем секрета
+func (r *KeyRepo) Create(ctx context.Context, key *model.Key, hash string) (*model.Key, error) {
+	created, err := scanKey(r.db.QueryRow(ctx, `
+		INSERT INTO api_keys (id, name, prefix, key_hash, scopes, tenant_id, rotated_from, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING `+keyColumns,
+		key.ID, key.Name, key.Prefix, hash, scopeArgs(key.Scopes), key.TenantID, key.RotatedFrom, key.CreatedAt, key.ExpiresAt))
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+		return nil, ErrUnknownTenant
+	}
+	return created, err
+}
+
+// Get возвращает ключ по id
+func (r *KeyRepo) Get(ctx context.Context, id string) (*model.Key, error) {
+	return scanKey(r.db.QueryRow(ctx, `SELECT `+keyColumns+` FROM api_keys WHERE id = $1`, id))
+}
+
+// GetByHash находит ключ по хэшу секрета, в том числе отозванный
+func (r *KeyRepo) GetByHash(ctx context.Context, hash string) (*model.Key, error) {
+	return scanKey(r.db.QueryRow(ctx, `SELECT `+keyColumns+` FROM api_keys WHERE key_hash = $1`, hash))
+}
+
+// List возвращает ключи, новые первыми; tenantID ограничивает список ключами тенанта
+func (r *KeyRepo) List(ctx context.Context, tenantID *string) ([]model.Key, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+keyColumns+`
+		FROM api_keys
+		WHERE $1::text IS NULL OR tenant_id = $1
+		ORDER BY created_at DESC, id
+	`, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []model.Key{}
+	for rows.Next() {
+		k, err := scanKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *k)
+	}
+	return keys, rows.Err()
+}
+
+// Rotate выпускает преемника действующего ключа и сокращает срок старого до oldExpiresAt,
+// чтобы клиенты успели перейти на новый ключ
+func (r *KeyRepo) Rotate(ctx context.Context, id string, successor *model.Key, hash string, oldExpiresAt time.Time) (*model.Key, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE api_keys
+		SET expires_at = LEAST(COALESCE(expires_at, $2), $2)
+		WHERE id = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
+	`, id, oldExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrNotFound
+	}
+
+	created, err := scanKey(tx.QueryRow(ctx, `
+		INSERT INTO api_keys (id, name, prefix, key_hash, scopes, tenant_id, rotated_from, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING `+keyColumns,
+		successor.ID, successor.Name, successor.Prefix, hash, scopeArgs(successor.Scopes),
+		successor.TenantID, successor.RotatedFrom, successor.CreatedAt, successor.ExpiresAt))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// Revoke отзывает ключ; повторный отзыв сохраняет исходное время
+func (r *KeyRepo) Revoke(ctx context.Context, id string) (*model.Key, error) {
+	return scanKey(r.db.QueryRow(ctx, `
+		UPDATE api_keys SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE id = $1
+		RETURNING `+keyColumns,
+		id))
+}
+
+// Touch записывает время последнего использования ключа
+func (r *KeyRepo) Touch(ctx context.Context, id string, at time.Time) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE api_keys SET last_used_at = $2
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < $2)
+	`, id, at)
+	return err
+}
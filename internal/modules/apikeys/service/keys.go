@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pksep/comments/internal/modules/apikeys/model"
+	"github.com/pksep/comments/internal/modules/apikeys/repository"
+	"github.com/pksep/comments/internal/modules/shared/auth"
+)
+
+const (
+	// secretPrefix отличает ключи сервиса в логах и сканерах секретов
+	secretPrefix = "cmk_"
+	// displayPrefixLength — сколько символов ключа хранится открыто для опознания
+	displayPrefixLength = 12
+	// touchInterval — не чаще этого last_used_at обновляется в базе
+	touchInterval = time.Minute
+)
+
+// ErrForbidden — ключ тенанта не может выпускать ключи другого тенанта или без тенанта
+var ErrForbidden = errors.New("api key is restricted to another tenant")
+
+type KeyService struct {
+	repo repository.KeyRepoInterface
+}
+
+// NewKeyService создаёт сервис API-ключей
+func NewKeyService(repo repository.KeyRepoInterface) *KeyService {
+	return &KeyService{repo: repo}
+}
+
+// Issue выпускает ключ. Клиент, ограниченный тенантом, выпускает ключи только своего тенанта
+func (s *KeyService) Issue(ctx context.Context, in model.IssueInput) (*model.IssuedKey, error) {
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+	if p, ok := auth.FromContext(ctx); ok && p.TenantID != nil {
+		if in.TenantID == nil {
+			in.TenantID = p.TenantID
+		} else if *in.TenantID != *p.TenantID {
+			return nil, ErrForbidden
+		}
+	}
+
+	key := model.Key{
+		ID:        uuid.New().String(),
+		Name:      in.Name,
+		Scopes:    in.Scopes,
+		TenantID:  in.TenantID,
+		CreatedAt: time.Now(),
+		ExpiresAt: in.ExpiresAt,
+	}
+	secret, hash, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+	key.Prefix = secret[:displayPrefixLength]
+
+	created, err := s.repo.Create(ctx, &key, hash)
+	if err != nil {
+		return nil, err
+	}
+	return &model.IssuedKey{Key: *created, Secret: secret}, nil
+}
+
+// Rotate выпускает новый ключ с теми же правами; старый продолжает работать ещё grace
+func (s *KeyService) Rotate(ctx context.Context, id string, grace time.Duration) (*model.IssuedKey, error) {
+	old, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	successor := model.Key{
+		ID:          uuid.New().String(),
+		Name:        old.Name,
+		Scopes:      old.Scopes,
+		TenantID:    old.TenantID,
+		RotatedFrom: &old.ID,
+		CreatedAt:   now,
+	}
+	// Срочный ключ заменяется ключом с тем же сроком жизни
+	if old.ExpiresAt != nil {
+		expiresAt := now.Add(old.ExpiresAt.Sub(old.CreatedAt))
+		successor.ExpiresAt = &expiresAt
+	}
+	secret, hash, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+	successor.Prefix = secret[:displayPrefixLength]
+
+	created, err := s.repo.Rotate(ctx, old.ID, &successor, hash, now.Add(grace))
+	if err != nil {
+		return nil, err
+	}
+	return &model.IssuedKey{Key: *created, Secret: secret}, nil
+}
+
+// Revoke отзывает ключ немедленно
+func (s *KeyService) Revoke(ctx context.Context, id string) (*model.Key, error) {
+	if _, err := s.get(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.Revoke(ctx, id)
+}
+
+// List возвращает ключи, доступные вызывающему
+func (s *KeyService) List(ctx context.Context) ([]model.Key, error) {
+	var tenantID *string
+	if p, ok := auth.FromContext(ctx); ok {
+		tenantID = p.TenantID
+	}
+	return s.repo.List(ctx, tenantID)
+}
+
+// Authenticate проверяет секрет и возвращает клиента. Время использования
+// записывается не чаще touchInterval, ошибка записи только логируется
+func (s *KeyService) Authenticate(ctx context.Context, secret string) (*auth.Principal, error) {
+	if !strings.HasPrefix(secret, secretPrefix) {
+		return nil, model.ErrInvalidKey
+	}
+	key, err := s.repo.GetByHash(ctx, hashSecret(secret))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, model.ErrInvalidKey
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if !key.Active(now) {
+		return nil, model.ErrInvalidKey
+	}
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= touchInterval {
+		if err := s.repo.Touch(ctx, key.ID, now); err != nil {
+			log.Printf("Ошибка записи времени использования ключа %s: %v", key.ID, err)
+		}
+	}
+
+	p := key.Principal()
+	return &p, nil
+}
+
+// get возвращает ключ, если он доступен вызывающему; ключи чужих тенантов не видны
+func (s *KeyService) get(ctx context.Context, id string) (*model.Key, error) {
+	key, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if p, ok := auth.FromContext(ctx); ok && p.TenantID != nil {
+		if key.TenantID == nil || *key.TenantID != *p.TenantID {
+			return nil, repository.ErrNotFound
+		}
+	}
+	return key, nil
+}
+
+// newSecret генерирует секрет ключа и его хэш для хранения
+func newSecret() (secret, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	secret = secretPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	return secret, hashSecret(secret), nil
+}
+
+// hashSecret — SHA-256 секрета. Секрет случайный и длинный, медленный хэш не нужен
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
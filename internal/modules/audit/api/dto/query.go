@@ -0,0 +1,15 @@
+package dto
+
+import "time"
+
+type AuditQuery struct {
+	CommentID string     `form:"comment_id"`
+	ThreadID  string     `form:"thread_id"`
+	ActorID   string     `form:"actor_id"`
+	AuthorID  string     `form:"author_id"`
+	Action    string     `form:"action" binding:"omitempty,oneof=create update delete restore approve hide pin unpin accept unaccept erase schedule"`
+	From      *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To        *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit     int        `form:"limit,default=50" binding:"min=1,max=500"`
+	Offset    int        `form:"offset" binding:"min=0"`
+}
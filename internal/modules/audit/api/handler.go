@@ -0,0 +1,81 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pksep/comments/internal/modules/audit/api/dto"
+	"github.com/pksep/comments/internal/modules/audit/model"
+	"github.com/pksep/comments/internal/modules/audit/service"
+)
+
+type AuditHandler struct {
+	service *service.AuditService
+}
+
+func NewAuditHandler(service *service.AuditService) *AuditHandler {
+	return &AuditHandler{service: service}
+}
+
+func (h *AuditHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	audit := rg.Group("/audit")
+	{
+		audit.GET("", h.List)
+		audit.GET("/export", h.Export) // JSONL, те же фильтры без пагинации
+	}
+}
+
+func (h *AuditHandler) List(c *gin.Context) {
+	filter, ok := bindFilter(c)
+	if !ok {
+		return
+	}
+
+	entries, err := h.service.List(c, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+func (h *AuditHandler) Export(c *gin.Context) {
+	filter, ok := bindFilter(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Header("Content-Disposition", `attachment; filename="comment-audit.jsonl"`)
+	c.Status(http.StatusOK)
+
+	enc := json.NewEncoder(c.Writer)
+	err := h.service.Export(c, filter, func(e model.Entry) error {
+		return enc.Encode(e)
+	})
+	if err != nil {
+		// Заголовки уже отправлены, поэтому прерываем поток
+		_ = c.Error(err)
+		c.Abort()
+	}
+}
+
+func bindFilter(c *gin.Context) (model.Filter, bool) {
+	var query dto.AuditQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return model.Filter{}, false
+	}
+	return model.Filter{
+		CommentID:       query.CommentID,
+		ThreadID:        query.ThreadID,
+		ActorID:         query.ActorID,
+		SubjectAuthorID: query.AuthorID,
+		Action:          model.Action(query.Action),
+		From:            query.From,
+		To:              query.To,
+		Limit:           query.Limit,
+		Offset:          query.Offset,
+	}, true
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Action string
+
+const (
+	ActionCreate  Action = "create"
+	ActionUpdate  Action = "update"
+	ActionDelete  Action = "delete"
+	ActionRestore Action = "restore"
+	ActionApprove Action = "approve"
+	ActionHide    Action = "hide"
+	ActionPin     Action = "pin"
+	ActionUnpin   Action = "unpin"
+	// ActionAccept и ActionUnaccept — выбор и отмена принятого ответа в треде
+	ActionAccept   Action = "accept"
+	ActionUnaccept Action = "unaccept"
+	// ActionErase — обезличивание комментария по запросу на удаление данных автора
+	ActionErase Action = "erase"
+	// ActionSchedule — создание комментария с отложенной публикацией; при публикации
+	// записывается обычный ActionCreate
+	ActionSchedule Action = "schedule"
+)
+
+// SystemActor — исполнитель автоматических действий (например, автоскрытия по жалобам)
+const SystemActor = "system"
+
+// Entry — запись журнала аудита об одном изменении комментария
+type Entry struct {
+	ID        string          `json:"id" db:"id"`
+	CommentID string          `json:"comment_id" db:"comment_id"`
+	ThreadID  *string         `json:"thread_id,omitempty" db:"thread_id"`
+	ActorID   string          `json:"actor_id" db:"actor_id"`
+	Action    Action          `json:"action" db:"action"`
+	Before    json.RawMessage `json:"before,omitempty" db:"before"`
+	After     json.RawMessage `json:"after,omitempty" db:"after"`
+	RequestID string          `json:"request_id" db:"request_id"`
+	IP        string          `json:"ip" db:"ip"`
+	UserAgent string          `json:"user_agent" db:"user_agent"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
+
+// Filter — условия выборки из журнала; пустые поля не ограничивают выборку
+type Filter struct {
+	CommentID string
+	ThreadID  string
+	ActorID   string
+	// Автор изменённого комментария (по снимкам), а не исполнитель действия
+	SubjectAuthorID string
+	Action          Action
+	From            *time.Time
+	To              *time.Time
+	Limit           int
+	Offset          int
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pksep/comments/internal/db"
+	"github.com/pksep/comments/internal/modules/audit/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+type AuditRepoInterface interface {
+	Record(ctx context.Context, q db.Querier, entry *model.Entry) error
+	List(ctx context.Context, filter model.Filter) ([]model.Entry, error)
+	Stream(ctx context.Context, filter model.Filter, fn func(model.Entry) error) error
+	Redact(ctx context.Context, authorID, pseudonym, marker string) (int64, error)
+}
+
+type AuditRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewAuditRepo(db *pgxpool.Pool) *AuditRepo {
+	return &AuditRepo{db: db}
+}
+
+// Record добавляет запись в журнал. q — транзакция, в которой выполняется
+// само изменение, чтобы запись и изменение фиксировались атомарно.
+// Запись принадлежит тенанту комментария, а не запроса: фоновые задачи работают без тенанта
+func (r *AuditRepo) Record(ctx context.Context, q db.Querier, entry *model.Entry) error {
+	entry.ID = uuid.New().String()
+	entry.CreatedAt = time.Now()
+
+	_, err := q.Exec(ctx, `
+		INSERT INTO comment_audit_log
+			(id, comment_id, thread_id, actor_id, action, before, after, request_id, ip, user_agent, created_at, tenant_id)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,
+		        COALESCE((SELECT tenant_id FROM comments WHERE id = $2), $12))
+	`,
+		entry.ID,
+		entry.CommentID,
+		entry.ThreadID,
+		entry.ActorID,
+		entry.Action,
+		entry.Before,
+		entry.After,
+		entry.RequestID,
+		entry.IP,
+		entry.UserAgent,
+		entry.CreatedAt,
+		tenant.IDOrDefault(ctx),
+	)
+	return err
+}
+
+// List возвращает страницу журнала, новые записи первыми
+func (r *AuditRepo) List(ctx context.Context, filter model.Filter) ([]model.Entry, error) {
+	entries := []model.Entry{}
+	err := r.Stream(ctx, filter, func(e model.Entry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	return entries, err
+}
+
+// Stream построчно передаёт записи журнала тенанта в fn, не загружая выборку в память целиком
+func (r *AuditRepo) Stream(ctx context.Context, filter model.Filter, fn func(model.Entry) error) error {
+	query := `
+		SELECT id, comment_id, thread_id, actor_id, action, before, after,
+		       request_id, ip, user_agent, created_at
+		FROM comment_audit_log
+		WHERE ($1 = '' OR comment_id::text = $1)
+		  AND ($2 = '' OR thread_id::text = $2)
+		  AND ($3 = '' OR actor_id = $3)
+		  AND ($4 = '' OR action = $4)
+		  AND ($5::timestamptz IS NULL OR created_at >= $5)
+		  AND ($6::timestamptz IS NULL OR created_at < $6)
+		  AND ($7 = '' OR before->>'author_id' = $7 OR after->>'author_id' = $7)
+		  AND ($8::text = '' OR tenant_id = $8)
+		ORDER BY created_at DESC, id DESC
+	`
+	args := []any{filter.CommentID, filter.ThreadID, filter.ActorID, string(filter.Action), filter.From, filter.To, filter.SubjectAuthorID, tenant.ID(ctx)}
+	if filter.Limit > 0 {
+		query += ` LIMIT $9 OFFSET $10`
+		args = append(args, filter.Limit, filter.Offset)
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e model.Entry
+		if err := rows.Scan(
+			&e.ID, &e.CommentID, &e.ThreadID, &e.ActorID, &e.Action, &e.Before, &e.After,
+			&e.RequestID, &e.IP, &e.UserAgent, &e.CreatedAt,
+		); err != nil {
+			return err
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Redact обезличивает записи журнала, относящиеся к автору: заменяет его id
+// псевдонимом в actor_id и снимках, текст снимков его комментариев — marker,
+// стирает IP и User-Agent его запросов — в пределах тенанта. Это единственное
+// разрешённое изменение журнала
+func (r *AuditRepo) Redact(ctx context.Context, authorID, pseudonym, marker string) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SET LOCAL comments.audit_redaction = 'on'`); err != nil {
+		return 0, err
+	}
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE comment_audit_log
+		SET actor_id = CASE WHEN actor_id = $1 THEN $2 ELSE actor_id END,
+		    ip = CASE WHEN actor_id = $1 THEN '' ELSE ip END,
+		    user_agent = CASE WHEN actor_id = $1 THEN '' ELSE user_agent END,
+		    before = CASE WHEN before->>'author_id' = $1
+		                  THEN before || jsonb_build_object('author_id', $2::text, 'content', $3::text)
+		                  ELSE before END,
+		    after = CASE WHEN after->>'author_id' = $1
+		                 THEN after || jsonb_build_object('author_id', $2::text, 'content', $3::text)
+		                 ELSE after END
+		WHERE (actor_id = $1 OR before->>'author_id' = $1 OR after->>'author_id' = $1)
+		  AND ($4::text = '' OR tenant_id = $4)
+	`, authorID, pseudonym, marker, tenant.ID(ctx))
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+
+	"github.com/pksep/comments/internal/modules/audit/model"
+	"github.com/pksep/comments/internal/modules/audit/repository"
+)
+
+type AuditService struct {
+	repo repository.AuditRepoInterface
+}
+
+func NewAuditService(repo repository.AuditRepoInterface) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+// List возвращает страницу журнала аудита
+func (s *AuditService) List(ctx context.Context, filter model.Filter) ([]model.Entry, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// Export построчно отдаёт все записи, подходящие под фильтр, без пагинации
+func (s *AuditService) Export(ctx context.Context, filter model.Filter, fn func(model.Entry) error) error {
+	filter.Limit, filter.Offset = 0, 0
+	return s.repo.Stream(ctx, filter, fn)
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pksep/comments/internal/modules/audit/model"
+	"github.com/pksep/comments/internal/modules/audit/repository"
+)
+
+type streamRepo struct {
+	repository.AuditRepoInterface
+	filter model.Filter
+}
+
+func (r *streamRepo) Stream(ctx context.Context, filter model.Filter, fn func(model.Entry) error) error {
+	r.filter = filter
+	return fn(model.Entry{ID: "e1"})
+}
+
+func TestExportIgnoresPagination(t *testing.T) {
+	repo := &streamRepo{}
+	var got []string
+	err := NewAuditService(repo).Export(context.Background(), model.Filter{ActorID: "u1", Limit: 50, Offset: 100}, func(e model.Entry) error {
+		got = append(got, e.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.filter.Limit != 0 || repo.filter.Offset != 0 || repo.filter.ActorID != "u1" {
+		t.Fatalf("filter = %+v, want actor filter without pagination", repo.filter)
+	}
+	if len(got) != 1 {
+		t.Fatalf("entries = %v", got)
+	}
+}
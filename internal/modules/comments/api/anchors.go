@@ -0,0 +1,86 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pksep/comments/internal/modules/comments/api/dto"
+	"github.com/pksep/comments/internal/modules/comments/model"
+)
+
+func (h *CommentHandler) registerAnchorRoutes(rg *gin.RouterGroup) {
+	rg.GET("/comments/by-thread/:threadId/anchored", h.ListAnchored) // ?block_ids=b1,b2&sort=&viewer_id=
+	rg.POST("/comments/anchors/remap", h.RemapAnchors)               // thread_id будет в теле
+}
+
+func (h *CommentHandler) registerAnchorRoutesV2(threads *gin.RouterGroup) {
+	threads.GET("/:id/anchored", h.ListAnchoredV2) // ?block_ids=b1,b2&sort=&viewer_id=
+	threads.POST("/:id/anchors/remap", h.RemapAnchorsV2)
+}
+
+func (h *CommentHandler) ListAnchored(c *gin.Context) {
+	comments, err := h.listAnchored(c, c.Param("threadId"))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, comments)
+}
+
+func (h *CommentHandler) ListAnchoredV2(c *gin.Context) {
+	comments, err := h.listAnchored(c, c.Param("id"))
+	if err != nil {
+		respondError(c, err, http.StatusBadRequest)
+		return
+	}
+	c.JSON(http.StatusOK, comments)
+}
+
+func (h *CommentHandler) listAnchored(c *gin.Context, threadID string) ([]model.Comment, error) {
+	var query dto.AnchoredQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		return nil, err
+	}
+
+	blockIDs := []string{}
+	for _, id := range strings.Split(query.BlockIDs, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			blockIDs = append(blockIDs, id)
+		}
+	}
+	return h.service.ListAnchored(c, threadID, blockIDs, model.ThreadOptions{
+		Sort:     model.ReplySort(query.Sort),
+		ViewerID: query.ViewerID,
+	})
+}
+
+func (h *CommentHandler) RemapAnchors(c *gin.Context) {
+	var body dto.RemapThreadAnchorsDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.service.RemapAnchors(c, body.ThreadID, model.AnchorRemap{Blocks: body.Blocks, Anchors: body.Anchors})
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"updated": updated})
+}
+
+func (h *CommentHandler) RemapAnchorsV2(c *gin.Context) {
+	var body dto.RemapAnchorsDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.service.RemapAnchors(c, c.Param("id"), model.AnchorRemap{Blocks: body.Blocks, Anchors: body.Anchors})
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"updated": updated})
+}
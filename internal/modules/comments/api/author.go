@@ -0,0 +1,63 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pksep/comments/internal/modules/comments/api/dto"
+	"github.com/pksep/comments/internal/modules/comments/model"
+)
+
+func (h *CommentHandler) registerAuthorRoutes(rg *gin.RouterGroup) {
+	authors := rg.Group("/authors")
+	{
+		authors.GET("/:id/comments", h.ListByAuthor) // ?status=created,edited&limit=20&offset=0
+	}
+}
+
+func (h *CommentHandler) ListByAuthor(c *gin.Context) {
+	var query dto.AuthorCommentsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	statuses, err := parseStatuses(query.Status)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	items, err := h.service.ListByAuthor(c, model.AuthorFilter{
+		AuthorID: c.Param("id"),
+		Statuses: statuses,
+		Limit:    query.Limit,
+		Offset:   query.Offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// parseStatuses разбирает список статусов через запятую
+func parseStatuses(raw string) ([]model.CommentStatus, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	var statuses []model.CommentStatus
+	for _, s := range strings.Split(raw, ",") {
+		st := model.CommentStatus(strings.TrimSpace(s))
+		switch st {
+		case model.CommentStatusCreated, model.CommentStatusEdited, model.CommentStatusDeleted,
+			model.CommentStatusPending, model.CommentStatusHidden, model.CommentStatusScheduled:
+			statuses = append(statuses, st)
+		default:
+			return nil, fmt.Errorf("unknown comment status %q", s)
+		}
+	}
+	return statuses, nil
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/comments/repository"
+)
+
+type authorRepo struct {
+	repository.CommentRepoInterface
+	filter *model.AuthorFilter
+}
+
+func (r *authorRepo) ListByAuthor(ctx context.Context, filter model.AuthorFilter) ([]model.AuthorComment, error) {
+	r.filter = &filter
+	return []model.AuthorComment{}, nil
+}
+
+func TestListByAuthor(t *testing.T) {
+	repo := &authorRepo{}
+	r := newTestRouter(repo)
+
+	if w := do(r, http.MethodGet, "/api/authors/alice/comments", ""); w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	if f := repo.filter; f.AuthorID != "alice" || f.Statuses != nil || f.Limit != 20 || f.Offset != 0 {
+		t.Fatalf("default filter = %+v", f)
+	}
+
+	if w := do(r, http.MethodGet, "/api/authors/alice/comments?status=pending,+hidden&limit=5&offset=10", ""); w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	want := []model.CommentStatus{model.CommentStatusPending, model.CommentStatusHidden}
+	if f := repo.filter; !slices.Equal(f.Statuses, want) || f.Limit != 5 || f.Offset != 10 {
+		t.Fatalf("filter = %+v", f)
+	}
+}
+
+func TestListByAuthorRejectsBadQuery(t *testing.T) {
+	repo := &authorRepo{}
+	r := newTestRouter(repo)
+	for _, query := range []string{"status=archived", "limit=0", "limit=101", "offset=-1"} {
+		if w := do(r, http.MethodGet, "/api/authors/alice/comments?"+query, ""); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: %d, want 400", query, w.Code)
+		}
+	}
+	if repo.filter != nil {
+		t.Fatal("invalid query reached the repository")
+	}
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+// lastModifiedDelay — сколько должно пройти с изменения треда, прежде чем отдавать
+// Last-Modified. Заголовок точен до секунды: пока секунда изменения не закончилась,
+// в неё могут попасть новые изменения, и If-Modified-Since вернул бы 304 на изменённый
+// тред. Запас покрывает и расхождение часов сервиса и базы
+const lastModifiedDelay = 2 * time.Second
+
+// threadNotModified выставляет ETag и Last-Modified чтения треда по его версии и
+// отвечает 304, если версия клиента актуальна. Комментарии треда при этом не читаются.
+// true — ответ уже отправлен
+func (h *CommentHandler) threadNotModified(c *gin.Context, threadID string, opts model.ThreadOptions) (bool, error) {
+	version, err := h.service.ThreadVersion(c, threadID)
+	if err != nil {
+		return false, err
+	}
+	// Тред без опубликованных комментариев отдаётся как есть: для него нет представления
+	if version == nil || version.Count == 0 {
+		return false, nil
+	}
+
+	etag := threadETag(c, version, opts)
+	c.Header("ETag", etag)
+	lastModified := version.UpdatedAt.Truncate(time.Second)
+	stable := time.Since(version.UpdatedAt) >= lastModifiedDelay
+	if stable {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	// If-None-Match важнее If-Modified-Since (RFC 9110, 13.1.3)
+	notModified := false
+	if header := c.GetHeader("If-None-Match"); header != "" {
+		notModified = etagMatches(header, etag)
+	} else if since, err := http.ParseTime(c.GetHeader("If-Modified-Since")); err == nil && stable {
+		notModified = !lastModified.After(since)
+	}
+	if notModified {
+		c.Status(http.StatusNotModified)
+	}
+	return notModified, nil
+}
+
+// threadETag — слабый ETag чтения треда: версия треда, тенант и параметры представления
+func threadETag(c *gin.Context, v *model.ThreadVersion, opts model.ThreadOptions) string {
+	sum := sha256.Sum256(fmt.Appendf(nil, "%s\x00%s\x00%d\x00%d\x00%s\x00%s",
+		tenant.ID(c), v.ThreadID, v.UpdatedAt.UnixMicro(), v.Count, opts.Sort, opts.ViewerID))
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches сравнивает список из If-None-Match с etag слабым сравнением
+func etagMatches(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
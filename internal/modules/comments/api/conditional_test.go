@@ -0,0 +1,122 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+func seedThread(repo *memRepo) {
+	thread := "t1"
+	repo.Create(context.Background(), &model.Comment{AuthorID: "alice", Content: "hello", ThreadID: &thread})
+}
+
+func TestThreadIfNoneMatch(t *testing.T) {
+	repo := newMemRepo()
+	seedThread(repo)
+	r := newTestRouter(repo)
+
+	w := do(r, http.MethodGet, "/api/comments/by-thread/t1", "")
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" {
+		t.Fatalf("first read: %d, ETag %q", w.Code, etag)
+	}
+
+	reads := repo.threadReads
+	w = do(r, http.MethodGet, "/api/comments/by-thread/t1", "", "If-None-Match", `"other", `+etag)
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Fatalf("revalidation: %d %s, want 304", w.Code, w.Body)
+	}
+	if repo.threadReads != reads {
+		t.Fatal("304 read the thread comments")
+	}
+
+	// Другой порядок ответов — другое представление
+	if w := do(r, http.MethodGet, "/api/comments/by-thread/t1?sort=top", "", "If-None-Match", etag); w.Code != http.StatusOK {
+		t.Fatalf("other sort: %d, want 200", w.Code)
+	}
+
+	seedThread(repo)
+	if w := do(r, http.MethodGet, "/api/comments/by-thread/t1", "", "If-None-Match", etag); w.Code != http.StatusOK {
+		t.Fatalf("after a new comment: %d, want 200", w.Code)
+	}
+}
+
+func TestThreadIfModifiedSince(t *testing.T) {
+	repo := newMemRepo()
+	seedThread(repo)
+	r := newTestRouter(repo)
+
+	w := do(r, http.MethodGet, "/api/comments/by-thread/t1", "")
+	lastModified := w.Header().Get("Last-Modified")
+	if lastModified == "" {
+		t.Fatal("no Last-Modified for a thread changed an hour ago")
+	}
+	if w := do(r, http.MethodGet, "/api/comments/by-thread/t1", "", "If-Modified-Since", lastModified); w.Code != http.StatusNotModified {
+		t.Fatalf("If-Modified-Since: %d, want 304", w.Code)
+	}
+	// If-None-Match важнее: несовпавший ETag отдаёт тред, даже если дата свежая
+	if w := do(r, http.MethodGet, "/api/comments/by-thread/t1", "", "If-None-Match", `"stale"`, "If-Modified-Since", lastModified); w.Code != http.StatusOK {
+		t.Fatalf("stale ETag: %d, want 200", w.Code)
+	}
+
+	// Изменение в текущей секунде: дата ещё неточна, поэтому не отдаётся и не сравнивается
+	repo.updatedAt = time.Now()
+	w = do(r, http.MethodGet, "/api/comments/by-thread/t1", "", "If-Modified-Since", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	if w.Code != http.StatusOK || w.Header().Get("Last-Modified") != "" {
+		t.Fatalf("fresh change: %d, Last-Modified %q", w.Code, w.Header().Get("Last-Modified"))
+	}
+}
+
+func TestThreadETagVaries(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	v := &model.ThreadVersion{ThreadID: "t1", UpdatedAt: time.Now(), Count: 1}
+	etag := func(tenantID string, opts model.ThreadOptions) string {
+		// Как в app.go: gin.Context отдаёт значения из контекста запроса
+		c, e := gin.CreateTestContext(httptest.NewRecorder())
+		e.ContextWithFallback = true
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		if tenantID != "" {
+			c.Request = c.Request.WithContext(tenant.WithTenant(c.Request.Context(), tenant.Tenant{ID: tenantID}))
+		}
+		return threadETag(c, v, opts)
+	}
+
+	base := etag("", model.ThreadOptions{})
+	for name, other := range map[string]string{
+		"tenant": etag("acme", model.ThreadOptions{}),
+		"sort":   etag("", model.ThreadOptions{Sort: model.ReplySort("top")}),
+		"viewer": etag("", model.ThreadOptions{ViewerID: "bob"}),
+	} {
+		if other == base {
+			t.Errorf("%s does not change the ETag", name)
+		}
+	}
+	if etag("", model.ThreadOptions{}) != base {
+		t.Error("ETag is not stable")
+	}
+}
+
+func TestETagMatches(t *testing.T) {
+	cases := []struct {
+		header string
+		want   bool
+	}{
+		{`W/"abc"`, true},
+		{`"abc"`, true},
+		{`"x", W/"abc"`, true},
+		{`*`, true},
+		{`W/"abd"`, false},
+	}
+	for _, tc := range cases {
+		if got := etagMatches(tc.header, `W/"abc"`); got != tc.want {
+			t.Errorf("etagMatches(%q) = %v, want %v", tc.header, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package dto
+
+import "github.com/pksep/comments/internal/modules/comments/model"
+
+// AnchoredQuery — параметры чтения привязанных комментариев; block_ids — через запятую
+type AnchoredQuery struct {
+	BlockIDs string `form:"block_ids"`
+	Sort     string `form:"sort,default=created" binding:"oneof=created top controversial"`
+	ViewerID string `form:"viewer_id"`
+}
+
+// RemapAnchorsDTO — тело POST /v2/threads/:id/anchors/remap
+type RemapAnchorsDTO struct {
+	Blocks  []model.BlockRemap   `json:"blocks" binding:"max=1000,dive"`
+	Anchors []model.AnchorUpdate `json:"anchors" binding:"max=1000,dive"`
+}
+
+// RemapThreadAnchorsDTO — тело POST /comments/anchors/remap
+type RemapThreadAnchorsDTO struct {
+	ThreadID string `json:"thread_id" binding:"required"`
+	RemapAnchorsDTO
+}
@@ -0,0 +1,8 @@
+package dto
+
+type AuthorCommentsQuery struct {
+	// Статусы через запятую: created,edited,deleted,pending,hidden
+	Status string `form:"status"`
+	Limit  int    `form:"limit,default=20" binding:"min=1,max=100"`
+	Offset int    `form:"offset" binding:"min=0"`
+}
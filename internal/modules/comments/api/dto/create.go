@@ -1,8 +1,17 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+)
+
 type CreateCommentDTO struct {
-	AuthorID        string  `json:"author_id" binding:"required"`
-	Content         string  `json:"content" binding:"required"`
-	ThreadID         *string `json:"thread_id,omitempty"`
-	AnswerCommentID  *string `json:"answer_comment_id,omitempty"`
+	AuthorID        string        `json:"author_id" binding:"required"`
+	Content         string        `json:"content" binding:"required"`
+	ThreadID        *string       `json:"thread_id,omitempty"`
+	AnswerCommentID *string       `json:"answer_comment_id,omitempty"`
+	Anchor          *model.Anchor `json:"anchor,omitempty"`
+	// Время отложенной публикации; прошедшее время — публикация сразу
+	PublishAt *time.Time `json:"publish_at,omitempty"`
 }
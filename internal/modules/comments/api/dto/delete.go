@@ -3,4 +3,6 @@ package dto
 type DeleteCommentDTO struct {
 	ID       string `json:"id" binding:"required"`
 	AuthorID string `json:"author_id" binding:"required"`
+	// Ожидаемая версия комментария; альтернатива заголовку If-Match
+	ExpectedVersion *int `json:"expected_version,omitempty"`
 }
@@ -0,0 +1,7 @@
+package dto
+
+type ExportThreadQuery struct {
+	Format string `form:"format,default=json" binding:"oneof=json csv html md"`
+	// IncludeHidden добавляет скрытые и ожидающие модерации комментарии
+	IncludeHidden bool `form:"include_hidden"`
+}
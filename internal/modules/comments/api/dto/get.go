@@ -0,0 +1,7 @@
+package dto
+
+// CommentContextQuery — сколько родителей и соседей вернуть вместе с комментарием
+type CommentContextQuery struct {
+	Ancestors int `form:"ancestors" binding:"min=0,max=50"`
+	Siblings  int `form:"siblings" binding:"min=0,max=50"`
+}
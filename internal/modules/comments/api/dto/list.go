@@ -0,0 +1,10 @@
+package dto
+
+type ListQuery struct {
+	IDs        string `form:"ids"`
+	Sort       string `form:"sort,default=created" binding:"oneof=created activity replies top controversial"`
+	Preview    string `form:"preview,default=latest" binding:"oneof=latest earliest"`
+	ReplyLimit int    `form:"reply_limit,default=3" binding:"min=0,max=50"`
+	Resolved   *bool  `form:"resolved"`
+	ViewerID   string `form:"viewer_id"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+type ReportCommentDTO struct {
+	CommentID  string `json:"comment_id" binding:"required"`
+	ReporterID string `json:"reporter_id" binding:"required"`
+	Reason     string `json:"reason" binding:"required"`
+}
+
+type ApproveCommentDTO struct {
+	ID          string  `json:"id" binding:"required"`
+	ModeratorID string  `json:"moderator_id" binding:"required"`
+	Reason      *string `json:"reason,omitempty"`
+}
+
+type ModerateCommentDTO struct {
+	ID          string `json:"id" binding:"required"`
+	ModeratorID string `json:"moderator_id" binding:"required"`
+	Reason      string `json:"reason" binding:"required"`
+}
+
+type ModerationQueueQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending hidden reported"`
+	Limit  int    `form:"limit,default=50" binding:"min=1,max=200"`
+	Offset int    `form:"offset" binding:"min=0"`
+}
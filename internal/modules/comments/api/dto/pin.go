@@ -0,0 +1,12 @@
+package dto
+
+// PinCommentDTO — тело POST /comments/pin и /comments/unpin
+type PinCommentDTO struct {
+	ID      string `json:"id" binding:"required"`
+	ActorID string `json:"actor_id" binding:"required"`
+}
+
+// ActorQuery — параметры v2-маршрутов, которым нужен только исполнитель действия
+type ActorQuery struct {
+	ActorID string `form:"actor_id" binding:"required"`
+}
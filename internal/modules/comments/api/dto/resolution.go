@@ -0,0 +1,19 @@
+package dto
+
+// AcceptAnswerDTO — тело POST /comments/accept
+type AcceptAnswerDTO struct {
+	ID      string `json:"id" binding:"required"`
+	ActorID string `json:"actor_id" binding:"required"`
+}
+
+// UnacceptAnswerDTO — тело POST /comments/unaccept
+type UnacceptAnswerDTO struct {
+	ThreadID string `json:"thread_id" binding:"required"`
+	ActorID  string `json:"actor_id" binding:"required"`
+}
+
+// AcceptThreadAnswerDTO — тело PUT /v2/threads/:id/accepted
+type AcceptThreadAnswerDTO struct {
+	CommentID string `json:"comment_id" binding:"required"`
+	ActorID   string `json:"actor_id" binding:"required"`
+}
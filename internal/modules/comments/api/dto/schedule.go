@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+// RescheduleCommentDTO — тело POST /comments/reschedule
+type RescheduleCommentDTO struct {
+	ID              string    `json:"id" binding:"required"`
+	AuthorID        string    `json:"author_id" binding:"required"`
+	PublishAt       time.Time `json:"publish_at" binding:"required"`
+	ExpectedVersion *int      `json:"expected_version,omitempty"`
+}
+
+// PutScheduleDTO — тело PUT /v2/comments/:id/schedule
+type PutScheduleDTO struct {
+	AuthorID        string    `json:"author_id" binding:"required"`
+	PublishAt       time.Time `json:"publish_at" binding:"required"`
+	ExpectedVersion *int      `json:"expected_version,omitempty"`
+}
@@ -4,4 +4,6 @@ type UpdateCommentDTO struct {
 	ID       string `json:"id" binding:"required"`
 	Content  string `json:"content" binding:"required"`
 	AuthorID string `json:"author_id" binding:"required"`
+	// Ожидаемая версия комментария; альтернатива заголовку If-Match
+	ExpectedVersion *int `json:"expected_version,omitempty"`
 }
@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+)
+
+// CreateThreadCommentDTO — тело POST /v2/threads/:id/comments, тред берётся из пути
+type CreateThreadCommentDTO struct {
+	AuthorID        string        `json:"author_id" binding:"required"`
+	Content         string        `json:"content" binding:"required"`
+	AnswerCommentID *string       `json:"answer_comment_id,omitempty"`
+	Anchor          *model.Anchor `json:"anchor,omitempty"`
+	PublishAt       *time.Time    `json:"publish_at,omitempty"`
+}
+
+// PatchCommentDTO — тело PATCH /v2/comments/:id
+type PatchCommentDTO struct {
+	AuthorID        string `json:"author_id" binding:"required"`
+	Content         string `json:"content" binding:"required"`
+	ExpectedVersion *int   `json:"expected_version,omitempty"`
+}
+
+// DeleteCommentQuery — параметры DELETE /v2/comments/:id
+type DeleteCommentQuery struct {
+	AuthorID        string `form:"author_id" binding:"required"`
+	ExpectedVersion *int   `form:"expected_version"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+// VoteCommentDTO — тело POST /comments/vote; value 0 снимает голос
+type VoteCommentDTO struct {
+	ID      string `json:"id" binding:"required"`
+	VoterID string `json:"voter_id" binding:"required"`
+	Value   *int   `json:"value" binding:"required,oneof=-1 0 1"`
+}
+
+// PutVoteDTO — тело PUT /v2/comments/:id/vote
+type PutVoteDTO struct {
+	VoterID string `json:"voter_id" binding:"required"`
+	Value   int    `json:"value" binding:"required,oneof=-1 1"`
+}
+
+// ThreadQuery — параметры чтения треда
+type ThreadQuery struct {
+	Sort     string `form:"sort,default=created" binding:"oneof=created top controversial"`
+	ViewerID string `form:"viewer_id"`
+}
+
+// VoterQuery — параметры DELETE /v2/comments/:id/vote
+type VoterQuery struct {
+	VoterID string `form:"voter_id" binding:"required"`
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pksep/comments/internal/modules/comments/api/dto"
+	"github.com/pksep/comments/internal/modules/comments/export"
+)
+
+func (h *CommentHandler) registerExportRoutes(rg *gin.RouterGroup) {
+	threads := rg.Group("/threads")
+	{
+		threads.GET("/:id/export", h.ExportThread) // ?format=json|csv|html|md&include_hidden=true
+	}
+}
+
+func (h *CommentHandler) ExportThread(c *gin.Context) {
+	var query dto.ExportThreadQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	threadID := c.Param("id")
+	format := export.Format(query.Format)
+	c.Header("Content-Type", format.ContentType())
+	if format != export.FormatHTML {
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="thread-%s.%s"`, threadID, format))
+	}
+	c.Status(http.StatusOK)
+
+	err := h.service.ExportThread(c, threadID, format, query.IncludeHidden, c.Writer)
+	if err == nil {
+		return
+	}
+	if c.Writer.Written() {
+		// Заголовки уже отправлены, поэтому прерываем поток
+		_ = c.Error(err)
+		c.Abort()
+		return
+	}
+	c.Writer.Header().Del("Content-Type")
+	c.Writer.Header().Del("Content-Disposition")
+	c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+}
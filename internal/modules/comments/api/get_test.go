@@ -0,0 +1,52 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+)
+
+func TestGetCommentWithContext(t *testing.T) {
+	repo := newMemRepo()
+	r := newTestRouter(repo)
+	do(r, http.MethodPost, "/api/comments/create", `{"author_id":"a","content":"root","thread_id":"t1"}`)
+	do(r, http.MethodPost, "/api/comments/create", `{"author_id":"b","content":"reply","thread_id":"t1","answer_comment_id":"c1"}`)
+	do(r, http.MethodPost, "/api/comments/create", `{"author_id":"a","content":"nested","thread_id":"t1","answer_comment_id":"c2"}`)
+
+	w := do(r, http.MethodGet, "/api/comments/c3?ancestors=5", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	if etag := w.Header().Get("ETag"); etag != `"1"` {
+		t.Fatalf("ETag = %q", etag)
+	}
+	var got model.CommentWithContext
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != "c3" || len(got.Ancestors) != 2 || got.Ancestors[0].ID != "c1" || got.Ancestors[1].ID != "c2" {
+		t.Fatalf("comment %s with ancestors %+v, want c3 with c1, c2", got.ID, got.Ancestors)
+	}
+
+	w = do(r, http.MethodGet, "/api/comments/c3?ancestors=1", "")
+	json.Unmarshal(w.Body.Bytes(), &got)
+	if len(got.Ancestors) != 1 || got.Ancestors[0].ID != "c2" {
+		t.Fatalf("limited ancestors = %+v, want only the parent", got.Ancestors)
+	}
+}
+
+func TestGetCommentErrors(t *testing.T) {
+	r := newTestRouter(newMemRepo())
+	for path, want := range map[string]int{
+		"/api/comments/missing":              http.StatusNotFound,
+		"/api/comments/missing?ancestors=51": http.StatusBadRequest,
+		"/api/comments/missing?siblings=-1":  http.StatusBadRequest,
+		"/api/v2/comments/missing":           http.StatusNotFound,
+	} {
+		if w := do(r, http.MethodGet, path, ""); w.Code != want {
+			t.Errorf("GET %s: %d, want %d", path, w.Code, want)
+		}
+	}
+}
@@ -12,21 +12,37 @@ import (
 
 type CommentHandler struct {
 	service *comments.CommentService
+	// idempotency обрабатывает заголовок Idempotency-Key на создании комментария
+	idempotency gin.HandlerFunc
+	// v2BasePath — префикс маршрутов v2 для заголовка Location
+	v2BasePath string
 }
 
-func NewCommentHandler(service *comments.CommentService) *CommentHandler {
-	return &CommentHandler{service: service}
+func NewCommentHandler(service *comments.CommentService, idempotency gin.HandlerFunc) *CommentHandler {
+	return &CommentHandler{service: service, idempotency: idempotency}
 }
 
 func (h *CommentHandler) RegisterRoutes(rg *gin.RouterGroup) {
 	comments := rg.Group("/comments")
 	{
-		comments.POST("/create", h.Create)
-		comments.POST("/update", h.Update) // id будет в теле
-		comments.POST("/delete", h.Delete) // id, author_id будет в теле
-		comments.GET("/by-thread/:threadId", h.Get)
-		comments.GET("/list", h.List) // ids[]=id1&ids[]=id2
+		comments.POST("/create", h.idempotency, h.Create) // повторы по Idempotency-Key
+		comments.POST("/update", h.Update)                // id будет в теле
+		comments.POST("/delete", h.Delete)                // id, author_id будет в теле
+		comments.GET("/by-thread/:threadId", h.Get)       // ?sort=created|top|controversial&viewer_id=; If-None-Match, If-Modified-Since
+		comments.GET("/:id", h.GetComment)                // ?ancestors=N&siblings=N
+		comments.GET("/list", h.List)                     // ids=id1,id2&sort=created|activity|replies&preview=latest|earliest&reply_limit=3&resolved=true|false&viewer_id=
+		comments.POST("/report", h.Report)
+		comments.POST("/pin", h.Pin)               // только владелец треда
+		comments.POST("/unpin", h.Unpin)           // только владелец треда
+		comments.POST("/vote", h.Vote)             // value: 1, -1 или 0 — снять голос
+		comments.POST("/accept", h.Accept)         // только владелец треда
+		comments.POST("/unaccept", h.Unaccept)     // thread_id, actor_id будут в теле
+		comments.POST("/reschedule", h.Reschedule) // только автор запланированного комментария
 	}
+
+	h.registerAuthorRoutes(rg)
+	h.registerExportRoutes(rg)
+	h.registerAnchorRoutes(rg)
 }
 
 func (h *CommentHandler) Create(c *gin.Context) {
@@ -41,11 +57,14 @@ func (h *CommentHandler) Create(c *gin.Context) {
 		Content:         body.Content,
 		ThreadID:        body.ThreadID,
 		AnswerCommentID: body.AnswerCommentID,
+		Anchor:          body.Anchor,
+		PublishAt:       body.PublishAt,
 	})
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
 		return
 	}
+	setETag(c, created.Version)
 	c.JSON(http.StatusCreated, created)
 }
 
@@ -56,11 +75,18 @@ func (h *CommentHandler) Update(c *gin.Context) {
 		return
 	}
 
-	updated, err := h.service.UpdateContent(c, body.ID, body.Content, body.AuthorID)
+	expectedVersion, err := expectedVersion(c, body.ExpectedVersion)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+
+	updated, err := h.service.UpdateContent(c, body.ID, body.Content, body.AuthorID, expectedVersion)
+	if err != nil {
+		respondError(c, err, http.StatusBadRequest)
+		return
+	}
+	setETag(c, updated.Version)
 	c.JSON(http.StatusOK, updated)
 }
 
@@ -73,20 +99,46 @@ func (h *CommentHandler) Delete(c *gin.Context) {
 		return
 	}
 
+	expectedVersion, err := expectedVersion(c, body.ExpectedVersion)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Удаляем комментарий
-	deletedComment, err := h.service.Delete(c, body.ID, body.AuthorID)
+	deletedComment, err := h.service.Delete(c, body.ID, body.AuthorID, expectedVersion)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, err, http.StatusInternalServerError)
 		return
 	}
+	setETag(c, deletedComment.Version)
 
 	// Возвращаем удалённый комментарий
 	c.JSON(http.StatusOK, deletedComment)
 }
 
 func (h *CommentHandler) Get(c *gin.Context) {
+	var query dto.ThreadQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	threadId := c.Param("threadId")
-	item, err := h.service.GetByID(c, threadId)
+	opts := model.ThreadOptions{
+		Sort:     model.ReplySort(query.Sort),
+		ViewerID: query.ViewerID,
+	}
+	notModified, err := h.threadNotModified(c, threadId, opts)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if notModified {
+		return
+	}
+
+	item, err := h.service.GetByID(c, threadId, opts)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
@@ -94,15 +146,41 @@ func (h *CommentHandler) Get(c *gin.Context) {
 	c.JSON(http.StatusOK, item)
 }
 
+func (h *CommentHandler) GetComment(c *gin.Context) {
+	var query dto.CommentContextQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := h.service.GetCommentContext(c, c.Param("id"), query.Ancestors, query.Siblings)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	setETag(c, item.Version)
+	c.JSON(http.StatusOK, item)
+}
+
 func (h *CommentHandler) List(c *gin.Context) {
-	idsParam := c.Query("ids")
+	var query dto.ListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	var ids []string
-	if idsParam != "" {
-		ids = strings.Split(idsParam, ",")
+	if query.IDs != "" {
+		ids = strings.Split(query.IDs, ",")
 	}
 
-	// тут limit = 3 для реплаев
-	items, err := h.service.ListWithReplies(c, ids, 3)
+	items, err := h.service.ListWithReplies(c, ids, model.ListOptions{
+		Sort:       model.ThreadSort(query.Sort),
+		Preview:    model.ReplyPreview(query.Preview),
+		ReplyLimit: query.ReplyLimit,
+		Resolved:   query.Resolved,
+		ViewerID:   query.ViewerID,
+	})
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pksep/comments/internal/modules/comments/api/dto"
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/comments/moderation"
+	"github.com/pksep/comments/internal/modules/comments/repository"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+// RegisterModerationRoutes регистрирует инструменты модератора в группе rg
+// (/api/moderation), которая должна требовать ключ с правом moderate: moderator_id
+// берётся из тела запроса
+func (h *CommentHandler) RegisterModerationRoutes(rg *gin.RouterGroup) {
+	rg.GET("/queue", h.Queue) // ?status=pending|hidden|reported
+	rg.GET("/by-thread/:threadId", h.GetForModerator)
+	rg.POST("/approve", h.Approve)
+	rg.POST("/hide", h.Hide)
+	rg.POST("/delete", h.ModeratorDelete)
+	rg.POST("/restore", h.Restore)
+}
+
+func (h *CommentHandler) Report(c *gin.Context) {
+	var body dto.ReportCommentDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.service.Report(c, body.CommentID, body.ReporterID, body.Reason)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, report)
+}
+
+func (h *CommentHandler) Queue(c *gin.Context) {
+	var query dto.ModerationQueueQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	items, err := h.service.ModerationQueue(c, model.QueueFilter(query.Status), query.Limit, query.Offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+func (h *CommentHandler) GetForModerator(c *gin.Context) {
+	item, err := h.service.GetThreadForModerator(c, c.Param("threadId"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+func (h *CommentHandler) Approve(c *gin.Context) {
+	var body dto.ApproveCommentDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	approved, err := h.service.Approve(c, body.ID, body.ModeratorID, body.Reason)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, approved)
+}
+
+func (h *CommentHandler) Hide(c *gin.Context) {
+	var body dto.ModerateCommentDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hidden, err := h.service.Hide(c, body.ID, body.ModeratorID, body.Reason)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, hidden)
+}
+
+func (h *CommentHandler) ModeratorDelete(c *gin.Context) {
+	var body dto.ModerateCommentDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	deleted, err := h.service.ModeratorDelete(c, body.ID, body.ModeratorID, body.Reason)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, deleted)
+}
+
+func (h *CommentHandler) Restore(c *gin.Context) {
+	var body dto.ModerateCommentDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	restored, err := h.service.Restore(c, body.ID, body.ModeratorID, body.Reason)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, restored)
+}
+
+// errorStatus подбирает HTTP-статус для известных ошибок сервиса
+func errorStatus(err error, fallback int) int {
+	var rejected *moderation.RejectedError
+	var conflict *repository.VersionConflictError
+	switch {
+	case errors.As(err, &rejected):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, repository.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrNotReply), errors.Is(err, repository.ErrInvalidParent),
+		errors.Is(err, model.ErrInvalidAnchor),
+		errors.Is(err, model.ErrInvalidPublishAt), errors.Is(err, repository.ErrNotScheduled),
+		errors.Is(err, model.ErrContentTooLong):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, repository.ErrForbidden), errors.Is(err, tenant.ErrFeatureDisabled):
+		return http.StatusForbidden
+	case errors.Is(err, repository.ErrAlreadyReported), errors.Is(err, repository.ErrPinLimit):
+		return http.StatusConflict
+	case errors.As(err, &conflict):
+		return http.StatusConflict
+	default:
+		return fallback
+	}
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/comments/moderation"
+	"github.com/pksep/comments/internal/modules/comments/repository"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+func TestErrorStatus(t *testing.T) {
+	cases := []struct {
+		err  error
+		want int
+	}{
+		{&moderation.RejectedError{Reason: "spam"}, http.StatusUnprocessableEntity},
+		{fmt.Errorf("thread: %w", repository.ErrNotFound), http.StatusNotFound},
+		{fmt.Errorf("%w: parent", repository.ErrInvalidParent), http.StatusUnprocessableEntity},
+		{repository.ErrNotReply, http.StatusUnprocessableEntity},
+		{model.ErrContentTooLong, http.StatusUnprocessableEntity},
+		{tenant.ErrFeatureDisabled, http.StatusForbidden},
+		{repository.ErrForbidden, http.StatusForbidden},
+		{repository.ErrAlreadyReported, http.StatusConflict},
+		{repository.ErrPinLimit, http.StatusConflict},
+		{&repository.VersionConflictError{Current: 3}, http.StatusConflict},
+		{errors.New("boom"), http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		if got := errorStatus(tc.err, http.StatusInternalServerError); got != tc.want {
+			t.Errorf("errorStatus(%v) = %d, want %d", tc.err, got, tc.want)
+		}
+	}
+}
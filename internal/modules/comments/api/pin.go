@@ -0,0 +1,68 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pksep/comments/internal/modules/comments/api/dto"
+	"github.com/pksep/comments/internal/modules/comments/model"
+)
+
+// pinAction — закрепление или снятие закрепления
+type pinAction func(c *gin.Context, id, actorID string) (*model.Comment, error)
+
+func (h *CommentHandler) Pin(c *gin.Context) {
+	h.pinV1(c, func(c *gin.Context, id, actorID string) (*model.Comment, error) {
+		return h.service.Pin(c, id, actorID)
+	})
+}
+
+func (h *CommentHandler) Unpin(c *gin.Context) {
+	h.pinV1(c, func(c *gin.Context, id, actorID string) (*model.Comment, error) {
+		return h.service.Unpin(c, id, actorID)
+	})
+}
+
+func (h *CommentHandler) pinV1(c *gin.Context, action pinAction) {
+	var body dto.PinCommentDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, err := action(c, body.ID, body.ActorID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+	setETag(c, comment.Version)
+	c.JSON(http.StatusOK, comment)
+}
+
+func (h *CommentHandler) PinV2(c *gin.Context) {
+	h.pinV2(c, func(c *gin.Context, id, actorID string) (*model.Comment, error) {
+		return h.service.Pin(c, id, actorID)
+	})
+}
+
+func (h *CommentHandler) UnpinV2(c *gin.Context) {
+	h.pinV2(c, func(c *gin.Context, id, actorID string) (*model.Comment, error) {
+		return h.service.Unpin(c, id, actorID)
+	})
+}
+
+func (h *CommentHandler) pinV2(c *gin.Context, action pinAction) {
+	var query dto.ActorQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, err := action(c, c.Param("id"), query.ActorID)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+	setETag(c, comment.Version)
+	c.JSON(http.StatusOK, comment)
+}
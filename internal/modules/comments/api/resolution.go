@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pksep/comments/internal/modules/comments/api/dto"
+)
+
+func (h *CommentHandler) Accept(c *gin.Context) {
+	var body dto.AcceptAnswerDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.Accept(c, body.ID, body.ActorID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *CommentHandler) Unaccept(c *gin.Context) {
+	var body dto.UnacceptAnswerDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.Unaccept(c, body.ThreadID, body.ActorID)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *CommentHandler) AcceptV2(c *gin.Context) {
+	var body dto.AcceptThreadAnswerDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, err := h.service.GetComment(c, body.CommentID)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+	if comment.ThreadID == nil || *comment.ThreadID != c.Param("id") {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "comment does not belong to this thread"})
+		return
+	}
+
+	res, err := h.service.Accept(c, body.CommentID, body.ActorID)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *CommentHandler) UnacceptV2(c *gin.Context) {
+	var query dto.ActorQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.Unaccept(c, c.Param("id"), query.ActorID)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pksep/comments/internal/modules/comments/api/dto"
+)
+
+// Отмена запланированного комментария — обычное удаление автором
+
+func (h *CommentHandler) Reschedule(c *gin.Context) {
+	var body dto.RescheduleCommentDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	expectedVersion, err := expectedVersion(c, body.ExpectedVersion)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.service.Reschedule(c, body.ID, body.AuthorID, body.PublishAt, expectedVersion)
+	if err != nil {
+		respondError(c, err, http.StatusBadRequest)
+		return
+	}
+	setETag(c, updated.Version)
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *CommentHandler) RescheduleV2(c *gin.Context) {
+	var body dto.PutScheduleDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	expectedVersion, err := expectedVersion(c, body.ExpectedVersion)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.service.Reschedule(c, c.Param("id"), body.AuthorID, body.PublishAt, expectedVersion)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+	setETag(c, updated.Version)
+	c.JSON(http.StatusOK, updated)
+}
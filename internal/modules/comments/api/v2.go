@@ -0,0 +1,153 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pksep/comments/internal/modules/comments/api/dto"
+	"github.com/pksep/comments/internal/modules/comments/model"
+)
+
+// RegisterRoutesV2 регистрирует ресурсные маршруты API v2. Они работают
+// через тот же CommentService, что и POST-маршруты v1
+func (h *CommentHandler) RegisterRoutesV2(rg *gin.RouterGroup) {
+	h.v2BasePath = rg.BasePath()
+
+	threads := rg.Group("/threads")
+	{
+		threads.GET("/:id/comments", h.GetThreadV2) // ?sort=created|top|controversial&viewer_id=; If-None-Match, If-Modified-Since
+		threads.POST("/:id/comments", h.idempotency, h.CreateV2)
+		threads.PUT("/:id/accepted", h.AcceptV2)      // comment_id, actor_id будут в теле
+		threads.DELETE("/:id/accepted", h.UnacceptV2) // ?actor_id=
+		h.registerAnchorRoutesV2(threads)
+	}
+
+	comments := rg.Group("/comments")
+	{
+		comments.GET("/:id", h.GetCommentV2) // ?ancestors=N&siblings=N
+		comments.PATCH("/:id", h.UpdateV2)
+		comments.DELETE("/:id", h.DeleteV2)
+		comments.PUT("/:id/pin", h.PinV2)      // ?actor_id=
+		comments.DELETE("/:id/pin", h.UnpinV2) // ?actor_id=
+		comments.PUT("/:id/vote", h.VoteV2)
+		comments.DELETE("/:id/vote", h.UnvoteV2) // ?voter_id=
+		comments.PUT("/:id/schedule", h.RescheduleV2)
+	}
+}
+
+func (h *CommentHandler) GetThreadV2(c *gin.Context) {
+	var query dto.ThreadQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	threadID := c.Param("id")
+	opts := model.ThreadOptions{
+		Sort:     model.ReplySort(query.Sort),
+		ViewerID: query.ViewerID,
+	}
+	notModified, err := h.threadNotModified(c, threadID, opts)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+	if notModified {
+		return
+	}
+
+	thread, err := h.service.GetByID(c, threadID, opts)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+	if thread == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "thread not found"})
+		return
+	}
+	c.JSON(http.StatusOK, thread)
+}
+
+func (h *CommentHandler) CreateV2(c *gin.Context) {
+	var body dto.CreateThreadCommentDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	threadID := c.Param("id")
+	created, err := h.service.Create(c, model.Comment{
+		AuthorID:        body.AuthorID,
+		Content:         body.Content,
+		ThreadID:        &threadID,
+		AnswerCommentID: body.AnswerCommentID,
+		Anchor:          body.Anchor,
+		PublishAt:       body.PublishAt,
+	})
+	if err != nil {
+		respondError(c, err, http.StatusBadRequest)
+		return
+	}
+
+	c.Header("Location", h.v2BasePath+"/comments/"+created.ID)
+	setETag(c, created.Version)
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *CommentHandler) GetCommentV2(c *gin.Context) {
+	var query dto.CommentContextQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, err := h.service.GetCommentContext(c, c.Param("id"), query.Ancestors, query.Siblings)
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+	setETag(c, comment.Version)
+	c.JSON(http.StatusOK, comment)
+}
+
+func (h *CommentHandler) UpdateV2(c *gin.Context) {
+	var body dto.PatchCommentDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	expectedVersion, err := expectedVersion(c, body.ExpectedVersion)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.service.UpdateContent(c, c.Param("id"), body.Content, body.AuthorID, expectedVersion)
+	if err != nil {
+		respondError(c, err, http.StatusBadRequest)
+		return
+	}
+	setETag(c, updated.Version)
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *CommentHandler) DeleteV2(c *gin.Context) {
+	var query dto.DeleteCommentQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	expectedVersion, err := expectedVersion(c, query.ExpectedVersion)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := h.service.Delete(c, c.Param("id"), query.AuthorID, expectedVersion); err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,212 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/comments/repository"
+	comments "github.com/pksep/comments/internal/modules/comments/service"
+)
+
+// memRepo — хранилище комментариев в памяти с правилами авторства и версий репозитория
+type memRepo struct {
+	repository.CommentRepoInterface
+	comments map[string]*model.Comment
+	order    []string
+	// versionReads — сколько раз читалась версия треда
+	versionReads int
+	// threadReads — сколько раз читался сам тред
+	threadReads int
+	updatedAt   time.Time
+}
+
+func newMemRepo() *memRepo {
+	return &memRepo{comments: map[string]*model.Comment{}, updatedAt: time.Now().Add(-time.Hour)}
+}
+
+func (r *memRepo) Create(ctx context.Context, c *model.Comment) (*model.Comment, error) {
+	c.ID = fmt.Sprintf("c%d", len(r.order)+1)
+	c.Version = 1
+	if c.Status == "" {
+		c.Status = model.CommentStatusCreated
+	}
+	saved := *c
+	r.comments[c.ID] = &saved
+	r.order = append(r.order, c.ID)
+	r.updatedAt = r.updatedAt.Add(time.Second)
+	return c, nil
+}
+
+func (r *memRepo) thread(threadID string) []model.Comment {
+	var out []model.Comment
+	for _, id := range r.order {
+		c := r.comments[id]
+		if c.ThreadID != nil && *c.ThreadID == threadID && c.Published() {
+			out = append(out, *c)
+		}
+	}
+	return out
+}
+
+func (r *memRepo) GetByID(ctx context.Context, threadID string, opts model.ThreadOptions) (*model.Comment, error) {
+	r.threadReads++
+	list := r.thread(threadID)
+	if len(list) == 0 {
+		return nil, nil
+	}
+	root := list[0]
+	root.Replies = list[1:]
+	root.RepliesCount = len(list) - 1
+	return &root, nil
+}
+
+func (r *memRepo) ThreadVersion(ctx context.Context, threadID string) (*model.ThreadVersion, error) {
+	r.versionReads++
+	return &model.ThreadVersion{ThreadID: threadID, UpdatedAt: r.updatedAt, Count: len(r.thread(threadID))}, nil
+}
+
+func (r *memRepo) GetCommentContext(ctx context.Context, id string, ancestors, siblings int) (*model.CommentWithContext, error) {
+	c, ok := r.comments[id]
+	if !ok || !c.Published() {
+		return nil, repository.ErrNotFound
+	}
+	out := &model.CommentWithContext{Comment: *c}
+	// Как и репозиторий, отдаёт родителей от корня к ближайшему
+	for parent := c.AnswerCommentID; parent != nil && len(out.Ancestors) < ancestors; {
+		p := r.comments[*parent]
+		out.Ancestors = append([]model.Comment{*p}, out.Ancestors...)
+		parent = p.AnswerCommentID
+	}
+	return out, nil
+}
+
+func (r *memRepo) Update(ctx context.Context, id, content, authorID string, status model.CommentStatus, reason *string, expectedVersion *int) (*model.Comment, error) {
+	c, ok := r.comments[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	if c.AuthorID != authorID {
+		return nil, repository.ErrForbidden
+	}
+	if expectedVersion != nil && *expectedVersion != c.Version {
+		return nil, &repository.VersionConflictError{Current: c.Version}
+	}
+	c.Content, c.Status, c.ModerationReason = content, status, reason
+	c.Version++
+	r.updatedAt = r.updatedAt.Add(time.Second)
+	out := *c
+	return &out, nil
+}
+
+func (r *memRepo) Delete(ctx context.Context, id, authorID string, expectedVersion *int) (*model.Comment, error) {
+	c, ok := r.comments[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	if c.AuthorID != authorID {
+		return nil, repository.ErrForbidden
+	}
+	c.Status = model.CommentStatusDeleted
+	c.Version++
+	r.updatedAt = r.updatedAt.Add(time.Second)
+	out := *c
+	return &out, nil
+}
+
+type nopDrafts struct{}
+
+func (nopDrafts) Discard(ctx context.Context, authorID, threadID string) error { return nil }
+
+type nopSubscriber struct{}
+
+func (nopSubscriber) AutoFollow(ctx context.Context, threadID, userID string) error { return nil }
+
+func newTestRouter(repo repository.CommentRepoInterface) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewCommentHandler(comments.NewCommentService(repo, nil, nopDrafts{}, nopSubscriber{}, 0, 0), func(*gin.Context) {})
+	r := gin.New()
+	h.RegisterRoutes(r.Group("/api"))
+	h.RegisterRoutesV2(r.Group("/api/v2"))
+	return r
+}
+
+func do(r *gin.Engine, method, path, body string, headers ...string) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestV2CommentLifecycle(t *testing.T) {
+	r := newTestRouter(newMemRepo())
+
+	w := do(r, http.MethodPost, "/api/v2/threads/t1/comments", `{"author_id":"alice","content":"hello"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create: %d %s", w.Code, w.Body)
+	}
+	if loc := w.Header().Get("Location"); loc != "/api/v2/comments/c1" {
+		t.Fatalf("Location = %q", loc)
+	}
+	if etag := w.Header().Get("ETag"); etag != `"1"` {
+		t.Fatalf("create ETag = %q", etag)
+	}
+	var created model.Comment
+	json.Unmarshal(w.Body.Bytes(), &created)
+	if created.ThreadID == nil || *created.ThreadID != "t1" {
+		t.Fatalf("thread is not taken from the path: %+v", created.ThreadID)
+	}
+
+	if w := do(r, http.MethodGet, "/api/v2/comments/c1", ""); w.Code != http.StatusOK || w.Header().Get("ETag") != `"1"` {
+		t.Fatalf("get: %d, ETag %q", w.Code, w.Header().Get("ETag"))
+	}
+
+	if w := do(r, http.MethodPatch, "/api/v2/comments/c1", `{"author_id":"bob","content":"mine"}`); w.Code != http.StatusForbidden {
+		t.Fatalf("patch by another user: %d, want 403", w.Code)
+	}
+	if w := do(r, http.MethodPost, "/api/comments/update", `{"id":"c1","author_id":"bob","content":"mine"}`); w.Code != http.StatusForbidden {
+		t.Fatalf("v1 update by another user: %d, want 403", w.Code)
+	}
+	if w := do(r, http.MethodPatch, "/api/v2/comments/c1", `{"author_id":"alice","content":"edited"}`, "If-Match", `"0"`); w.Code != http.StatusConflict {
+		t.Fatalf("stale patch: %d, want 409", w.Code)
+	}
+	w = do(r, http.MethodPatch, "/api/v2/comments/c1", `{"author_id":"alice","content":"edited"}`, "If-Match", `"1"`)
+	if w.Code != http.StatusOK || w.Header().Get("ETag") != `"2"` {
+		t.Fatalf("patch: %d, ETag %q", w.Code, w.Header().Get("ETag"))
+	}
+
+	if w := do(r, http.MethodGet, "/api/v2/threads/t1/comments", ""); w.Code != http.StatusOK {
+		t.Fatalf("thread: %d", w.Code)
+	}
+
+	if w := do(r, http.MethodDelete, "/api/v2/comments/c1", ""); w.Code != http.StatusBadRequest {
+		t.Fatalf("delete without author: %d, want 400", w.Code)
+	}
+	if w := do(r, http.MethodDelete, "/api/v2/comments/c1?author_id=alice", ""); w.Code != http.StatusNoContent {
+		t.Fatalf("delete: %d, want 204", w.Code)
+	}
+	if w := do(r, http.MethodGet, "/api/v2/threads/t1/comments", ""); w.Code != http.StatusNotFound {
+		t.Fatalf("empty thread: %d, want 404", w.Code)
+	}
+	if w := do(r, http.MethodGet, "/api/v2/comments/c1", ""); w.Code != http.StatusNotFound {
+		t.Fatalf("deleted comment: %d, want 404", w.Code)
+	}
+}
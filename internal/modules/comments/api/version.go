@@ -0,0 +1,48 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pksep/comments/internal/modules/comments/repository"
+)
+
+// setETag выставляет ETag комментария по его версии
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", fmt.Sprintf(`"%d"`, version))
+}
+
+// expectedVersion берёт ожидаемую версию из тела запроса, а если её нет — из If-Match.
+// If-Match: * и отсутствие обоих означают «без проверки»
+func expectedVersion(c *gin.Context, fromBody *int) (*int, error) {
+	if fromBody != nil {
+		return fromBody, nil
+	}
+
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	tag := strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+	version, err := strconv.Atoi(tag)
+	if err != nil {
+		return nil, fmt.Errorf("invalid If-Match header %q", header)
+	}
+	return &version, nil
+}
+
+// respondError отвечает ошибкой сервиса; при конфликте версий отдаёт текущую версию
+func respondError(c *gin.Context, err error, fallback int) {
+	var conflict *repository.VersionConflictError
+	if errors.As(err, &conflict) {
+		setETag(c, conflict.Current)
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "current_version": conflict.Current})
+		return
+	}
+	c.JSON(errorStatus(err, fallback), gin.H{"error": err.Error()})
+}
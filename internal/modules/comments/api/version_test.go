@@ -0,0 +1,79 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pksep/comments/internal/modules/comments/repository"
+)
+
+func testContext(ifMatch string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+	if ifMatch != "" {
+		c.Request.Header.Set("If-Match", ifMatch)
+	}
+	return c, w
+}
+
+func TestExpectedVersion(t *testing.T) {
+	two := 2
+	cases := []struct {
+		name     string
+		body     *int
+		ifMatch  string
+		want     *int
+		wantFail bool
+	}{
+		{"no version", nil, "", nil, false},
+		{"any version", nil, "*", nil, false},
+		{"strong etag", nil, `"7"`, intPtr(7), false},
+		{"weak etag", nil, `W/"7"`, intPtr(7), false},
+		{"body wins over header", &two, `"7"`, &two, false},
+		{"garbage", nil, `"abc"`, nil, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, _ := testContext(tc.ifMatch)
+			got, err := expectedVersion(c, tc.body)
+			if (err != nil) != tc.wantFail {
+				t.Fatalf("err = %v, want failure %v", err, tc.wantFail)
+			}
+			if (got == nil) != (tc.want == nil) || (got != nil && *got != *tc.want) {
+				t.Fatalf("version = %v, want %v", deref(got), deref(tc.want))
+			}
+		})
+	}
+}
+
+func TestRespondErrorVersionConflict(t *testing.T) {
+	c, w := testContext("")
+	respondError(c, &repository.VersionConflictError{Current: 4}, http.StatusInternalServerError)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409", w.Code)
+	}
+	if etag := w.Header().Get("ETag"); etag != `"4"` {
+		t.Fatalf("ETag = %q, want current version", etag)
+	}
+	var body struct {
+		CurrentVersion int `json:"current_version"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body.CurrentVersion != 4 {
+		t.Fatalf("body = %s", w.Body)
+	}
+}
+
+func intPtr(v int) *int { return &v }
+
+func deref(v *int) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
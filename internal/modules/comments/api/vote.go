@@ -0,0 +1,54 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pksep/comments/internal/modules/comments/api/dto"
+	"github.com/pksep/comments/internal/modules/comments/model"
+)
+
+func (h *CommentHandler) Vote(c *gin.Context) {
+	var body dto.VoteCommentDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.Vote(c, model.Vote{CommentID: body.ID, VoterID: body.VoterID, Value: *body.Value})
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *CommentHandler) VoteV2(c *gin.Context) {
+	var body dto.PutVoteDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.Vote(c, model.Vote{CommentID: c.Param("id"), VoterID: body.VoterID, Value: body.Value})
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *CommentHandler) UnvoteV2(c *gin.Context) {
+	var query dto.VoterQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.Vote(c, model.Vote{CommentID: c.Param("id"), VoterID: query.VoterID})
+	if err != nil {
+		respondError(c, err, http.StatusInternalServerError)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,51 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+)
+
+// csvFlushEvery — через сколько строк буфер отправляется клиенту
+const csvFlushEvery = 100
+
+type csvWriter struct {
+	w    *csv.Writer
+	rows int
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) Begin(string) error {
+	return c.w.Write([]string{"id", "parent_id", "depth", "author_id", "status", "content", "version", "created_at", "updated_at"})
+}
+
+func (c *csvWriter) Write(comment model.Comment, depth int) error {
+	parentID := ""
+	if comment.AnswerCommentID != nil {
+		parentID = *comment.AnswerCommentID
+	}
+	if err := c.w.Write([]string{
+		comment.ID, parentID, strconv.Itoa(depth), comment.AuthorID, string(comment.Status), comment.Content,
+		strconv.Itoa(comment.Version), comment.CreatedAt.UTC().Format(time.RFC3339Nano), comment.UpdatedAt.UTC().Format(time.RFC3339Nano),
+	}); err != nil {
+		return err
+	}
+
+	c.rows++
+	if c.rows%csvFlushEvery == 0 {
+		c.w.Flush()
+		return c.w.Error()
+	}
+	return nil
+}
+
+func (c *csvWriter) End() error {
+	c.w.Flush()
+	return c.w.Error()
+}
@@ -0,0 +1,67 @@
+// Package export форматирует тред для выгрузки. Комментарии передаются
+// по одному в порядке обхода дерева в глубину, поэтому тред любого размера
+// выгружается без загрузки в память целиком
+package export
+
+import (
+	"errors"
+	"io"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+)
+
+type Format string
+
+const (
+	// FormatJSON — вложенное дерево ответов
+	FormatJSON Format = "json"
+	// FormatCSV — плоская таблица с parent_id
+	FormatCSV Format = "csv"
+	// FormatHTML — страница для печати
+	FormatHTML Format = "html"
+	// FormatMarkdown — стенограмма в Markdown
+	FormatMarkdown Format = "md"
+)
+
+// ErrUnknownFormat — формат выгрузки не поддерживается
+var ErrUnknownFormat = errors.New("format must be one of json, csv, html, md")
+
+// Writer записывает тред. Begin вызывается перед первым комментарием, End — после последнего.
+// Комментарии приходят в порядке обхода в глубину: за каждым следуют его ответы с depth+1
+type Writer interface {
+	Begin(threadID string) error
+	Write(c model.Comment, depth int) error
+	End() error
+}
+
+// NewWriter создаёт Writer для формата
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatJSON:
+		return &jsonWriter{w: w}, nil
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatHTML:
+		return &htmlWriter{w: w}, nil
+	case FormatMarkdown:
+		return &markdownWriter{w: w}, nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// ContentType возвращает MIME-тип выгрузки
+func (f Format) ContentType() string {
+	switch f {
+	case FormatJSON:
+		return "application/json; charset=utf-8"
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatHTML:
+		return "text/html; charset=utf-8"
+	case FormatMarkdown:
+		return "text/markdown; charset=utf-8"
+	default:
+		return "application/octet-stream"
+	}
+}
@@ -0,0 +1,138 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+)
+
+type item struct {
+	c     model.Comment
+	depth int
+}
+
+// tree — тред в порядке обхода в глубину:
+// r1 → (a → a1, b), r2
+func tree() []item {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	mk := func(id, parent, content string) model.Comment {
+		c := model.Comment{ID: id, AuthorID: "u-" + id, Content: content, Status: model.CommentStatusCreated, Version: 1, CreatedAt: at, UpdatedAt: at}
+		if parent != "" {
+			c.AnswerCommentID = &parent
+		}
+		return c
+	}
+	return []item{
+		{mk("r1", "", "root"), 0},
+		{mk("a", "r1", "<b>bold</b>"), 1},
+		{mk("a1", "a", "two\nlines"), 2},
+		{mk("b", "r1", "plain, with comma"), 1},
+		{mk("r2", "", "second root"), 0},
+	}
+}
+
+func export(t *testing.T, format Format, items []item) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(format, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Begin("t1"); err != nil {
+		t.Fatal(err)
+	}
+	for _, it := range items {
+		if err := w.Write(it.c, it.depth); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.End(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+type node struct {
+	ID      string `json:"id"`
+	Content string `json:"content"`
+	Replies []node `json:"replies"`
+}
+
+func TestJSONTree(t *testing.T) {
+	var doc struct {
+		ThreadID string `json:"thread_id"`
+		Comments []node `json:"comments"`
+	}
+	out := export(t, FormatJSON, tree())
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("invalid JSON %s: %v", out, err)
+	}
+	if doc.ThreadID != "t1" || len(doc.Comments) != 2 {
+		t.Fatalf("doc = %+v", doc)
+	}
+	r1 := doc.Comments[0]
+	if r1.ID != "r1" || len(r1.Replies) != 2 || r1.Replies[0].ID != "a" || r1.Replies[1].ID != "b" {
+		t.Fatalf("r1 = %+v", r1)
+	}
+	if a := r1.Replies[0]; len(a.Replies) != 1 || a.Replies[0].Content != "two\nlines" {
+		t.Fatalf("a = %+v", a)
+	}
+	if r2 := doc.Comments[1]; r2.ID != "r2" || r2.Replies == nil || len(r2.Replies) != 0 {
+		t.Fatalf("r2 = %+v, want empty replies", r2)
+	}
+}
+
+func TestJSONEmptyAndInvalidDepth(t *testing.T) {
+	if out := export(t, FormatJSON, nil); out != `{"thread_id":"t1","comments":[]}` {
+		t.Fatalf("empty thread = %s", out)
+	}
+
+	w, _ := NewWriter(FormatJSON, &bytes.Buffer{})
+	w.Begin("t1")
+	if err := w.Write(model.Comment{ID: "x"}, 1); err == nil {
+		t.Fatal("reply without parent accepted")
+	}
+}
+
+func TestCSVRows(t *testing.T) {
+	rows, err := csv.NewReader(strings.NewReader(export(t, FormatCSV, tree()))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 6 || rows[0][0] != "id" {
+		t.Fatalf("rows = %q", rows)
+	}
+	if a1 := rows[3]; a1[0] != "a1" || a1[1] != "a" || a1[2] != "2" || a1[5] != "two\nlines" {
+		t.Fatalf("a1 row = %q", a1)
+	}
+}
+
+func TestTranscripts(t *testing.T) {
+	page := export(t, FormatHTML, tree())
+	if strings.Contains(page, "<b>bold</b>") || !strings.Contains(page, "&lt;b&gt;bold&lt;/b&gt;") {
+		t.Fatal("comment content is not escaped in HTML")
+	}
+	if !strings.Contains(page, `id="c-a1" style="margin-left: 4em"`) {
+		t.Fatal("reply depth is not indented in HTML")
+	}
+
+	md := export(t, FormatMarkdown, tree())
+	if !strings.Contains(md, "> > two\n> > lines\n") {
+		t.Fatalf("nested reply is not quoted per line:\n%s", md)
+	}
+	if !strings.HasPrefix(md, "# Thread t1\n") {
+		t.Fatalf("markdown header:\n%s", md)
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	if _, err := NewWriter("xml", &bytes.Buffer{}); !errors.Is(err, ErrUnknownFormat) {
+		t.Fatalf("err = %v, want ErrUnknownFormat", err)
+	}
+}
@@ -0,0 +1,96 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+)
+
+// jsonNode — комментарий в дереве без полей, которые заменяет вложенность
+type jsonNode struct {
+	ID        string              `json:"id"`
+	AuthorID  string              `json:"author_id"`
+	Content   string              `json:"content"`
+	Status    model.CommentStatus `json:"status"`
+	Version   int                 `json:"version"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+// jsonWriter пишет {"thread_id": ..., "comments": [{..., "replies": [...]}]}.
+// Открытыми остаются только объекты текущей ветки
+type jsonWriter struct {
+	w io.Writer
+	// open — сколько объектов комментариев сейчас не закрыто
+	open    int
+	written int
+	prev    int
+}
+
+func (j *jsonWriter) Begin(threadID string) error {
+	id, _ := json.Marshal(threadID)
+	_, err := fmt.Fprintf(j.w, `{"thread_id":%s,"comments":[`, id)
+	return err
+}
+
+func (j *jsonWriter) Write(c model.Comment, depth int) error {
+	if depth > j.open {
+		return fmt.Errorf("comment %s: depth %d without parent", c.ID, depth)
+	}
+	if err := j.close(depth); err != nil {
+		return err
+	}
+
+	node, err := json.Marshal(jsonNode{
+		ID:        c.ID,
+		AuthorID:  c.AuthorID,
+		Content:   c.Content,
+		Status:    c.Status,
+		Version:   c.Version,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Первый ответ идёт сразу за открытым "replies":[, остальные — через запятую
+	if j.written > 0 && depth <= j.prev {
+		if _, err := io.WriteString(j.w, ","); err != nil {
+			return err
+		}
+	}
+	if _, err := j.w.Write(node[:len(node)-1]); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(j.w, `,"replies":[`); err != nil {
+		return err
+	}
+
+	j.open++
+	j.written++
+	j.prev = depth
+	return nil
+}
+
+func (j *jsonWriter) End() error {
+	if err := j.close(0); err != nil {
+		return err
+	}
+	_, err := io.WriteString(j.w, "]}")
+	return err
+}
+
+// close закрывает объекты, пока открытыми не останется depth
+func (j *jsonWriter) close(depth int) error {
+	for j.open > depth {
+		if _, err := io.WriteString(j.w, "]}"); err != nil {
+			return err
+		}
+		j.open--
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package export
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+)
+
+// transcriptTime — формат времени в стенограммах
+const transcriptTime = "2006-01-02 15:04:05 UTC"
+
+type htmlWriter struct {
+	w io.Writer
+}
+
+func (h *htmlWriter) Begin(threadID string) error {
+	id := html.EscapeString(threadID)
+	_, err := fmt.Fprintf(h.w, `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Thread %s</title>
+<style>
+body { font-family: serif; max-width: 50em; margin: 2em auto; }
+.comment { border-left: 2px solid #ccc; padding-left: 0.75em; margin: 0.75em 0; page-break-inside: avoid; }
+.meta { color: #555; font-size: 0.9em; margin: 0; }
+.content { white-space: pre-wrap; margin: 0.25em 0 0; }
+</style>
+</head>
+<body>
+<h1>Thread %s</h1>
+`, id, id)
+	return err
+}
+
+func (h *htmlWriter) Write(c model.Comment, depth int) error {
+	_, err := fmt.Fprintf(h.w, `<div class="comment" id="c-%s" style="margin-left: %dem">
+<p class="meta"><strong>%s</strong> · %s%s</p>
+<p class="content">%s</p>
+</div>
+`,
+		html.EscapeString(c.ID), depth*2, html.EscapeString(c.AuthorID),
+		c.CreatedAt.UTC().Format(transcriptTime), html.EscapeString(statusNote(c)), html.EscapeString(c.Content))
+	return err
+}
+
+func (h *htmlWriter) End() error {
+	_, err := io.WriteString(h.w, "</body>\n</html>\n")
+	return err
+}
+
+// markdownWriter передаёт вложенность уровнями цитирования
+type markdownWriter struct {
+	w io.Writer
+}
+
+func (m *markdownWriter) Begin(threadID string) error {
+	_, err := fmt.Fprintf(m.w, "# Thread %s\n\n", threadID)
+	return err
+}
+
+func (m *markdownWriter) Write(c model.Comment, depth int) error {
+	prefix := strings.Repeat("> ", depth)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s**%s** · %s%s\n%s\n", prefix, c.AuthorID, c.CreatedAt.UTC().Format(transcriptTime), statusNote(c), strings.TrimSpace(prefix))
+	for _, line := range strings.Split(c.Content, "\n") {
+		b.WriteString(prefix + line + "\n")
+	}
+	b.WriteString("\n")
+
+	_, err := io.WriteString(m.w, b.String())
+	return err
+}
+
+func (m *markdownWriter) End() error {
+	return nil
+}
+
+// statusNote — пометка о статусе для стенограммы, пусто у обычных комментариев
+func statusNote(c model.Comment) string {
+	switch c.Status {
+	case model.CommentStatusCreated:
+		return ""
+	case model.CommentStatusEdited:
+		return " (edited " + c.UpdatedAt.UTC().Format(transcriptTime) + ")"
+	default:
+		return " (" + string(c.Status) + ")"
+	}
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	maxAnchorBlockID = 200
+	maxAnchorQuote   = 2000
+)
+
+// ErrInvalidAnchor — якорь не прошёл проверку
+var ErrInvalidAnchor = errors.New("invalid anchor")
+
+// Anchor — привязка комментария к месту в документе: блок и диапазон текста [Start, End)
+// в нём. Quote — выделенный текст на момент создания, помогает восстановить привязку
+type Anchor struct {
+	BlockID string `json:"block_id"`
+	Start   int    `json:"start"`
+	End     int    `json:"end"`
+	Quote   string `json:"quote,omitempty"`
+}
+
+// Validate проверяет якорь
+func (a *Anchor) Validate() error {
+	switch {
+	case a.BlockID == "":
+		return fmt.Errorf("%w: block_id is required", ErrInvalidAnchor)
+	case len(a.BlockID) > maxAnchorBlockID:
+		return fmt.Errorf("%w: block_id is longer than %d", ErrInvalidAnchor, maxAnchorBlockID)
+	case a.Start < 0 || a.End < a.Start:
+		return fmt.Errorf("%w: range must satisfy 0 <= start <= end", ErrInvalidAnchor)
+	case len(a.Quote) > maxAnchorQuote:
+		return fmt.Errorf("%w: quote is longer than %d", ErrInvalidAnchor, maxAnchorQuote)
+	}
+	return nil
+}
+
+// BlockRemap переносит все якоря блока From в блок To (пусто — блок тот же)
+// со сдвигом диапазонов на OffsetDelta; смещения не уходят ниже нуля
+type BlockRemap struct {
+	From        string `json:"from" binding:"required"`
+	To          string `json:"to"`
+	OffsetDelta int    `json:"offset_delta"`
+}
+
+// AnchorUpdate задаёт новый якорь конкретного комментария
+type AnchorUpdate struct {
+	CommentID string `json:"comment_id" binding:"required"`
+	Anchor    Anchor `json:"anchor"`
+}
+
+// AnchorRemap — изменения якорей треда после правки документа.
+// Сначала применяются переносы блоков, затем точечные обновления
+type AnchorRemap struct {
+	Blocks  []BlockRemap
+	Anchors []AnchorUpdate
+}
+
+// Validate проверяет все новые якоря
+func (r *AnchorRemap) Validate() error {
+	for _, b := range r.Blocks {
+		if len(b.To) > maxAnchorBlockID {
+			return fmt.Errorf("%w: block_id is longer than %d", ErrInvalidAnchor, maxAnchorBlockID)
+		}
+	}
+	for _, u := range r.Anchors {
+		if err := u.Anchor.Validate(); err != nil {
+			return fmt.Errorf("comment %s: %w", u.CommentID, err)
+		}
+	}
+	return nil
+}
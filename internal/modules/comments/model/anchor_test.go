@@ -0,0 +1,51 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAnchorValidate(t *testing.T) {
+	cases := []struct {
+		name  string
+		a     Anchor
+		valid bool
+	}{
+		{"range", Anchor{BlockID: "p1", Start: 3, End: 10, Quote: "quoted"}, true},
+		{"caret", Anchor{BlockID: "p1", Start: 4, End: 4}, true},
+		{"no block", Anchor{Start: 0, End: 1}, false},
+		{"long block", Anchor{BlockID: strings.Repeat("b", maxAnchorBlockID+1)}, false},
+		{"negative start", Anchor{BlockID: "p1", Start: -1, End: 1}, false},
+		{"reversed range", Anchor{BlockID: "p1", Start: 5, End: 2}, false},
+		{"long quote", Anchor{BlockID: "p1", Quote: strings.Repeat("q", maxAnchorQuote+1)}, false},
+	}
+	for _, tc := range cases {
+		err := tc.a.Validate()
+		if tc.valid && err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+		}
+		if !tc.valid && !errors.Is(err, ErrInvalidAnchor) {
+			t.Errorf("%s: err = %v, want ErrInvalidAnchor", tc.name, err)
+		}
+	}
+}
+
+func TestAnchorRemapValidate(t *testing.T) {
+	ok := AnchorRemap{
+		Blocks:  []BlockRemap{{From: "p1", To: "p2", OffsetDelta: -3}},
+		Anchors: []AnchorUpdate{{CommentID: "c1", Anchor: Anchor{BlockID: "p2", Start: 0, End: 4}}},
+	}
+	if err := ok.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	bad := AnchorRemap{Anchors: []AnchorUpdate{{CommentID: "c1", Anchor: Anchor{BlockID: "p2", Start: 4, End: 0}}}}
+	if err := bad.Validate(); !errors.Is(err, ErrInvalidAnchor) || !strings.Contains(err.Error(), "c1") {
+		t.Fatalf("err = %v, want ErrInvalidAnchor naming the comment", err)
+	}
+	long := AnchorRemap{Blocks: []BlockRemap{{From: "p1", To: strings.Repeat("b", maxAnchorBlockID+1)}}}
+	if err := long.Validate(); !errors.Is(err, ErrInvalidAnchor) {
+		t.Fatalf("err = %v, want ErrInvalidAnchor", err)
+	}
+}
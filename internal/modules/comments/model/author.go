@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// AuthorFilter — параметры выборки комментариев автора
+type AuthorFilter struct {
+	AuthorID string
+	// Статусы для выборки; пустой список — только опубликованные (created, edited)
+	Statuses []CommentStatus
+	Limit    int
+	Offset   int
+}
+
+// ThreadRef — краткие сведения о треде, к которому относится комментарий
+type ThreadRef struct {
+	ID            string     `json:"id"`
+	RootCommentID *string    `json:"root_comment_id,omitempty"`
+	CommentCount  int        `json:"comment_count"`
+	LastCommentAt *time.Time `json:"last_comment_at,omitempty"`
+}
+
+// AuthorComment — комментарий в ленте активности автора
+type AuthorComment struct {
+	Comment
+	Thread *ThreadRef `json:"thread,omitempty"`
+}
@@ -10,20 +10,88 @@ const (
 	CommentStatusCreated CommentStatus = "created"
 	CommentStatusEdited  CommentStatus = "edited"
 	CommentStatusDeleted CommentStatus = "deleted"
+	// CommentStatusPending — комментарий ожидает одобрения модератором и скрыт из публичного чтения
+	CommentStatusPending CommentStatus = "pending"
+	// CommentStatusHidden — комментарий скрыт модератором или по жалобам, виден только модераторам
+	CommentStatusHidden CommentStatus = "hidden"
+	// CommentStatusScheduled — комментарий ждёт публикации в PublishAt, виден только автору
+	CommentStatusScheduled CommentStatus = "scheduled"
 )
 
+// RedactedContent заменяет текст комментариев автора, чьи данные удалены по запросу
+const RedactedContent = "[redacted]"
+
 // Comment is a reusable comment entity that can be attached to any domain entity
 // by specifying entity type and entity id.
 type Comment struct {
-	ID              string        `json:"id" db:"id"`
-	AuthorID        string        `json:"author_id" db:"author_id"`
-	Content         string        `json:"content" db:"content"`
-	ThreadID        *string       `json:"thread_id,omitempty" db:"thread_id"`
-	AnswerCommentID *string       `json:"answer_comment_id,omitempty" db:"answer_comment_id"`
-	Status          CommentStatus `json:"status" db:"status"`
-	CreatedAt       time.Time     `json:"created_at" db:"created_at"`
-	UpdatedAt       time.Time     `json:"updated_at" db:"updated_at"`
-	Replies         []Comment     `json:"replies" db:"-"`
-	RepliesCount    int           `json:"replies_count" db:"-"`
-	IsFirstComment  bool          `json:"is_first_comment" db:"-"`
+	ID               string        `json:"id" db:"id"`
+	AuthorID         string        `json:"author_id" db:"author_id"`
+	Content          string        `json:"content" db:"content"`
+	ThreadID         *string       `json:"thread_id,omitempty" db:"thread_id"`
+	AnswerCommentID  *string       `json:"answer_comment_id,omitempty" db:"answer_comment_id"`
+	Status           CommentStatus `json:"status" db:"status"`
+	ModerationReason *string       `json:"moderation_reason,omitempty" db:"moderation_reason"`
+	ModeratedBy      *string       `json:"moderated_by,omitempty" db:"moderated_by"`
+	ModeratedAt      *time.Time    `json:"moderated_at,omitempty" db:"moderated_at"`
+	// Anchor — место в документе, к которому привязан комментарий верхнего уровня
+	Anchor *Anchor `json:"anchor,omitempty" db:"anchor"`
+	// PublishAt — время отложенной публикации. До публикации created_at совпадает с ним,
+	// чтобы порядок комментариев в треде не менялся в момент публикации
+	PublishAt *time.Time `json:"publish_at,omitempty" db:"publish_at"`
+	// Версия увеличивается при каждом изменении и используется для оптимистичной блокировки
+	Version   int        `json:"version" db:"version"`
+	PinnedAt  *time.Time `json:"pinned_at,omitempty" db:"pinned_at"`
+	PinnedBy  *string    `json:"pinned_by,omitempty" db:"pinned_by"`
+	Upvotes   int        `json:"upvotes" db:"upvotes"`
+	Downvotes int        `json:"downvotes" db:"downvotes"`
+	Score     int        `json:"score" db:"score"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+	// ViewerVote — голос пользователя, для которого читается тред (1, -1 или nil)
+	ViewerVote *int `json:"viewer_vote,omitempty" db:"-"`
+	// AcceptedAnswerID и ResolvedAt заполняются у корня решённого треда
+	AcceptedAnswerID *string    `json:"accepted_answer_id,omitempty" db:"-"`
+	ResolvedAt       *time.Time `json:"resolved_at,omitempty" db:"-"`
+	// Accepted — комментарий принят как ответ на вопрос треда
+	Accepted bool `json:"accepted,omitempty" db:"-"`
+	// Pinned — закреплённые комментарии треда в порядке закрепления; заполняется
+	// у корня треда. Закреплённые комментарии остаются и в Replies
+	Pinned         []Comment `json:"pinned,omitempty" db:"-"`
+	Replies        []Comment `json:"replies" db:"-"`
+	RepliesCount   int       `json:"replies_count" db:"-"`
+	IsFirstComment bool      `json:"is_first_comment" db:"-"`
+	// FirstByAuthor — первый комментарий автора в треде; выставляется при создании
+	// и публикации по расписанию в той же транзакции
+	FirstByAuthor bool `json:"-" db:"-"`
+}
+
+// Published сообщает, виден ли комментарий в публичном чтении и учитывается ли он
+// в счётчиках треда. nil — комментария нет
+func (c *Comment) Published() bool {
+	return c != nil && (c.Status == CommentStatusCreated || c.Status == CommentStatusEdited)
+}
+
+// AfterEdit возвращает статус и причину модерации комментария после правки автором;
+// status и reason — решение модерации по новому тексту. Скрытый комментарий остаётся
+// скрытым с причиной модератора: правкой автор не снимает скрытие. Ожидающий модерации
+// остаётся в очереди со своей причиной, иначе правка обходила бы проверку. Запланированный
+// остаётся запланированным, а решение модерации применится при публикации
+func (c *Comment) AfterEdit(status CommentStatus, reason *string) (CommentStatus, *string) {
+	switch c.Status {
+	case CommentStatusHidden, CommentStatusPending:
+		return c.Status, c.ModerationReason
+	case CommentStatusScheduled:
+		return c.Status, reason
+	default:
+		return status, reason
+	}
+}
+
+// CommentWithContext — комментарий вместе с окружением для перехода «к комментарию»:
+// цепочкой родителей (от дальнего к ближайшему) и соседями по ветке
+type CommentWithContext struct {
+	Comment
+	Ancestors      []Comment `json:"ancestors,omitempty"`
+	SiblingsBefore []Comment `json:"siblings_before,omitempty"`
+	SiblingsAfter  []Comment `json:"siblings_after,omitempty"`
 }
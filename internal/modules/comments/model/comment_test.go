@@ -0,0 +1,36 @@
+package model
+
+import "testing"
+
+func TestAfterEdit(t *testing.T) {
+	moderatorReason := "reported by users"
+	pendingReason := "too many links"
+
+	cases := []struct {
+		name       string
+		comment    Comment
+		status     CommentStatus
+		reason     *string
+		wantStatus CommentStatus
+		wantReason *string
+	}{
+		{"published edit", Comment{Status: CommentStatusCreated}, CommentStatusEdited, nil, CommentStatusEdited, nil},
+		{"published edit held", Comment{Status: CommentStatusEdited}, CommentStatusPending, &pendingReason, CommentStatusPending, &pendingReason},
+		{"hidden stays hidden", Comment{Status: CommentStatusHidden, ModerationReason: &moderatorReason}, CommentStatusEdited, nil, CommentStatusHidden, &moderatorReason},
+		{"hidden keeps moderator reason", Comment{Status: CommentStatusHidden, ModerationReason: &moderatorReason}, CommentStatusPending, &pendingReason, CommentStatusHidden, &moderatorReason},
+		{"pending stays pending", Comment{Status: CommentStatusPending, ModerationReason: &pendingReason}, CommentStatusEdited, nil, CommentStatusPending, &pendingReason},
+		{"pending keeps its reason", Comment{Status: CommentStatusPending, ModerationReason: &pendingReason}, CommentStatusPending, &moderatorReason, CommentStatusPending, &pendingReason},
+		{"scheduled stays scheduled", Comment{Status: CommentStatusScheduled}, CommentStatusPending, &pendingReason, CommentStatusScheduled, &pendingReason},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			status, reason := tc.comment.AfterEdit(tc.status, tc.reason)
+			if status != tc.wantStatus {
+				t.Errorf("status = %q, want %q", status, tc.wantStatus)
+			}
+			if reason != tc.wantReason {
+				t.Errorf("reason = %v, want %v", reason, tc.wantReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,6 @@
+package model
+
+import "errors"
+
+// ErrContentTooLong — текст длиннее лимита тенанта
+var ErrContentTooLong = errors.New("content exceeds the tenant limit")
@@ -0,0 +1,60 @@
+package model
+
+// ThreadSort — порядок тредов в списке
+type ThreadSort string
+
+const (
+	// ThreadSortCreated — по дате корневого комментария, новые первыми
+	ThreadSortCreated ThreadSort = "created"
+	// ThreadSortActivity — по дате последнего комментария в треде, свежие первыми
+	ThreadSortActivity ThreadSort = "activity"
+	// ThreadSortReplies — по числу ответов, обсуждаемые первыми
+	ThreadSortReplies ThreadSort = "replies"
+	// ThreadSortTop — по рейтингу корневого комментария, лучшие первыми
+	ThreadSortTop ThreadSort = "top"
+	// ThreadSortControversial — по спорности корневого комментария
+	ThreadSortControversial ThreadSort = "controversial"
+)
+
+// ReplySort — порядок ответов при чтении треда
+type ReplySort string
+
+const (
+	// ReplySortCreated — в порядке написания
+	ReplySortCreated ReplySort = "created"
+	// ReplySortTop — по рейтингу, лучшие первыми
+	ReplySortTop ReplySort = "top"
+	// ReplySortControversial — по спорности
+	ReplySortControversial ReplySort = "controversial"
+)
+
+// ReplyPreview — какие ответы показываются в превью треда
+type ReplyPreview string
+
+const (
+	// ReplyPreviewLatest — последние N ответов
+	ReplyPreviewLatest ReplyPreview = "latest"
+	// ReplyPreviewEarliest — первые N ответов
+	ReplyPreviewEarliest ReplyPreview = "earliest"
+)
+
+// DefaultReplyLimit — сколько ответов показывать в превью по умолчанию
+const DefaultReplyLimit = 3
+
+// ListOptions — параметры выдачи списка тредов
+type ListOptions struct {
+	Sort       ThreadSort
+	Preview    ReplyPreview
+	ReplyLimit int
+	// Resolved оставляет только решённые (true) или нерешённые (false) треды, nil — все
+	Resolved *bool
+	// ViewerID — пользователь, чей голос вернуть в ViewerVote
+	ViewerID string
+}
+
+// ThreadOptions — параметры чтения одного треда
+type ThreadOptions struct {
+	Sort ReplySort
+	// ViewerID — пользователь, чей голос вернуть в ViewerVote
+	ViewerID string
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// Report — жалоба пользователя на комментарий
+type Report struct {
+	ID         string     `json:"id" db:"id"`
+	CommentID  string     `json:"comment_id" db:"comment_id"`
+	ReporterID string     `json:"reporter_id" db:"reporter_id"`
+	Reason     string     `json:"reason" db:"reason"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty" db:"resolved_at"`
+}
+
+// QueueFilter — какие комментарии показывать в очереди модерации
+type QueueFilter string
+
+const (
+	QueueAll      QueueFilter = ""
+	QueuePending  QueueFilter = "pending"
+	QueueHidden   QueueFilter = "hidden"
+	QueueReported QueueFilter = "reported"
+)
+
+// QueueItem — комментарий в очереди модерации вместе с открытыми жалобами
+type QueueItem struct {
+	Comment
+	ReportsCount int      `json:"reports_count"`
+	Reports      []Report `json:"reports"`
+}
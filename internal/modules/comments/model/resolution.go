@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// Resolution — состояние треда-вопроса. Тред решён, если выбран принятый ответ
+type Resolution struct {
+	ThreadID          string     `json:"thread_id"`
+	Resolved          bool       `json:"resolved"`
+	AcceptedCommentID *string    `json:"accepted_comment_id,omitempty"`
+	ResolvedBy        *string    `json:"resolved_by,omitempty"`
+	ResolvedAt        *time.Time `json:"resolved_at,omitempty"`
+}
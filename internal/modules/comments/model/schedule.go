@@ -0,0 +1,6 @@
+package model
+
+import "errors"
+
+// ErrInvalidPublishAt — время публикации при переносе должно быть в будущем
+var ErrInvalidPublishAt = errors.New("publish_at must be in the future")
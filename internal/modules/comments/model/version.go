@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// ThreadVersion — слепок состояния треда для условных запросов: меняется
+// при любом изменении, видимом в ответе чтения треда
+type ThreadVersion struct {
+	ThreadID string
+	// Время последнего изменения треда, строго растёт
+	UpdatedAt time.Time
+	// Число опубликованных комментариев треда
+	Count int
+}
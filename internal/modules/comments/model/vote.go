@@ -0,0 +1,31 @@
+package model
+
+import "math"
+
+// Vote — голос пользователя за комментарий: 1 — за, -1 — против
+type Vote struct {
+	CommentID string `json:"comment_id"`
+	VoterID   string `json:"voter_id"`
+	Value     int    `json:"value"`
+}
+
+// VoteResult — счётчики комментария после голосования и текущий голос пользователя
+// (nil, если голос снят)
+type VoteResult struct {
+	CommentID  string `json:"comment_id"`
+	Upvotes    int    `json:"upvotes"`
+	Downvotes  int    `json:"downvotes"`
+	Score      int    `json:"score"`
+	ViewerVote *int   `json:"viewer_vote"`
+}
+
+// Controversy — насколько спорный комментарий: растёт с числом голосов и тем быстрее,
+// чем ближе соотношение «за» и «против» к равному. Без голосов одной из сторон — 0
+func Controversy(up, down int) float64 {
+	if up <= 0 || down <= 0 {
+		return 0
+	}
+	magnitude := float64(up + down)
+	balance := float64(min(up, down)) / float64(max(up, down))
+	return math.Pow(magnitude, balance)
+}
@@ -0,0 +1,15 @@
+package model
+
+import "testing"
+
+func TestControversy(t *testing.T) {
+	if Controversy(10, 0) != 0 || Controversy(0, 10) != 0 {
+		t.Fatal("one-sided votes are controversial")
+	}
+	if Controversy(5, 5) <= Controversy(9, 1) {
+		t.Fatal("an even split must be more controversial than a lopsided one")
+	}
+	if Controversy(50, 50) <= Controversy(5, 5) {
+		t.Fatal("more votes must be more controversial at the same balance")
+	}
+}
@@ -0,0 +1,28 @@
+package moderation
+
+import "github.com/pksep/comments/internal/config"
+
+// NewPipelineFromConfig собирает конвейер из встроенных правил согласно конфигурации.
+// Внешние классификаторы добавляются после через Register.
+func NewPipelineFromConfig(cfg config.ModerationConfig, finder DuplicateFinder) (*Pipeline, error) {
+	p := NewPipeline()
+
+	if len(cfg.BlockedWords) > 0 {
+		p.Register(NewWordBlocklist(cfg.BlockedWords))
+	}
+	if len(cfg.BlockedPatterns) > 0 {
+		patterns, err := NewPatternBlocklist(cfg.BlockedPatterns)
+		if err != nil {
+			return nil, err
+		}
+		p.Register(patterns)
+	}
+	if cfg.MaxLinks > 0 {
+		p.Register(NewLinkLimit(cfg.MaxLinks))
+	}
+	if cfg.DuplicateWindow > 0 && finder != nil {
+		p.Register(NewDuplicateContent(finder, cfg.DuplicateWindow))
+	}
+
+	return p, nil
+}
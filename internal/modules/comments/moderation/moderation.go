@@ -0,0 +1,98 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+)
+
+// Verdict — решение конвейера модерации по комментарию
+type Verdict int
+
+const (
+	// VerdictAccept — комментарий публикуется сразу
+	VerdictAccept Verdict = iota
+	// VerdictPending — комментарий сохраняется, но скрыт до одобрения модератором
+	VerdictPending
+	// VerdictReject — комментарий отклоняется и не сохраняется
+	VerdictReject
+)
+
+// Decision — результат одной проверки или всего конвейера
+type Decision struct {
+	Verdict Verdict
+	Reason  string
+}
+
+// Accept — решение «пропустить без замечаний»
+var Accept = Decision{Verdict: VerdictAccept}
+
+// Input — данные комментария, передаваемые на проверку
+type Input struct {
+	// ID комментария; пустой при создании
+	CommentID string
+	AuthorID  string
+	ThreadID  *string
+	Content   string
+}
+
+// Checker — точка расширения конвейера. Встроенные правила и внешние
+// классификаторы (ML-сервисы, антиспам-API) реализуют один и тот же интерфейс.
+type Checker interface {
+	Check(ctx context.Context, in Input) (Decision, error)
+}
+
+// CheckerFunc позволяет использовать обычную функцию как Checker
+type CheckerFunc func(ctx context.Context, in Input) (Decision, error)
+
+func (f CheckerFunc) Check(ctx context.Context, in Input) (Decision, error) {
+	return f(ctx, in)
+}
+
+// RejectedError возвращается, когда комментарий отклонён модерацией
+type RejectedError struct {
+	Reason string
+}
+
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("comment rejected by moderation: %s", e.Reason)
+}
+
+// Pipeline последовательно применяет проверки. Первый Reject прерывает
+// конвейер, Pending запоминается, но остальные проверки всё равно выполняются.
+type Pipeline struct {
+	checkers []Checker
+}
+
+// NewPipeline создаёт конвейер из набора проверок
+func NewPipeline(checkers ...Checker) *Pipeline {
+	return &Pipeline{checkers: checkers}
+}
+
+// Register добавляет проверку (например, внешний классификатор) в конец конвейера
+func (p *Pipeline) Register(c Checker) {
+	p.checkers = append(p.checkers, c)
+}
+
+// Check прогоняет комментарий через все проверки
+func (p *Pipeline) Check(ctx context.Context, in Input) (Decision, error) {
+	if p == nil {
+		return Accept, nil
+	}
+
+	result := Accept
+	for _, c := range p.checkers {
+		d, err := c.Check(ctx, in)
+		if err != nil {
+			return Decision{}, err
+		}
+		switch d.Verdict {
+		case VerdictReject:
+			return d, nil
+		case VerdictPending:
+			if result.Verdict == VerdictAccept {
+				result = d
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,109 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pksep/comments/internal/config"
+)
+
+func verdict(v Verdict, reason string) Checker {
+	return CheckerFunc(func(context.Context, Input) (Decision, error) {
+		return Decision{Verdict: v, Reason: reason}, nil
+	})
+}
+
+func TestPipelineVerdicts(t *testing.T) {
+	var calledAfterReject bool
+	after := CheckerFunc(func(context.Context, Input) (Decision, error) {
+		calledAfterReject = true
+		return Accept, nil
+	})
+
+	cases := []struct {
+		name     string
+		pipeline *Pipeline
+		want     Decision
+	}{
+		{"nil pipeline accepts", nil, Accept},
+		{"empty pipeline accepts", NewPipeline(), Accept},
+		{"first pending wins", NewPipeline(verdict(VerdictPending, "a"), verdict(VerdictPending, "b")), Decision{VerdictPending, "a"}},
+		{"reject overrides pending", NewPipeline(verdict(VerdictPending, "a"), verdict(VerdictReject, "b")), Decision{VerdictReject, "b"}},
+		{"reject stops pipeline", NewPipeline(verdict(VerdictReject, "a"), after), Decision{VerdictReject, "a"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.pipeline.Check(context.Background(), Input{Content: "hi"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("decision = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+	if calledAfterReject {
+		t.Error("checker after reject was called")
+	}
+}
+
+func TestPipelineCheckerError(t *testing.T) {
+	boom := errors.New("classifier unavailable")
+	p := NewPipeline(CheckerFunc(func(context.Context, Input) (Decision, error) {
+		return Decision{}, boom
+	}))
+	if _, err := p.Check(context.Background(), Input{}); !errors.Is(err, boom) {
+		t.Fatalf("err = %v, want %v", err, boom)
+	}
+}
+
+type duplicates bool
+
+func (d duplicates) HasDuplicate(ctx context.Context, authorID, content, excludeID string, since time.Time) (bool, error) {
+	return bool(d), nil
+}
+
+func TestBuiltinRules(t *testing.T) {
+	p, err := NewPipelineFromConfig(config.ModerationConfig{
+		BlockedWords:    []string{"Casino"},
+		BlockedPatterns: []string{`\d{4}-\d{4}`},
+		MaxLinks:        1,
+		DuplicateWindow: time.Minute,
+	}, duplicates(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		content string
+		want    Verdict
+	}{
+		{"hello", VerdictAccept},
+		{"best CASINO in town", VerdictReject},
+		{"call 1234-5678", VerdictReject},
+		{"see https://a.example", VerdictAccept},
+		{"see https://a.example and www.b.example", VerdictPending},
+	}
+	for _, tc := range cases {
+		d, err := p.Check(context.Background(), Input{Content: tc.content})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.Verdict != tc.want {
+			t.Errorf("%q: verdict = %d (%s), want %d", tc.content, d.Verdict, d.Reason, tc.want)
+		}
+	}
+
+	dup, _ := NewPipelineFromConfig(config.ModerationConfig{DuplicateWindow: time.Minute}, duplicates(true))
+	if d, _ := dup.Check(context.Background(), Input{Content: "again"}); d.Verdict != VerdictReject {
+		t.Errorf("duplicate verdict = %d, want reject", d.Verdict)
+	}
+}
+
+func TestInvalidPattern(t *testing.T) {
+	if _, err := NewPipelineFromConfig(config.ModerationConfig{BlockedPatterns: []string{"("}}, nil); err == nil {
+		t.Fatal("invalid pattern accepted")
+	}
+}
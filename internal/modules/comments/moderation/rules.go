@@ -0,0 +1,102 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// WordBlocklist отклоняет комментарии, содержащие запрещённые слова (без учёта регистра)
+type WordBlocklist struct {
+	words []string
+}
+
+func NewWordBlocklist(words []string) *WordBlocklist {
+	lowered := make([]string, 0, len(words))
+	for _, w := range words {
+		lowered = append(lowered, strings.ToLower(w))
+	}
+	return &WordBlocklist{words: lowered}
+}
+
+func (b *WordBlocklist) Check(_ context.Context, in Input) (Decision, error) {
+	content := strings.ToLower(in.Content)
+	for _, w := range b.words {
+		if strings.Contains(content, w) {
+			return Decision{Verdict: VerdictReject, Reason: "blocked word"}, nil
+		}
+	}
+	return Accept, nil
+}
+
+// PatternBlocklist отклоняет комментарии, совпадающие с одним из регулярных выражений
+type PatternBlocklist struct {
+	patterns []*regexp.Regexp
+}
+
+func NewPatternBlocklist(patterns []string) (*PatternBlocklist, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid moderation pattern %q: %w", p, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return &PatternBlocklist{patterns: compiled}, nil
+}
+
+func (b *PatternBlocklist) Check(_ context.Context, in Input) (Decision, error) {
+	for _, re := range b.patterns {
+		if re.MatchString(in.Content) {
+			return Decision{Verdict: VerdictReject, Reason: "blocked pattern"}, nil
+		}
+	}
+	return Accept, nil
+}
+
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+
+// LinkLimit отправляет на премодерацию комментарии, в которых больше max ссылок
+type LinkLimit struct {
+	max int
+}
+
+func NewLinkLimit(max int) *LinkLimit {
+	return &LinkLimit{max: max}
+}
+
+func (l *LinkLimit) Check(_ context.Context, in Input) (Decision, error) {
+	if n := len(linkPattern.FindAllStringIndex(in.Content, -1)); n > l.max {
+		return Decision{Verdict: VerdictPending, Reason: fmt.Sprintf("too many links: %d > %d", n, l.max)}, nil
+	}
+	return Accept, nil
+}
+
+// DuplicateFinder ищет такой же комментарий того же автора, созданный после since
+type DuplicateFinder interface {
+	HasDuplicate(ctx context.Context, authorID, content, excludeID string, since time.Time) (bool, error)
+}
+
+// DuplicateContent отклоняет повторную отправку одного и того же текста автором в пределах окна
+type DuplicateContent struct {
+	finder DuplicateFinder
+	window time.Duration
+}
+
+func NewDuplicateContent(finder DuplicateFinder, window time.Duration) *DuplicateContent {
+	return &DuplicateContent{finder: finder, window: window}
+}
+
+func (d *DuplicateContent) Check(ctx context.Context, in Input) (Decision, error) {
+	dup, err := d.finder.HasDuplicate(ctx, in.AuthorID, in.Content, in.CommentID, time.Now().Add(-d.window))
+	if err != nil {
+		return Decision{}, err
+	}
+	if dup {
+		return Decision{Verdict: VerdictReject, Reason: "duplicate content"}, nil
+	}
+	return Accept, nil
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+// ListAnchored возвращает привязанные к документу комментарии треда с ответами.
+// blockIDs ограничивает выборку блоками документа, пустой список — все блоки.
+// Комментарии упорядочены по блоку и началу диапазона, ответы всех уровней
+// собраны в Replies своего якорного комментария в порядке создания
+func (r *CommentRepo) ListAnchored(ctx context.Context, threadID string, blockIDs []string, opts model.ThreadOptions) ([]model.Comment, error) {
+	rows, err := r.db.Query(ctx, `
+		WITH RECURSIVE sub AS (
+			SELECT id AS cid, id AS anchor_root
+			FROM comments
+			WHERE thread_id = $1 AND anchor IS NOT NULL AND `+publicFilter+`
+			  AND (cardinality($2::text[]) = 0 OR anchor->>'block_id' = ANY($2))
+			  AND `+tenantFilter("tenant_id", 4)+`
+			UNION ALL
+			SELECT c.id, s.anchor_root
+			FROM comments c
+			JOIN sub s ON c.answer_comment_id = s.cid
+			WHERE c.thread_id = $1 AND `+publicFilter+`
+		)
+		SELECT `+readColumns+`, `+viewerVoteColumn(3)+`, anchor_root
+		FROM sub
+		JOIN comments ON comments.id = sub.cid
+		ORDER BY created_at ASC, id
+	`, threadID, blockIDs, opts.ViewerID, tenant.ID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var all []model.Comment
+	rootOf := map[string]string{}
+	for rows.Next() {
+		var c model.Comment
+		var root string
+		if err := scanRead(rows, &c, &c.ViewerVote, &root); err != nil {
+			return nil, err
+		}
+		rootOf[c.ID] = root
+		all = append(all, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	resolved, err := r.resolutions(ctx, []string{threadID})
+	if err != nil {
+		return nil, err
+	}
+	applyResolution(all, resolved[threadID])
+
+	index := map[string]int{}
+	roots := []model.Comment{}
+	replies := map[string][]model.Comment{}
+	for _, c := range all {
+		if rootOf[c.ID] == c.ID {
+			c.Replies = []model.Comment{}
+			index[c.ID] = len(roots)
+			roots = append(roots, c)
+			continue
+		}
+		replies[rootOf[c.ID]] = append(replies[rootOf[c.ID]], c)
+	}
+	for id, rs := range replies {
+		root := &roots[index[id]]
+		sortReplies(rs, opts.Sort)
+		root.Replies = rs
+		root.RepliesCount = len(rs)
+	}
+
+	sortAnchored(roots)
+	return roots, nil
+}
+
+// RemapAnchors переносит якоря треда после изменения документа: сначала переносы
+// блоков со сдвигом диапазонов, затем точечная замена якорей комментариев.
+// Всё выполняется в одной транзакции. Якорь — служебные данные, поэтому версия
+// комментария не меняется и в audit не пишется. Возвращает число изменённых якорей
+func (r *CommentRepo) RemapAnchors(ctx context.Context, threadID string, remap model.AnchorRemap) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var total int64
+	for _, b := range remap.Blocks {
+		to := b.To
+		if to == "" {
+			to = b.From
+		}
+		tag, err := tx.Exec(ctx, `
+			UPDATE comments
+			SET anchor = anchor || jsonb_build_object(
+				'block_id', $3::text,
+				'start', GREATEST(0, (anchor->>'start')::int + $4),
+				'end', GREATEST(0, (anchor->>'end')::int + $4))
+			WHERE thread_id = $1 AND anchor IS NOT NULL AND anchor->>'block_id' = $2
+			  AND `+tenantFilter("tenant_id", 5)+`
+		`, threadID, b.From, to, b.OffsetDelta, tenant.ID(ctx))
+		if err != nil {
+			return 0, err
+		}
+		total += tag.RowsAffected()
+	}
+
+	for _, u := range remap.Anchors {
+		anchor := u.Anchor
+		tag, err := tx.Exec(ctx, `
+			UPDATE comments SET anchor = $3
+			WHERE id = $1 AND thread_id = $2 AND anchor IS NOT NULL AND `+tenantFilter("tenant_id", 4)+`
+		`, u.CommentID, threadID, &anchor, tenant.ID(ctx))
+		if err != nil {
+			return 0, err
+		}
+		total += tag.RowsAffected()
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// sortAnchored упорядочивает якорные комментарии по положению в документе
+func sortAnchored(roots []model.Comment) {
+	sort.SliceStable(roots, func(i, j int) bool {
+		a, b := roots[i].Anchor, roots[j].Anchor
+		if a.BlockID != b.BlockID {
+			return a.BlockID < b.BlockID
+		}
+		if a.Start != b.Start {
+			return a.Start < b.Start
+		}
+		return a.End < b.End
+	})
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pksep/comments/internal/db"
+	auditModel "github.com/pksep/comments/internal/modules/audit/model"
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/shared/requestmeta"
+)
+
+// AuditRecorder пишет запись журнала аудита в переданную транзакцию
+type AuditRecorder interface {
+	Record(ctx context.Context, q db.Querier, entry *auditModel.Entry) error
+}
+
+// recordAudit фиксирует изменение комментария: кто, что сделал, снимки до и после
+// и сведения о запросе из контекста
+func (r *CommentRepo) recordAudit(ctx context.Context, q db.Querier, action auditModel.Action, actorID string, before, after *model.Comment) error {
+	if r.audit == nil {
+		return nil
+	}
+
+	entry := &auditModel.Entry{
+		ActorID: actorID,
+		Action:  action,
+	}
+
+	for _, snap := range []struct {
+		c   *model.Comment
+		dst *json.RawMessage
+	}{{before, &entry.Before}, {after, &entry.After}} {
+		if snap.c == nil {
+			continue
+		}
+		entry.CommentID = snap.c.ID
+		entry.ThreadID = snap.c.ThreadID
+		raw, err := json.Marshal(snap.c)
+		if err != nil {
+			return err
+		}
+		*snap.dst = raw
+	}
+
+	meta := requestmeta.FromContext(ctx)
+	entry.RequestID = meta.RequestID
+	entry.IP = meta.IP
+	entry.UserAgent = meta.UserAgent
+
+	return r.audit.Record(ctx, q, entry)
+}
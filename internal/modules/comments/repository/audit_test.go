@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/pksep/comments/internal/db"
+	auditModel "github.com/pksep/comments/internal/modules/audit/model"
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/shared/requestmeta"
+)
+
+type auditRecorder struct{ entries []*auditModel.Entry }
+
+func (r *auditRecorder) Record(ctx context.Context, q db.Querier, entry *auditModel.Entry) error {
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func TestRecordAuditSnapshots(t *testing.T) {
+	rec := &auditRecorder{}
+	repo := &CommentRepo{audit: rec}
+	ctx := requestmeta.WithMeta(context.Background(), requestmeta.Meta{RequestID: "req-1", IP: "10.0.0.1", UserAgent: "test"})
+	thread := "t1"
+	before := &model.Comment{ID: "c1", ThreadID: &thread, Content: "old", Status: model.CommentStatusCreated}
+	after := &model.Comment{ID: "c1", ThreadID: &thread, Content: "new", Status: model.CommentStatusEdited}
+
+	if err := repo.recordAudit(ctx, nil, auditModel.ActionUpdate, "u1", before, after); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.recordAudit(ctx, nil, auditModel.ActionCreate, "u1", nil, before); err != nil {
+		t.Fatal(err)
+	}
+
+	e := rec.entries[0]
+	if e.CommentID != "c1" || e.ThreadID == nil || *e.ThreadID != "t1" || e.ActorID != "u1" || e.Action != auditModel.ActionUpdate {
+		t.Fatalf("entry = %+v", e)
+	}
+	if e.RequestID != "req-1" || e.IP != "10.0.0.1" || e.UserAgent != "test" {
+		t.Fatalf("request meta = %q %q %q", e.RequestID, e.IP, e.UserAgent)
+	}
+	var gotBefore, gotAfter model.Comment
+	if err := json.Unmarshal(e.Before, &gotBefore); err != nil || gotBefore.Content != "old" {
+		t.Fatalf("before = %s (%v)", e.Before, err)
+	}
+	if err := json.Unmarshal(e.After, &gotAfter); err != nil || gotAfter.Content != "new" {
+		t.Fatalf("after = %s (%v)", e.After, err)
+	}
+
+	if created := rec.entries[1]; created.Before != nil || created.After == nil {
+		t.Fatalf("create entry: before = %s, after = %s", created.Before, created.After)
+	}
+}
+
+func TestRecordAuditDisabled(t *testing.T) {
+	repo := &CommentRepo{}
+	if err := repo.recordAudit(context.Background(), nil, auditModel.ActionCreate, "u1", nil, &model.Comment{ID: "c1"}); err != nil {
+		t.Fatal(err)
+	}
+}
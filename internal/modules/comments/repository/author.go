@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+// ListByAuthor возвращает комментарии автора, новые первыми, вместе со сведениями о треде.
+// Использует индекс (author_id, created_at)
+func (r *CommentRepo) ListByAuthor(ctx context.Context, filter model.AuthorFilter) ([]model.AuthorComment, error) {
+	statuses := filter.Statuses
+	if len(statuses) == 0 {
+		statuses = []model.CommentStatus{model.CommentStatusCreated, model.CommentStatusEdited}
+	}
+	statusArgs := make([]string, 0, len(statuses))
+	for _, st := range statuses {
+		statusArgs = append(statusArgs, string(st))
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT c.id, c.author_id, c.content, c.thread_id, c.answer_comment_id, c.status, c.version,
+		       c.upvotes, c.downvotes, c.score, c.created_at, c.updated_at,
+		       root.id, COALESCE(ts.comment_count, 0), ts.last_comment_at
+		FROM comments c
+		LEFT JOIN thread_stats ts ON ts.thread_id = c.thread_id
+		LEFT JOIN LATERAL (
+			SELECT id FROM comments
+			WHERE thread_id = c.thread_id
+			ORDER BY created_at ASC
+			LIMIT 1
+		) root ON TRUE
+		WHERE c.author_id = $1 AND c.status = ANY($2) AND `+tenantFilter("c.tenant_id", 5)+`
+		ORDER BY c.created_at DESC, c.id DESC
+		LIMIT $3 OFFSET $4
+	`, filter.AuthorID, statusArgs, filter.Limit, filter.Offset, tenant.ID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []model.AuthorComment{}
+	for rows.Next() {
+		var it model.AuthorComment
+		var ref model.ThreadRef
+		if err := rows.Scan(
+			&it.ID, &it.AuthorID, &it.Content, &it.ThreadID, &it.AnswerCommentID, &it.Status, &it.Version,
+			&it.Upvotes, &it.Downvotes, &it.Score, &it.CreatedAt, &it.UpdatedAt,
+			&ref.RootCommentID, &ref.CommentCount, &ref.LastCommentAt,
+		); err != nil {
+			return nil, err
+		}
+		it.Replies = []model.Comment{}
+		if it.ThreadID != nil {
+			ref.ID = *it.ThreadID
+			it.Thread = &ref
+		}
+		items = append(items, it)
+	}
+	return items, rows.Err()
+}
@@ -0,0 +1,414 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/pksep/comments/internal/cache"
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+	"golang.org/x/sync/singleflight"
+)
+
+// errGenerationLost — поколение исчезло между Add и Get; чтение идёт мимо кэша
+var errGenerationLost = errors.New("cache generation expired concurrently")
+
+const (
+	cachePrefix = "comments:"
+	// globalGenKey — поколение всех тредов; меняется, когда затронутые треды неизвестны
+	globalGenKey = cachePrefix + "gen"
+	// invalidateTimeout — предел на инвалидацию, которая переживает отмену запроса
+	invalidateTimeout = 2 * time.Second
+	// loadTimeout — предел на общую загрузку промаха: она не зависит от запроса,
+	// который её начал, чтобы его отмена не вернула ошибку остальным ожидающим
+	loadTimeout = 10 * time.Second
+)
+
+// CachedCommentRepo кэширует чтение тредов (GetByID и ListWithReplies) поверх
+// CommentRepoInterface. Ключ записи включает поколение каждого треда, а запись
+// меняет поколение затронутых тредов, поэтому старые записи просто перестают читаться.
+// Ответы с голосом читателя (ViewerID) персональные и не кэшируются
+type CachedCommentRepo struct {
+	CommentRepoInterface
+	cache   cache.Cache
+	ttl     time.Duration
+	metrics *cache.Metrics
+	loads   singleflight.Group
+}
+
+// NewCachedCommentRepo оборачивает repo кэшем; ttl — срок жизни записи
+func NewCachedCommentRepo(repo CommentRepoInterface, c cache.Cache, ttl time.Duration, metrics *cache.Metrics) *CachedCommentRepo {
+	return &CachedCommentRepo{CommentRepoInterface: repo, cache: c, ttl: ttl, metrics: metrics}
+}
+
+func (r *CachedCommentRepo) GetByID(ctx context.Context, threadID string, opts model.ThreadOptions) (*model.Comment, error) {
+	if opts.ViewerID != "" {
+		return r.CommentRepoInterface.GetByID(ctx, threadID, opts)
+	}
+	gens, err := r.generations(ctx, []string{threadID})
+	if err != nil {
+		return r.CommentRepoInterface.GetByID(ctx, threadID, opts)
+	}
+
+	key := cachePrefix + "thread:" + tenant.ID(ctx) + ":" + threadID + ":" + strings.Join(gens, ":") + ":" + string(opts.Sort)
+	// Пустой тред кэшируется как null и читается обратно как nil: первый комментарий
+	// меняет поколение треда, поэтому такая запись не переживает его появления
+	var c *model.Comment
+	err = r.read(ctx, key, &c, func(ctx context.Context) (any, error) {
+		return r.CommentRepoInterface.GetByID(ctx, threadID, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *CachedCommentRepo) ListWithReplies(ctx context.Context, ids []string, opts model.ListOptions) ([]model.Comment, error) {
+	if opts.ViewerID != "" || len(ids) == 0 {
+		return r.CommentRepoInterface.ListWithReplies(ctx, ids, opts)
+	}
+	gens, err := r.generations(ctx, ids)
+	if err != nil {
+		return r.CommentRepoInterface.ListWithReplies(ctx, ids, opts)
+	}
+
+	// Набор id и параметры не ограничены по длине, поэтому в ключ идёт их хэш
+	params, _ := json.Marshal(struct {
+		IDs  []string
+		Gens []string
+		Opts model.ListOptions
+	}{ids, gens, opts})
+	sum := sha256.Sum256(params)
+	key := cachePrefix + "list:" + tenant.ID(ctx) + ":" + hex.EncodeToString(sum[:])
+
+	var comments []model.Comment
+	err = r.read(ctx, key, &comments, func(ctx context.Context) (any, error) {
+		return r.CommentRepoInterface.ListWithReplies(ctx, ids, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// read отдаёт запись из кэша или загружает её через load. Параллельные промахи
+// по одному ключу ждут одну загрузку; она идёт в собственном контексте, а каждый
+// ожидающий прекращает ждать при отмене своего запроса. Ошибки кэша не мешают
+// чтению из базы
+func (r *CachedCommentRepo) read(ctx context.Context, key string, dst any, load func(ctx context.Context) (any, error)) error {
+	data, ok, err := r.cache.Get(ctx, key)
+	if err != nil {
+		r.cacheError("чтения", err)
+	}
+	if ok && json.Unmarshal(data, dst) == nil {
+		r.metrics.Hit()
+		return nil
+	}
+	r.metrics.Miss()
+
+	leader := false
+	loadCtx := context.WithoutCancel(ctx)
+	ch := r.loads.DoChan(key, func() (any, error) {
+		leader = true
+		ctx, cancel := context.WithTimeout(loadCtx, loadTimeout)
+		defer cancel()
+
+		value, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.cache.Set(ctx, key, data, r.ttl); err != nil {
+			r.cacheError("записи", err)
+		}
+		return data, nil
+	})
+
+	var res singleflight.Result
+	select {
+	case res = <-ch:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if res.Err != nil {
+		return res.Err
+	}
+	if res.Shared && !leader {
+		r.metrics.Shared()
+	}
+	return json.Unmarshal(res.Val.([]byte), dst)
+}
+
+// generations возвращает глобальное поколение и поколения тредов. Пропавшее из кэша
+// поколение заменяется новым случайным, чтобы не совпасть ни с одним прежним; Add
+// гарантирует, что параллельные читатели сойдутся на одном поколении
+func (r *CachedCommentRepo) generations(ctx context.Context, threadIDs []string) ([]string, error) {
+	keys := make([]string, 0, len(threadIDs)+1)
+	keys = append(keys, globalGenKey)
+	for _, id := range threadIDs {
+		keys = append(keys, threadGenKey(id))
+	}
+
+	values, err := r.cache.GetMulti(ctx, keys)
+	if err != nil {
+		r.cacheError("чтения", err)
+		return nil, err
+	}
+	gens := make([]string, len(keys))
+	for i, v := range values {
+		if v != nil {
+			gens[i] = string(v)
+			continue
+		}
+		gen := newGeneration()
+		added, err := r.cache.Add(ctx, keys[i], []byte(gen), r.generationTTL())
+		if err == nil && !added {
+			var ok bool
+			v, ok, err = r.cache.Get(ctx, keys[i])
+			if err == nil && !ok {
+				err = errGenerationLost
+			}
+			gen = string(v)
+		}
+		if err != nil {
+			r.cacheError("записи", err)
+			return nil, err
+		}
+		gens[i] = gen
+	}
+	return gens, nil
+}
+
+// invalidate меняет поколение тредов; пустой список меняет глобальное поколение
+func (r *CachedCommentRepo) invalidate(ctx context.Context, threadIDs ...string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), invalidateTimeout)
+	defer cancel()
+
+	keys := []string{globalGenKey}
+	if len(threadIDs) > 0 {
+		keys = keys[:0]
+		for _, id := range threadIDs {
+			keys = append(keys, threadGenKey(id))
+		}
+	}
+	for _, key := range keys {
+		if err := r.cache.Set(ctx, key, []byte(newGeneration()), r.generationTTL()); err != nil {
+			r.cacheError("инвалидации", err)
+			continue
+		}
+		r.metrics.Invalidation()
+	}
+}
+
+// invalidateComment меняет поколение треда комментария
+func (r *CachedCommentRepo) invalidateComment(ctx context.Context, c *model.Comment) {
+	if c != nil && c.ThreadID != nil {
+		r.invalidate(ctx, *c.ThreadID)
+	}
+}
+
+// invalidateCommentIDs находит треды комментариев и меняет их поколение
+func (r *CachedCommentRepo) invalidateCommentIDs(ctx context.Context, ids ...string) {
+	threads, err := r.CommentRepoInterface.ThreadsOf(ctx, ids)
+	if err != nil {
+		log.Printf("Ошибка поиска тредов для инвалидации кэша, сбрасывается весь кэш: %v", err)
+		r.invalidate(ctx)
+		return
+	}
+	for _, threadID := range threads {
+		if threadID != nil {
+			r.invalidate(ctx, *threadID)
+		}
+	}
+}
+
+// generationTTL — поколение живёт дольше записей, которые на него ссылаются
+func (r *CachedCommentRepo) generationTTL() time.Duration {
+	return 2 * r.ttl
+}
+
+func (r *CachedCommentRepo) cacheError(op string, err error) {
+	r.metrics.Error()
+	log.Printf("Ошибка %s кэша комментариев: %v", op, err)
+}
+
+func threadGenKey(threadID string) string {
+	return cachePrefix + "gen:" + threadID
+}
+
+func newGeneration() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+func (r *CachedCommentRepo) Create(ctx context.Context, comment *model.Comment) (*model.Comment, error) {
+	c, err := r.CommentRepoInterface.Create(ctx, comment)
+	if err == nil {
+		r.invalidateComment(ctx, c)
+	}
+	return c, err
+}
+
+func (r *CachedCommentRepo) Update(ctx context.Context, id string, content string, authorId string, status model.CommentStatus, reason *string, expectedVersion *int) (*model.Comment, error) {
+	c, err := r.CommentRepoInterface.Update(ctx, id, content, authorId, status, reason, expectedVersion)
+	if err == nil {
+		r.invalidateComment(ctx, c)
+	}
+	return c, err
+}
+
+func (r *CachedCommentRepo) Delete(ctx context.Context, id string, authorId string, expectedVersion *int) (*model.Comment, error) {
+	c, err := r.CommentRepoInterface.Delete(ctx, id, authorId, expectedVersion)
+	if err == nil {
+		r.invalidateComment(ctx, c)
+	}
+	return c, err
+}
+
+func (r *CachedCommentRepo) Approve(ctx context.Context, id, moderatorID string, reason *string) (*model.Comment, error) {
+	c, err := r.CommentRepoInterface.Approve(ctx, id, moderatorID, reason)
+	if err == nil {
+		r.invalidateComment(ctx, c)
+	}
+	return c, err
+}
+
+func (r *CachedCommentRepo) Hide(ctx context.Context, id, moderatorID, reason string) (*model.Comment, error) {
+	c, err := r.CommentRepoInterface.Hide(ctx, id, moderatorID, reason)
+	if err == nil {
+		r.invalidateComment(ctx, c)
+	}
+	return c, err
+}
+
+func (r *CachedCommentRepo) ModeratorDelete(ctx context.Context, id, moderatorID, reason string) (*model.Comment, error) {
+	c, err := r.CommentRepoInterface.ModeratorDelete(ctx, id, moderatorID, reason)
+	if err == nil {
+		r.invalidateComment(ctx, c)
+	}
+	return c, err
+}
+
+func (r *CachedCommentRepo) Restore(ctx context.Context, id, moderatorID, reason string) (*model.Comment, error) {
+	c, err := r.CommentRepoInterface.Restore(ctx, id, moderatorID, reason)
+	if err == nil {
+		r.invalidateComment(ctx, c)
+	}
+	return c, err
+}
+
+func (r *CachedCommentRepo) Pin(ctx context.Context, id, actorID string, maxPinned int) (*model.Comment, error) {
+	c, err := r.CommentRepoInterface.Pin(ctx, id, actorID, maxPinned)
+	if err == nil {
+		r.invalidateComment(ctx, c)
+	}
+	return c, err
+}
+
+func (r *CachedCommentRepo) Unpin(ctx context.Context, id, actorID string) (*model.Comment, error) {
+	c, err := r.CommentRepoInterface.Unpin(ctx, id, actorID)
+	if err == nil {
+		r.invalidateComment(ctx, c)
+	}
+	return c, err
+}
+
+func (r *CachedCommentRepo) Reschedule(ctx context.Context, id, authorID string, publishAt time.Time, expectedVersion *int) (*model.Comment, error) {
+	c, err := r.CommentRepoInterface.Reschedule(ctx, id, authorID, publishAt, expectedVersion)
+	if err == nil {
+		r.invalidateComment(ctx, c)
+	}
+	return c, err
+}
+
+func (r *CachedCommentRepo) Vote(ctx context.Context, vote model.Vote) (*model.VoteResult, error) {
+	res, err := r.CommentRepoInterface.Vote(ctx, vote)
+	if err == nil {
+		r.invalidateCommentIDs(ctx, vote.CommentID)
+	}
+	return res, err
+}
+
+// Report меняет видимое состояние треда, только если жалоба может скрыть комментарий
+func (r *CachedCommentRepo) Report(ctx context.Context, report *model.Report, autoHideThreshold int) (*model.Report, error) {
+	res, err := r.CommentRepoInterface.Report(ctx, report, autoHideThreshold)
+	if err == nil && autoHideThreshold > 0 {
+		r.invalidateCommentIDs(ctx, report.CommentID)
+	}
+	return res, err
+}
+
+func (r *CachedCommentRepo) Accept(ctx context.Context, id, actorID string) (*model.Resolution, error) {
+	res, err := r.CommentRepoInterface.Accept(ctx, id, actorID)
+	if err == nil {
+		r.invalidate(ctx, res.ThreadID)
+	}
+	return res, err
+}
+
+func (r *CachedCommentRepo) Unaccept(ctx context.Context, threadID, actorID string) (*model.Resolution, error) {
+	res, err := r.CommentRepoInterface.Unaccept(ctx, threadID, actorID)
+	if err == nil {
+		r.invalidate(ctx, threadID)
+	}
+	return res, err
+}
+
+func (r *CachedCommentRepo) RemapAnchors(ctx context.Context, threadID string, remap model.AnchorRemap) (int64, error) {
+	n, err := r.CommentRepoInterface.RemapAnchors(ctx, threadID, remap)
+	if err == nil && n > 0 {
+		r.invalidate(ctx, threadID)
+	}
+	return n, err
+}
+
+func (r *CachedCommentRepo) ImportBatch(ctx context.Context, comments []model.Comment) error {
+	if err := r.CommentRepoInterface.ImportBatch(ctx, comments); err != nil {
+		return err
+	}
+	seen := map[string]bool{}
+	for _, c := range comments {
+		if c.ThreadID != nil && !seen[*c.ThreadID] {
+			seen[*c.ThreadID] = true
+			r.invalidate(ctx, *c.ThreadID)
+		}
+	}
+	return nil
+}
+
+// PublishDue сбрасывает треды опубликованных комментариев
+func (r *CachedCommentRepo) PublishDue(ctx context.Context, limit int) ([]model.Comment, error) {
+	published, err := r.CommentRepoInterface.PublishDue(ctx, limit)
+	for i := range published {
+		r.invalidateComment(ctx, &published[i])
+	}
+	return published, err
+}
+
+// AnonymizeAuthorBatch затрагивает все треды автора, поэтому сбрасывает все треды
+func (r *CachedCommentRepo) AnonymizeAuthorBatch(ctx context.Context, authorID, pseudonym, actorID string, limit int) (int, error) {
+	n, err := r.CommentRepoInterface.AnonymizeAuthorBatch(ctx, authorID, pseudonym, actorID, limit)
+	if err == nil && n > 0 {
+		r.invalidate(ctx)
+	}
+	return n, err
+}
+
+func (r *CachedCommentRepo) AnonymizeReferences(ctx context.Context, authorID, pseudonym string) error {
+	err := r.CommentRepoInterface.AnonymizeReferences(ctx, authorID, pseudonym)
+	if err == nil {
+		r.invalidate(ctx)
+	}
+	return err
+}
@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pksep/comments/internal/cache"
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+// threadStore — тред в памяти вместо базы; считает чтения
+type threadStore struct {
+	CommentRepoInterface
+	threads      map[string]*model.Comment
+	reads        int
+	threadsOfErr error
+}
+
+func (s *threadStore) GetByID(ctx context.Context, threadID string, opts model.ThreadOptions) (*model.Comment, error) {
+	s.reads++
+	root, ok := s.threads[threadID]
+	if !ok {
+		return nil, nil
+	}
+	c := *root
+	return &c, nil
+}
+
+func (s *threadStore) Create(ctx context.Context, c *model.Comment) (*model.Comment, error) {
+	created := *c
+	s.threads[*c.ThreadID] = &created
+	return &created, nil
+}
+
+func (s *threadStore) Update(ctx context.Context, id, content, authorID string, status model.CommentStatus, reason *string, expectedVersion *int) (*model.Comment, error) {
+	for _, root := range s.threads {
+		if root.ID == id {
+			root.Content = content
+			c := *root
+			return &c, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Vote и PublishDue меняют только данные; инвалидацию проверяют по числу чтений
+func (s *threadStore) Vote(ctx context.Context, vote model.Vote) (*model.VoteResult, error) {
+	return &model.VoteResult{CommentID: vote.CommentID}, nil
+}
+
+// PublishDue публикует ответ в первый тред
+func (s *threadStore) PublishDue(ctx context.Context, limit int) ([]model.Comment, error) {
+	for threadID := range s.threads {
+		return []model.Comment{{ID: "scheduled", ThreadID: &threadID}}, nil
+	}
+	return nil, nil
+}
+
+// ThreadsOf находит тред комментария по id корня
+func (s *threadStore) ThreadsOf(ctx context.Context, ids []string) (map[string]*string, error) {
+	if s.threadsOfErr != nil {
+		return nil, s.threadsOfErr
+	}
+	out := map[string]*string{}
+	for threadID, root := range s.threads {
+		for _, id := range ids {
+			if root.ID == id {
+				out[id] = &threadID
+			}
+		}
+	}
+	return out, nil
+}
+
+func newCachedStore() (*CachedCommentRepo, *threadStore, *cache.Metrics) {
+	store := &threadStore{threads: map[string]*model.Comment{}}
+	metrics := &cache.Metrics{}
+	return NewCachedCommentRepo(store, cache.NewMemory(0), time.Minute, metrics), store, metrics
+}
+
+func TestCachedGetByIDEmptyThread(t *testing.T) {
+	ctx := context.Background()
+	repo, store, _ := newCachedStore()
+	threadID := "thread-1"
+
+	for i := 0; i < 2; i++ {
+		c, err := repo.GetByID(ctx, threadID, model.ThreadOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c != nil {
+			t.Fatalf("read %d: got %+v, want nil for an empty thread", i, c)
+		}
+	}
+	if store.reads != 1 {
+		t.Fatalf("store reads = %d, want 1: empty result should be cached", store.reads)
+	}
+
+	// Первый комментарий делает закэшированную пустоту недействительной
+	if _, err := repo.Create(ctx, &model.Comment{ID: "c1", ThreadID: &threadID, Content: "first"}); err != nil {
+		t.Fatal(err)
+	}
+	c, err := repo.GetByID(ctx, threadID, model.ThreadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c == nil || c.Content != "first" {
+		t.Fatalf("got %+v after create, want the new comment", c)
+	}
+}
+
+func TestCachedGetByIDInvalidatedOnWrite(t *testing.T) {
+	ctx := context.Background()
+	repo, store, metrics := newCachedStore()
+	threadID := "thread-1"
+	store.threads[threadID] = &model.Comment{ID: "c1", ThreadID: &threadID, Content: "v1"}
+
+	for i := 0; i < 3; i++ {
+		if _, err := repo.GetByID(ctx, threadID, model.ThreadOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if store.reads != 1 {
+		t.Fatalf("store reads = %d, want 1", store.reads)
+	}
+	if stats := metrics.Snapshot(); stats.Hits != 2 || stats.Misses != 1 {
+		t.Fatalf("hits/misses = %d/%d, want 2/1", stats.Hits, stats.Misses)
+	}
+
+	if _, err := repo.Update(ctx, "c1", "v2", "", model.CommentStatusEdited, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	c, err := repo.GetByID(ctx, threadID, model.ThreadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Content != "v2" {
+		t.Fatalf("content = %q after update, want v2", c.Content)
+	}
+
+	// Голос читателя персональный: такие чтения идут мимо кэша
+	before := store.reads
+	if _, err := repo.GetByID(ctx, threadID, model.ThreadOptions{ViewerID: "u1"}); err != nil {
+		t.Fatal(err)
+	}
+	if store.reads != before+1 {
+		t.Fatal("viewer read was served from cache")
+	}
+}
+
+func TestCachedInvalidationScope(t *testing.T) {
+	ctx := context.Background()
+	repo, store, _ := newCachedStore()
+	a, b := "thread-a", "thread-b"
+	store.threads[a] = &model.Comment{ID: "ca", ThreadID: &a}
+	store.threads[b] = &model.Comment{ID: "cb", ThreadID: &b}
+
+	warm := func() {
+		repo.GetByID(ctx, a, model.ThreadOptions{})
+		repo.GetByID(ctx, b, model.ThreadOptions{})
+	}
+	// reloaded возвращает, сколько тредов пришлось перечитать из базы
+	reloaded := func() int {
+		before := store.reads
+		warm()
+		return store.reads - before
+	}
+	warm()
+
+	// Голос находит тред по id комментария и сбрасывает только его
+	if _, err := repo.Vote(ctx, model.Vote{CommentID: "ca", VoterID: "u1", Value: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if n := reloaded(); n != 1 {
+		t.Fatalf("after vote: %d threads reloaded, want only thread-a", n)
+	}
+
+	// Не нашли тред — сбрасывается весь кэш
+	store.threadsOfErr = errors.New("db down")
+	repo.Vote(ctx, model.Vote{CommentID: "ca", VoterID: "u1", Value: 1})
+	if n := reloaded(); n != 2 {
+		t.Fatalf("after failed lookup: %d threads reloaded, want all", n)
+	}
+
+	// Публикация по расписанию сбрасывает только треды опубликованных комментариев
+	if _, err := repo.PublishDue(ctx, 100); err != nil {
+		t.Fatal(err)
+	}
+	if n := reloaded(); n != 1 {
+		t.Fatalf("after PublishDue: %d threads reloaded, want one", n)
+	}
+	if n := reloaded(); n != 0 {
+		t.Fatalf("%d threads reloaded without writes", n)
+	}
+}
+
+func TestCachedGetByIDPerTenant(t *testing.T) {
+	repo, store, _ := newCachedStore()
+	threadID := "thread-1"
+	store.threads[threadID] = &model.Comment{ID: "c1", ThreadID: &threadID}
+
+	acme := tenant.WithTenant(context.Background(), tenant.Tenant{ID: "acme"})
+	globex := tenant.WithTenant(context.Background(), tenant.Tenant{ID: "globex"})
+	repo.GetByID(acme, threadID, model.ThreadOptions{})
+	repo.GetByID(globex, threadID, model.ThreadOptions{})
+	if store.reads != 2 {
+		t.Fatalf("store reads = %d, want one per tenant", store.reads)
+	}
+}
+
+// slowStore держит загрузку треда, пока тест её не отпустит
+type slowStore struct {
+	threadStore
+	started, release chan struct{}
+}
+
+func (s *slowStore) GetByID(ctx context.Context, threadID string, opts model.ThreadOptions) (*model.Comment, error) {
+	close(s.started)
+	<-s.release
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.threadStore.GetByID(ctx, threadID, opts)
+}
+
+func TestCachedLoadSurvivesLeaderCancel(t *testing.T) {
+	threadID := "thread-1"
+	store := &slowStore{
+		threadStore: threadStore{threads: map[string]*model.Comment{threadID: {ID: "c1", ThreadID: &threadID}}},
+		started:     make(chan struct{}),
+		release:     make(chan struct{}),
+	}
+	repo := NewCachedCommentRepo(store, cache.NewMemory(0), time.Minute, &cache.Metrics{})
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := repo.GetByID(leaderCtx, threadID, model.ThreadOptions{})
+		leaderErr <- err
+	}()
+	<-store.started
+
+	type result struct {
+		c   *model.Comment
+		err error
+	}
+	waiter := make(chan result, 1)
+	go func() {
+		c, err := repo.GetByID(context.Background(), threadID, model.ThreadOptions{})
+		waiter <- result{c, err}
+	}()
+
+	cancel()
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("leader err = %v, want its own cancellation", err)
+	}
+	close(store.release)
+
+	res := <-waiter
+	if res.err != nil || res.c == nil || res.c.ID != "c1" {
+		t.Fatalf("waiter got %+v, %v; want the thread despite the leader's cancel", res.c, res.err)
+	}
+}
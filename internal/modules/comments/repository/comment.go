@@ -9,52 +9,118 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	auditModel "github.com/pksep/comments/internal/modules/audit/model"
 	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
 )
 
 // CommentRepoInterface описывает методы работы с комментариями
 type CommentRepoInterface interface {
 	Create(ctx context.Context, comment *model.Comment) (*model.Comment, error)
-	GetByID(ctx context.Context, threadId string) (*model.Comment, error)
-	Update(ctx context.Context, id string, content string, authorId string) (*model.Comment, error)
-	Delete(ctx context.Context, id string, authorId string) (*model.Comment, error)
-	ListWithReplies(ctx context.Context, ids []string, replyLimit int) ([]model.Comment, error)
+	GetByID(ctx context.Context, threadId string, opts model.ThreadOptions) (*model.Comment, error)
+	ThreadVersion(ctx context.Context, threadID string) (*model.ThreadVersion, error)
+	GetComment(ctx context.Context, id string) (*model.Comment, error)
+	GetCommentContext(ctx context.Context, id string, ancestors, siblings int) (*model.CommentWithContext, error)
+	Update(ctx context.Context, id string, content string, authorId string, status model.CommentStatus, reason *string, expectedVersion *int) (*model.Comment, error)
+	Delete(ctx context.Context, id string, authorId string, expectedVersion *int) (*model.Comment, error)
+	ListWithReplies(ctx context.Context, ids []string, opts model.ListOptions) ([]model.Comment, error)
+	ListByAuthor(ctx context.Context, filter model.AuthorFilter) ([]model.AuthorComment, error)
+	StreamByAuthor(ctx context.Context, authorID string, fn func(model.Comment) error) error
+	CountByAuthor(ctx context.Context, authorID string) (int, error)
+	AnonymizeAuthorBatch(ctx context.Context, authorID, pseudonym, actorID string, limit int) (int, error)
+	AnonymizeReferences(ctx context.Context, authorID, pseudonym string) error
+	HasDuplicate(ctx context.Context, authorID, content, excludeID string, since time.Time) (bool, error)
+	StreamThread(ctx context.Context, threadID string, includeHidden bool, fn func(c model.Comment, depth int) error) error
+	ThreadsOf(ctx context.Context, ids []string) (map[string]*string, error)
+	ImportBatch(ctx context.Context, comments []model.Comment) error
+
+	Report(ctx context.Context, report *model.Report, autoHideThreshold int) (*model.Report, error)
+	ListModerationQueue(ctx context.Context, filter model.QueueFilter, limit, offset int) ([]model.QueueItem, error)
+	GetThreadForModerator(ctx context.Context, threadID string) (*model.Comment, error)
+	Approve(ctx context.Context, id, moderatorID string, reason *string) (*model.Comment, error)
+	Hide(ctx context.Context, id, moderatorID, reason string) (*model.Comment, error)
+	ModeratorDelete(ctx context.Context, id, moderatorID, reason string) (*model.Comment, error)
+	Restore(ctx context.Context, id, moderatorID, reason string) (*model.Comment, error)
+
+	Pin(ctx context.Context, id, actorID string, maxPinned int) (*model.Comment, error)
+	Unpin(ctx context.Context, id, actorID string) (*model.Comment, error)
+	Vote(ctx context.Context, vote model.Vote) (*model.VoteResult, error)
+	Reschedule(ctx context.Context, id, authorID string, publishAt time.Time, expectedVersion *int) (*model.Comment, error)
+	PublishDue(ctx context.Context, limit int) ([]model.Comment, error)
+	ListAnchored(ctx context.Context, threadID string, blockIDs []string, opts model.ThreadOptions) ([]model.Comment, error)
+	RemapAnchors(ctx context.Context, threadID string, remap model.AnchorRemap) (int64, error)
+	Accept(ctx context.Context, id, actorID string) (*model.Resolution, error)
+	Unaccept(ctx context.Context, threadID, actorID string) (*model.Resolution, error)
+}
+
+const (
+	// publicFilter — условие видимости комментария в публичном чтении:
+	// удалённые, скрытые и ожидающие модерации комментарии не отдаются
+	publicFilter = `deleted_at IS NULL AND status NOT IN ('pending', 'hidden', 'scheduled')`
+	// moderatorFilter — модераторам видно всё, кроме удалённого
+	moderatorFilter = `deleted_at IS NULL AND status <> 'scheduled'`
+
+	// readColumns — колонки комментария в операциях чтения, порядок совпадает со scanRead
+	readColumns = `id, author_id, content, thread_id, answer_comment_id, anchor, status, version, pinned_at, pinned_by,
+		upvotes, downvotes, score, created_at, updated_at`
+)
+
+// scanRead сканирует строку, выбранную по readColumns; extra — колонки, выбранные после них
+func scanRead(row pgx.Row, c *model.Comment, extra ...any) error {
+	dest := []any{&c.ID, &c.AuthorID, &c.Content, &c.ThreadID, &c.AnswerCommentID, &c.Anchor, &c.Status, &c.Version, &c.PinnedAt, &c.PinnedBy,
+		&c.Upvotes, &c.Downvotes, &c.Score, &c.CreatedAt, &c.UpdatedAt}
+	return row.Scan(append(dest, extra...)...)
+}
+
+// viewerVoteColumn — голос пользователя из параметра запроса $arg за комментарий строки.
+// Подзапрос идёт по первичному ключу comment_votes, отдельных запросов на комментарий нет
+func viewerVoteColumn(arg int) string {
+	return fmt.Sprintf(`(SELECT value FROM comment_votes
+		WHERE comment_votes.comment_id = comments.id AND comment_votes.voter_id = $%d)`, arg)
 }
 
 // CommentRepo — реализация репозитория комментариев
 type CommentRepo struct {
-	db *pgxpool.Pool
+	db    *pgxpool.Pool
+	audit AuditRecorder
 }
 
-// NewCommentRepo создаёт новый репозиторий
-func NewCommentRepo(db *pgxpool.Pool) *CommentRepo {
-	return &CommentRepo{db: db}
+// NewCommentRepo создаёт новый репозиторий. Каждое изменение комментария
+// записывается в audit в той же транзакции
+func NewCommentRepo(db *pgxpool.Pool, audit AuditRecorder) *CommentRepo {
+	return &CommentRepo{db: db, audit: audit}
 }
 
 func (r *CommentRepo) Create(ctx context.Context, comment *model.Comment) (*model.Comment, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	tenantID := tenant.IDOrDefault(ctx)
+
 	// 1. Ensure the comment has a ThreadID
 	if comment.ThreadID == nil {
 		threadID := uuid.New().String()
 		// Create a new thread
-		_, err := r.db.Exec(ctx, `INSERT INTO threads (id) VALUES ($1)`, threadID)
+		_, err := tx.Exec(ctx, `INSERT INTO threads (id, tenant_id) VALUES ($1, $2)`, threadID, tenantID)
 		if err != nil {
 			return nil, err
 		}
 		comment.ThreadID = &threadID
 	} else {
-		// Optional: check if the thread exists to avoid foreign key violation
-		var exists bool
-		err := r.db.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM threads WHERE id = $1)`, *comment.ThreadID).Scan(&exists)
-		if err != nil {
+		// Create the thread automatically if it does not exist yet
+		if err := ensureThread(ctx, tx, *comment.ThreadID, tenantID); err != nil {
 			return nil, err
 		}
-		if !exists {
-			// Create the thread automatically
-			_, err := r.db.Exec(ctx, `INSERT INTO threads (id) VALUES ($1)`, *comment.ThreadID)
-			if err != nil {
-				return nil, err
-			}
+	}
+
+	if comment.AnswerCommentID != nil {
+		if err := checkParent(ctx, tx, *comment.AnswerCommentID, *comment.ThreadID, tenantID); err != nil {
+			return nil, err
 		}
 	}
 
@@ -63,36 +129,110 @@ func (r *CommentRepo) Create(ctx context.Context, comment *model.Comment) (*mode
 	now := time.Now()
 	comment.CreatedAt = now
 	comment.UpdatedAt = now
+	if comment.Status == "" {
+		comment.Status = model.CommentStatusCreated
+	}
+	auditAction := auditModel.ActionCreate
+	if comment.Status == model.CommentStatusScheduled {
+		comment.CreatedAt = *comment.PublishAt
+		auditAction = auditModel.ActionSchedule
+	}
+	comment.Version = 1
 
 	// 3. Insert the comment
-	_, err := r.db.Exec(ctx,
+	_, err = tx.Exec(ctx,
 		`INSERT INTO comments
-            (id, author_id, content, thread_id, answer_comment_id, created_at, updated_at)
-         VALUES ($1,$2,$3,$4,$5,$6,$7)`,
+            (id, author_id, content, thread_id, answer_comment_id, anchor, status, moderation_reason, publish_at, created_at, updated_at, tenant_id)
+         VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)`,
 		comment.ID,
 		comment.AuthorID,
 		comment.Content,
 		comment.ThreadID,
 		comment.AnswerCommentID,
+		comment.Anchor,
+		comment.Status,
+		comment.ModerationReason,
+		comment.PublishAt,
 		comment.CreatedAt,
 		comment.UpdatedAt,
+		tenantID,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := applyThreadStats(ctx, tx, nil, comment); err != nil {
+		return nil, err
+	}
+
+	if comment.Status != model.CommentStatusScheduled {
+		if comment.FirstByAuthor, err = firstByAuthor(ctx, tx, comment); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.recordAudit(ctx, tx, auditAction, comment.AuthorID, nil, comment); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
 	return comment, nil
 }
 
+// checkParent проверяет, что родитель ответа есть в том же треде и тенанте:
+// иначе ответ ссылался бы на чужой тред, а контекст ответа раскрывал бы его
+func checkParent(ctx context.Context, tx pgx.Tx, parentID, threadID, tenantID string) error {
+	var exists bool
+	err := tx.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM comments WHERE id = $1 AND thread_id = $2 AND tenant_id = $3)
+	`, parentID, threadID, tenantID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrInvalidParent, parentID)
+	}
+	return nil
+}
+
 // GetByID возвращает комментарий по thread_id
-func (r *CommentRepo) GetByID(ctx context.Context, threadID string) (*model.Comment, error) {
+func (r *CommentRepo) GetByID(ctx context.Context, threadID string, opts model.ThreadOptions) (*model.Comment, error) {
+	return r.getThread(ctx, threadID, publicFilter, opts)
+}
+
+// GetComment возвращает один опубликованный комментарий по его id
+func (r *CommentRepo) GetComment(ctx context.Context, id string) (*model.Comment, error) {
+	var c model.Comment
+	err := scanRead(r.db.QueryRow(ctx, `
+		SELECT `+readColumns+`
+		FROM comments
+		WHERE id = $1 AND `+publicFilter+` AND `+tenantFilter("tenant_id", 2), id, tenant.ID(ctx)), &c)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	c.Replies = []model.Comment{}
+	return &c, nil
+}
+
+// GetThreadForModerator возвращает тред вместе со скрытыми и ожидающими модерации комментариями
+func (r *CommentRepo) GetThreadForModerator(ctx context.Context, threadID string) (*model.Comment, error) {
+	return r.getThread(ctx, threadID, moderatorFilter, model.ThreadOptions{})
+}
+
+func (r *CommentRepo) getThread(ctx context.Context, threadID string, filter string, opts model.ThreadOptions) (*model.Comment, error) {
 	query := `
-        SELECT id, author_id, content, thread_id, created_at, updated_at
+        SELECT ` + readColumns + `, ` + viewerVoteColumn(2) + `
         FROM comments
-        WHERE thread_id = $1 AND deleted_at IS NULL
+        WHERE thread_id = $1 AND ` + filter + ` AND ` + tenantFilter("tenant_id", 3) + `
         ORDER BY created_at ASC
     `
-	rows, err := r.db.Query(ctx, query, threadID)
+	rows, err := r.db.Query(ctx, query, threadID, opts.ViewerID, tenant.ID(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -101,7 +241,7 @@ func (r *CommentRepo) GetByID(ctx context.Context, threadID string) (*model.Comm
 	var comments []model.Comment
 	for rows.Next() {
 		var c model.Comment
-		if err := rows.Scan(&c.ID, &c.AuthorID, &c.Content, &c.ThreadID, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		if err := scanRead(rows, &c, &c.ViewerVote); err != nil {
 			return nil, err
 		}
 		c.Replies = []model.Comment{}
@@ -113,52 +253,70 @@ func (r *CommentRepo) GetByID(ctx context.Context, threadID string) (*model.Comm
 		return nil, nil // no comments for this thread
 	}
 
+	resolved, err := r.resolutions(ctx, []string{threadID})
+	if err != nil {
+		return nil, err
+	}
+	applyResolution(comments, resolved[threadID])
+
 	// First one (oldest) is root
 	root := comments[0]
+	root.Pinned = pinnedOf(comments)
 
 	// Replies are everything after the root
 	if len(comments) > 1 {
 		root.Replies = comments[1:]
 		root.RepliesCount = len(comments) - 1
+		sortReplies(root.Replies, opts.Sort)
 	}
 
 	return &root, nil
 }
 
-// Update обновляет комментарий
-func (r *CommentRepo) Update(ctx context.Context, id string, content string, authorId string) (*model.Comment, error) {
-	// Проверяем существование комментария и авторство
-	var dbAuthor string
-	err := r.db.QueryRow(ctx, `
-        SELECT author_id 
-        FROM comments 
-        WHERE id = $1
-    `, id).Scan(&dbAuthor)
+// Update обновляет комментарий. status — итоговый статус после модерации
+// (edited или pending), reason — причина отправки на премодерацию.
+// Если expectedVersion задан и не совпадает с текущей версией, возвращается *VersionConflictError
+func (r *CommentRepo) Update(ctx context.Context, id string, content string, authorId string, status model.CommentStatus, reason *string, expectedVersion *int) (*model.Comment, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
 
+	// Проверяем существование комментария и авторство
+	before, err := lockComment(ctx, tx, id)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("comment with ID %s not found", id)
+		if errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("comment with ID %s not found: %w", id, ErrNotFound)
 		}
 		return nil, err
 	}
 
-	if dbAuthor != authorId {
-		return nil, fmt.Errorf("only the author can edit this comment")
+	if before.AuthorID != authorId {
+		return nil, fmt.Errorf("only the author can edit this comment: %w", ErrForbidden)
+	}
+
+	if err := checkVersion(before, expectedVersion); err != nil {
+		return nil, err
 	}
 
 	// Обновляем только content и сразу возвращаем полный комментарий
+	status, reason = before.AfterEdit(status, reason)
 	updatedComment := &model.Comment{}
-	err = r.db.QueryRow(ctx, `
+	err = tx.QueryRow(ctx, `
         UPDATE comments
-        SET content = $1, status = $2, updated_at = $3
-        WHERE id = $4
-        RETURNING id, content, author_id, status, thread_id, created_at, updated_at
-    `, content, model.CommentStatusEdited, time.Now(), id).Scan(
+        SET content = $1, status = $2, moderation_reason = $3, updated_at = $4, version = version + 1
+        WHERE id = $5
+        RETURNING id, content, author_id, status, moderation_reason, thread_id, publish_at, version, created_at, updated_at
+    `, content, status, reason, time.Now(), id).Scan(
 		&updatedComment.ID,
 		&updatedComment.Content,
 		&updatedComment.AuthorID,
 		&updatedComment.Status,
+		&updatedComment.ModerationReason,
 		&updatedComment.ThreadID,
+		&updatedComment.PublishAt,
+		&updatedComment.Version,
 		&updatedComment.CreatedAt,
 		&updatedComment.UpdatedAt,
 	)
@@ -166,125 +324,217 @@ func (r *CommentRepo) Update(ctx context.Context, id string, content string, aut
 		return nil, err
 	}
 
+	if err := applyThreadStats(ctx, tx, before, updatedComment); err != nil {
+		return nil, err
+	}
+
+	if err := r.recordAudit(ctx, tx, auditModel.ActionUpdate, authorId, before, updatedComment); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
 	return updatedComment, nil
 }
 
 // Delete удаляет комментарий и возвращает его после удаления
-func (r *CommentRepo) Delete(ctx context.Context, id string, authorId string) (*model.Comment, error) {
+func (r *CommentRepo) Delete(ctx context.Context, id string, authorId string, expectedVersion *int) (*model.Comment, error) {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback(ctx)
 
-	var dbAuthor string
-	var threadID *string
-	err = tx.QueryRow(ctx, `
-		SELECT author_id, thread_id 
-		FROM comments 
-		WHERE id = $1
-	`, id).Scan(&dbAuthor, &threadID)
+	before, err := lockComment(ctx, tx, id)
 	if err != nil {
 		return nil, err
 	}
+	threadID := before.ThreadID
 
-	allowed := dbAuthor == authorId
-	isFirstComment := false
+	allowed := before.AuthorID == authorId
 
 	if !allowed && threadID != nil {
-		var threadAuthor string
-		err = tx.QueryRow(ctx, `
-			SELECT author_id
-			FROM comments
-			WHERE thread_id = $1
-			ORDER BY created_at ASC
-			LIMIT 1
-		`, *threadID).Scan(&threadAuthor)
+		_, threadAuthor, err := threadRootOf(ctx, tx, *threadID)
 		if err != nil {
 			return nil, err
 		}
-		if threadAuthor == authorId {
-			allowed = true
-		}
+		allowed = threadAuthor == authorId
 	}
 
 	if !allowed {
-		return nil, errors.New("only the comment author or thread author can delete this comment")
+		return nil, fmt.Errorf("only the comment author or thread author can delete this comment: %w", ErrForbidden)
 	}
 
-	_, err = tx.Exec(ctx, `
-		UPDATE comments
-		SET deleted_at = NOW(), status = 'deleted', updated_at = NOW()
-		WHERE id = $1
-	`, id)
-	if err != nil {
+	if err := checkVersion(before, expectedVersion); err != nil {
 		return nil, err
 	}
 
-	if threadID != nil {
-		var firstCommentID string
-		err = tx.QueryRow(ctx, `
-			SELECT id FROM comments
-			WHERE thread_id = $1
-			ORDER BY created_at ASC
-			LIMIT 1
-		`, *threadID).Scan(&firstCommentID)
-		if err != nil {
-			return nil, err
-		}
+	isFirstComment, err := softDelete(ctx, tx, id, threadID)
+	if err != nil {
+		return nil, err
+	}
 
-		if firstCommentID == id {
-			isFirstComment = true
-			_, err = tx.Exec(ctx, `
-				UPDATE comments
-				SET deleted_at = NOW(), status = 'deleted', updated_at = NOW()
-				WHERE thread_id = $1
-			`, *threadID)
-			if err != nil {
-				return nil, err
-			}
-		}
+	deletedComment, err := selectComment(ctx, tx, id)
+	if err != nil {
+		return nil, err
 	}
 
-	var deletedComment model.Comment
-	err = tx.QueryRow(ctx, `
-		SELECT id, thread_id, content, author_id, status, created_at, updated_at
-		FROM comments
-		WHERE id = $1
-	`, id).Scan(
-		&deletedComment.ID,
-		&deletedComment.ThreadID,
-		&deletedComment.Content,
-		&deletedComment.AuthorID,
-		&deletedComment.Status,
-		&deletedComment.CreatedAt,
-		&deletedComment.UpdatedAt,
-	)
+	// Удаление первого комментария удаляет весь тред
+	if isFirstComment {
+		err = resetThreadStats(ctx, tx, *threadID)
+	} else {
+		err = applyThreadStats(ctx, tx, before, deletedComment)
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	if err := r.recordAudit(ctx, tx, auditModel.ActionDelete, authorId, before, deletedComment); err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return nil, err
 	}
 
 	deletedComment.IsFirstComment = isFirstComment
 
-	return &deletedComment, nil
+	return deletedComment, nil
+}
+
+// firstByAuthor сообщает, что других комментариев автора в треде нет. Запланированные
+// не считаются, пока не опубликованы. Параллельные первые комментарии не видят друг
+// друга и оба считаются первыми, поэтому автоподписка не теряется
+func firstByAuthor(ctx context.Context, tx pgx.Tx, c *model.Comment) (bool, error) {
+	var exists bool
+	err := tx.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM comments
+			WHERE thread_id = $1 AND author_id = $2 AND id <> $3 AND status <> 'scheduled'
+		)
+	`, c.ThreadID, c.AuthorID, c.ID).Scan(&exists)
+	return !exists, err
+}
+
+// softDelete помечает комментарий удалённым. Если это первый комментарий треда,
+// удаляется весь тред; в этом случае возвращается true
+func softDelete(ctx context.Context, tx pgx.Tx, id string, threadID *string) (bool, error) {
+	_, err := tx.Exec(ctx, `
+		UPDATE comments
+		SET deleted_at = NOW(), deleted_status = status, status = 'deleted', updated_at = NOW(), version = version + 1
+		WHERE id = $1
+	`, id)
+	if err != nil {
+		return false, err
+	}
+
+	if threadID == nil {
+		return false, nil
+	}
+
+	var firstCommentID string
+	err = tx.QueryRow(ctx, `
+		SELECT id FROM comments
+		WHERE thread_id = $1
+		ORDER BY created_at ASC
+		LIMIT 1
+	`, *threadID).Scan(&firstCommentID)
+	if err != nil {
+		return false, err
+	}
+
+	if firstCommentID != id {
+		return false, nil
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE comments
+		SET deleted_at = NOW(), deleted_status = status, status = 'deleted', updated_at = NOW(), version = version + 1
+		WHERE thread_id = $1 AND deleted_at IS NULL
+	`, *threadID)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// selectComment читает один комментарий по id без учёта статуса
+func selectComment(ctx context.Context, q pgx.Tx, id string) (*model.Comment, error) {
+	return queryComment(ctx, q, id, "")
+}
+
+// lockComment читает комментарий и блокирует строку до конца транзакции
+func lockComment(ctx context.Context, q pgx.Tx, id string) (*model.Comment, error) {
+	return queryComment(ctx, q, id, "FOR UPDATE")
+}
+
+func queryComment(ctx context.Context, q pgx.Tx, id string, lock string) (*model.Comment, error) {
+	var c model.Comment
+	err := q.QueryRow(ctx, `
+		SELECT id, thread_id, answer_comment_id, anchor, content, author_id, status,
+		       moderation_reason, moderated_by, moderated_at, publish_at, version, pinned_at, pinned_by,
+		       upvotes, downvotes, score, created_at, updated_at
+		FROM comments
+		WHERE id = $1 AND `+tenantFilter("tenant_id", 2)+`
+	`+lock, id, tenant.ID(ctx)).Scan(
+		&c.ID,
+		&c.ThreadID,
+		&c.AnswerCommentID,
+		&c.Anchor,
+		&c.Content,
+		&c.AuthorID,
+		&c.Status,
+		&c.ModerationReason,
+		&c.ModeratedBy,
+		&c.ModeratedAt,
+		&c.PublishAt,
+		&c.Version,
+		&c.PinnedAt,
+		&c.PinnedBy,
+		&c.Upvotes,
+		&c.Downvotes,
+		&c.Score,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+// threadRootOf возвращает id и автора первого комментария треда. Автор первого
+// комментария — владелец треда
+func threadRootOf(ctx context.Context, q pgx.Tx, threadID string) (rootID, owner string, err error) {
+	err = q.QueryRow(ctx, `
+		SELECT id, author_id
+		FROM comments
+		WHERE thread_id = $1
+		ORDER BY created_at ASC
+		LIMIT 1
+	`, threadID).Scan(&rootID, &owner)
+	return rootID, owner, err
 }
 
-func (r *CommentRepo) ListWithReplies(ctx context.Context, threadIDs []string, replyLimit int) ([]model.Comment, error) {
+// ListWithReplies возвращает корневые комментарии тредов с превью ответов.
+// Порядок тредов задаётся opts.Sort, при равенстве — по id корня
+func (r *CommentRepo) ListWithReplies(ctx context.Context, threadIDs []string, opts model.ListOptions) ([]model.Comment, error) {
 	if len(threadIDs) == 0 {
 		return nil, nil
 	}
 
 	query := `
-        SELECT id, author_id, content, thread_id, created_at, updated_at
+        SELECT ` + readColumns + `, ` + viewerVoteColumn(2) + `
         FROM comments
-        WHERE thread_id = ANY($1) AND deleted_at IS NULL
-        ORDER BY created_at ASC
+        WHERE thread_id = ANY($1) AND ` + publicFilter + ` AND ` + tenantFilter("tenant_id", 3) + `
+        ORDER BY created_at ASC, id ASC
     `
-	rows, err := r.db.Query(ctx, query, threadIDs)
+	rows, err := r.db.Query(ctx, query, threadIDs, opts.ViewerID, tenant.ID(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -294,7 +544,7 @@ func (r *CommentRepo) ListWithReplies(ctx context.Context, threadIDs []string, r
 
 	for rows.Next() {
 		var c model.Comment
-		if err := rows.Scan(&c.ID, &c.AuthorID, &c.Content, &c.ThreadID, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		if err := scanRead(rows, &c, &c.ViewerVote); err != nil {
 			return nil, err
 		}
 		c.Replies = []model.Comment{}
@@ -303,34 +553,105 @@ func (r *CommentRepo) ListWithReplies(ctx context.Context, threadIDs []string, r
 		}
 	}
 
+	resolved, err := r.resolutions(ctx, threadIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	var result []model.Comment
+	// время последнего комментария по id корня — для сортировки по активности
+	lastActivity := make(map[string]time.Time)
 
-	for _, comments := range threadComments {
+	for threadID, comments := range threadComments {
 		if len(comments) == 0 {
 			continue
 		}
+		res, isResolved := resolved[threadID]
+		if opts.Resolved != nil && *opts.Resolved != isResolved {
+			continue
+		}
+		applyResolution(comments, res)
 
 		root := comments[0]
+		root.Pinned = pinnedOf(comments)
 
 		totalReplies := len(comments) - 1
 		root.RepliesCount = totalReplies
+		lastActivity[root.ID] = comments[totalReplies].CreatedAt
 
-		if replyLimit <= 0 || totalReplies <= 0 {
-			root.Replies = []model.Comment{}
-		} else {
-			start := len(comments) - replyLimit
-			if start < 1 {
-				start = 1
-			}
-			root.Replies = comments[start:]
-		}
+		root.Replies = withAccepted(previewReplies(comments[1:], opts), comments[1:])
 
 		result = append(result, root)
 	}
 
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].CreatedAt.After(result[j].CreatedAt)
+	sortThreads(result, opts.Sort, lastActivity)
+	return result, nil
+}
+
+// previewReplies выбирает ответы треда для превью согласно opts.Preview и opts.ReplyLimit
+func previewReplies(replies []model.Comment, opts model.ListOptions) []model.Comment {
+	limit := opts.ReplyLimit
+	if limit <= 0 || len(replies) == 0 {
+		return []model.Comment{}
+	}
+	if opts.Preview == model.ReplyPreviewEarliest {
+		return replies[:min(limit, len(replies))]
+	}
+	return replies[max(len(replies)-limit, 0):]
+}
+
+// sortThreads упорядочивает корни тредов по order, при равенстве — новые первыми, затем по id.
+// lastActivity — время последнего комментария по id корня
+func sortThreads(roots []model.Comment, order model.ThreadSort, lastActivity map[string]time.Time) {
+	sort.Slice(roots, func(i, j int) bool {
+		a, b := roots[i], roots[j]
+		switch order {
+		case model.ThreadSortActivity:
+			if ta, tb := lastActivity[a.ID], lastActivity[b.ID]; !ta.Equal(tb) {
+				return ta.After(tb)
+			}
+		case model.ThreadSortReplies:
+			if a.RepliesCount != b.RepliesCount {
+				return a.RepliesCount > b.RepliesCount
+			}
+		case model.ThreadSortTop:
+			if a.Score != b.Score {
+				return a.Score > b.Score
+			}
+		case model.ThreadSortControversial:
+			if ca, cb := model.Controversy(a.Upvotes, a.Downvotes), model.Controversy(b.Upvotes, b.Downvotes); ca != cb {
+				return ca > cb
+			}
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.ID < b.ID
 	})
+}
 
-	return result, nil
+// checkVersion сверяет версию комментария с ожидаемой клиентом
+func checkVersion(c *model.Comment, expectedVersion *int) error {
+	if expectedVersion != nil && *expectedVersion != c.Version {
+		return &VersionConflictError{Current: c.Version}
+	}
+	return nil
+}
+
+// HasDuplicate проверяет, отправлял ли автор такой же текст после since.
+// excludeID исключает редактируемый комментарий из поиска
+func (r *CommentRepo) HasDuplicate(ctx context.Context, authorID, content, excludeID string, since time.Time) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM comments
+			WHERE author_id = $1
+			  AND content = $2
+			  AND created_at >= $3
+			  AND deleted_at IS NULL
+			  AND ($4 = '' OR id::text <> $4)
+			  AND `+tenantFilter("tenant_id", 5)+`
+		)
+	`, authorID, content, since, excludeID, tenant.ID(ctx)).Scan(&exists)
+	return exists, err
 }
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/pksep/comments/internal/db"
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+// GetCommentContext возвращает опубликованный комментарий, до ancestors его
+// родителей по answer_comment_id и до siblings соседей с каждой стороны
+// среди ответов на того же родителя в том же треде. Комментарии других тенантов
+// в контекст не попадают
+func (r *CommentRepo) GetCommentContext(ctx context.Context, id string, ancestors, siblings int) (*model.CommentWithContext, error) {
+	c, err := r.GetComment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	result := &model.CommentWithContext{Comment: *c}
+
+	if ancestors > 0 && c.AnswerCommentID != nil {
+		// Цепочка строится по всем комментариям, а отдаются только видимые,
+		// чтобы скрытый родитель не обрывал контекст
+		result.Ancestors, err = queryComments(ctx, r.db, `
+			WITH RECURSIVE chain (cid, parent, depth) AS (
+				SELECT id, answer_comment_id, 0 FROM comments
+				WHERE id = $1 AND `+tenantFilter("tenant_id", 3)+`
+				UNION ALL
+				SELECT p.id, p.answer_comment_id, chain.depth + 1
+				FROM comments p
+				JOIN chain ON p.id = chain.parent
+				WHERE chain.depth < $2 AND `+tenantFilter("p.tenant_id", 3)+`
+			)
+			SELECT `+readColumns+`
+			FROM chain
+			JOIN comments ON comments.id = chain.cid
+			WHERE chain.depth > 0 AND `+publicFilter+`
+			ORDER BY chain.depth DESC
+		`, id, ancestors, tenant.ID(ctx))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if siblings > 0 && c.ThreadID != nil {
+		siblingsQuery := `
+			SELECT ` + readColumns + `
+			FROM comments
+			WHERE thread_id = $1
+			  AND answer_comment_id IS NOT DISTINCT FROM $2
+			  AND ` + publicFilter + `
+			  AND ` + tenantFilter("tenant_id", 6) + `
+			  AND (created_at, id) %s ($3, $4::uuid)
+			ORDER BY created_at %s, id %s
+			LIMIT $5
+		`
+		result.SiblingsBefore, err = queryComments(ctx, r.db,
+			fmt.Sprintf(siblingsQuery, "<", "DESC", "DESC"),
+			*c.ThreadID, c.AnswerCommentID, c.CreatedAt, c.ID, siblings, tenant.ID(ctx))
+		if err != nil {
+			return nil, err
+		}
+		slices.Reverse(result.SiblingsBefore)
+
+		result.SiblingsAfter, err = queryComments(ctx, r.db,
+			fmt.Sprintf(siblingsQuery, ">", "ASC", "ASC"),
+			*c.ThreadID, c.AnswerCommentID, c.CreatedAt, c.ID, siblings, tenant.ID(ctx))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// queryComments выполняет запрос, выбирающий readColumns, и собирает результат
+func queryComments(ctx context.Context, q db.Querier, query string, args ...any) ([]model.Comment, error) {
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []model.Comment{}
+	for rows.Next() {
+		var c model.Comment
+		if err := scanRead(rows, &c); err != nil {
+			return nil, err
+		}
+		c.Replies = []model.Comment{}
+		comments = append(comments, c)
+	}
+	return comments, rows.Err()
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotFound — комментарий не найден или недоступен для операции
+	ErrNotFound = errors.New("comment not found")
+	// ErrForbidden — у пользователя нет прав на операцию с комментарием
+	ErrForbidden = errors.New("forbidden")
+	// ErrAlreadyReported — пользователь уже пожаловался на этот комментарий
+	ErrAlreadyReported = errors.New("comment already reported by this user")
+	// ErrPinLimit — в треде уже закреплено максимальное число комментариев
+	ErrPinLimit = errors.New("pinned comments limit reached for this thread")
+	// ErrNotReply — принять ответом можно только ответ, а не сам вопрос треда
+	ErrNotReply = errors.New("only a reply can be accepted as an answer")
+	// ErrInvalidParent — ответить можно только на комментарий того же треда
+	ErrInvalidParent = errors.New("parent comment not found in this thread")
+	// ErrNotScheduled — перенести публикацию можно только у ещё не опубликованного комментария
+	ErrNotScheduled = errors.New("comment is not scheduled")
+)
+
+// VersionConflictError — комментарий изменён с момента, когда клиент получил версию
+type VersionConflictError struct {
+	Current int
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("comment was modified concurrently, current version is %d", e.Current)
+}
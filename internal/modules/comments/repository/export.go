@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+// StreamThread построчно передаёт комментарии треда в порядке обхода дерева в глубину:
+// за каждым комментарием идут его ответы, соседи упорядочены по времени создания.
+// depth — уровень вложенности, 0 у комментариев верхнего уровня. Ответы на комментарии,
+// не прошедшие фильтр видимости, не выгружаются. Если в треде нет видимых комментариев,
+// возвращается ErrNotFound
+func (r *CommentRepo) StreamThread(ctx context.Context, threadID string, includeHidden bool, fn func(c model.Comment, depth int) error) error {
+	filter := publicFilter
+	if includeHidden {
+		filter = moderatorFilter
+	}
+
+	// Ключ пути сортируется как строка: время создания с микросекундами, затем id
+	const pathKey = `to_char(created_at AT TIME ZONE 'UTC', 'YYYYMMDDHH24MISSUS') || id::text`
+
+	rows, err := r.db.Query(ctx, `
+		WITH RECURSIVE tree AS (
+			SELECT id AS tid, 0 AS depth, ARRAY[`+pathKey+`] AS path
+			FROM comments
+			WHERE thread_id = $1 AND answer_comment_id IS NULL AND `+filter+`
+			  AND `+tenantFilter("tenant_id", 2)+`
+			UNION ALL
+			SELECT c.id, t.depth + 1, t.path || (`+pathKey+`)
+			FROM comments c
+			JOIN tree t ON c.answer_comment_id = t.tid
+			WHERE c.thread_id = $1 AND `+filter+` AND `+tenantFilter("c.tenant_id", 2)+`
+		)
+		SELECT `+readColumns+`, depth
+		FROM tree
+		JOIN comments ON comments.id = tree.tid
+		ORDER BY path
+	`, threadID, tenant.ID(ctx))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	found := false
+	for rows.Next() {
+		var c model.Comment
+		var depth int
+		if err := scanRead(rows, &c, &depth); err != nil {
+			return err
+		}
+		found = true
+		if err := fn(c, depth); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if !found {
+		return ErrNotFound
+	}
+	return nil
+}
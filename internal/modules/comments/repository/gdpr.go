@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+
+	auditModel "github.com/pksep/comments/internal/modules/audit/model"
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+// StreamByAuthor построчно передаёт все комментарии автора в тенанте в любом статусе
+func (r *CommentRepo) StreamByAuthor(ctx context.Context, authorID string, fn func(model.Comment) error) error {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+readColumns+`
+		FROM comments
+		WHERE author_id = $1 AND `+tenantFilter("tenant_id", 2)+`
+		ORDER BY created_at ASC, id ASC
+	`, authorID, tenant.ID(ctx))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c model.Comment
+		if err := scanRead(rows, &c); err != nil {
+			return err
+		}
+		if err := fn(c); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// CountByAuthor возвращает число комментариев автора в тенанте в любом статусе
+func (r *CommentRepo) CountByAuthor(ctx context.Context, authorID string) (int, error) {
+	var n int
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM comments WHERE author_id = $1 AND `+tenantFilter("tenant_id", 2),
+		authorID, tenant.ID(ctx)).Scan(&n)
+	return n, err
+}
+
+// AnonymizeAuthorBatch обезличивает до limit комментариев автора в тенанте: author_id заменяется
+// псевдонимом, текст — RedactedContent. Структура тредов и ответов сохраняется.
+// Обработанные комментарии больше не подходят под условие, поэтому повторный
+// вызов продолжает с того места, где остановился предыдущий. Возвращает число обработанных
+func (r *CommentRepo) AnonymizeAuthorBatch(ctx context.Context, authorID, pseudonym, actorID string, limit int) (int, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	erased, err := queryComments(ctx, tx, `
+		WITH batch AS (
+			SELECT id AS batch_id FROM comments
+			WHERE author_id = $1 AND `+tenantFilter("tenant_id", 5)+`
+			ORDER BY id
+			LIMIT $4
+			FOR UPDATE
+		)
+		UPDATE comments
+		SET author_id = $2, content = $3, updated_at = NOW(), version = version + 1
+		FROM batch
+		WHERE comments.id = batch.batch_id
+		RETURNING `+readColumns, authorID, pseudonym, model.RedactedContent, limit, tenant.ID(ctx))
+	if err != nil {
+		return 0, err
+	}
+
+	// Снимок «до» не пишется, чтобы персональные данные не оседали в журнале
+	for i := range erased {
+		if err := r.recordAudit(ctx, tx, auditModel.ActionErase, actorID, nil, &erased[i]); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return len(erased), nil
+}
+
+// AnonymizeReferences заменяет псевдонимом упоминания автора вне его комментариев
+// в тенанте: в жалобах, закреплениях, принятых ответах и голосах
+func (r *CommentRepo) AnonymizeReferences(ctx context.Context, authorID, pseudonym string) error {
+	ofTenant := `comment_id IN (SELECT id FROM comments WHERE ` + tenantFilter("tenant_id", 3) + `)`
+	for _, query := range []string{
+		`UPDATE comment_reports SET reporter_id = $2 WHERE reporter_id = $1 AND ` + ofTenant,
+		`UPDATE comments SET pinned_by = $2 WHERE pinned_by = $1 AND ` + tenantFilter("tenant_id", 3),
+		`UPDATE threads SET resolved_by = $2 WHERE resolved_by = $1 AND ` + tenantFilter("tenant_id", 3),
+		`UPDATE comment_votes SET voter_id = $2 WHERE voter_id = $1 AND ` + ofTenant,
+	} {
+		if _, err := r.db.Exec(ctx, query, authorID, pseudonym, tenant.ID(ctx)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+// importColumns — колонки, заполняемые при импорте через COPY
+var importColumns = []string{
+	"id", "author_id", "content", "thread_id", "answer_comment_id",
+	"status", "version", "created_at", "updated_at", "deleted_at", "tenant_id",
+}
+
+// ThreadsOf возвращает thread_id для тех из ids, которые уже есть в базе тенанта (в любом статусе)
+func (r *CommentRepo) ThreadsOf(ctx context.Context, ids []string) (map[string]*string, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, thread_id FROM comments WHERE id = ANY($1::uuid[]) AND `+tenantFilter("tenant_id", 2),
+		ids, tenant.ID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	threads := make(map[string]*string, len(ids))
+	for rows.Next() {
+		var id string
+		var threadID *string
+		if err := rows.Scan(&id, &threadID); err != nil {
+			return nil, err
+		}
+		threads[id] = threadID
+	}
+	return threads, rows.Err()
+}
+
+// ImportBatch вставляет пачку комментариев с готовыми id и датами одной транзакцией через COPY.
+// Родитель каждого комментария должен быть в базе или в этой же пачке.
+// Удалённые комментарии получают deleted_at = updated_at. Журнал аудита не пишется:
+// импорт переносит историю, а не изменяет её. Комментарии и новые треды получают тенант
+// из контекста, треды других тенантов не принимаются
+func (r *CommentRepo) ImportBatch(ctx context.Context, comments []model.Comment) error {
+	tenantID := tenant.IDOrDefault(ctx)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	threadIDs := make([]string, 0, len(comments))
+	seen := make(map[string]bool, len(comments))
+	for _, c := range comments {
+		if c.ThreadID != nil && !seen[*c.ThreadID] {
+			seen[*c.ThreadID] = true
+			threadIDs = append(threadIDs, *c.ThreadID)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO threads (id, tenant_id) SELECT unnest($1::uuid[]), $2 ON CONFLICT (id) DO NOTHING
+	`, threadIDs, tenantID); err != nil {
+		return err
+	}
+
+	var foreign *string
+	err = tx.QueryRow(ctx, `
+		SELECT MIN(id::text) FROM threads WHERE id = ANY($1::uuid[]) AND tenant_id <> $2
+	`, threadIDs, tenantID).Scan(&foreign)
+	if err != nil {
+		return err
+	}
+	if foreign != nil {
+		return fmt.Errorf("thread with ID %s not found: %w", *foreign, ErrNotFound)
+	}
+
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"comments"}, importColumns,
+		pgx.CopyFromSlice(len(comments), func(i int) ([]any, error) {
+			c := comments[i]
+			var deletedAt any
+			if c.Status == model.CommentStatusDeleted {
+				deletedAt = c.UpdatedAt
+			}
+			return []any{
+				c.ID, c.AuthorID, c.Content, c.ThreadID, c.AnswerCommentID,
+				c.Status, 1, c.CreatedAt, c.UpdatedAt, deletedAt, tenantID,
+			}, nil
+		}))
+	if err != nil {
+		return err
+	}
+
+	// Родитель должен быть в том же треде: внешний ключ проверяет только его существование
+	ids := make([]string, len(comments))
+	for i, c := range comments {
+		ids[i] = c.ID
+	}
+	var orphan *string
+	err = tx.QueryRow(ctx, `
+		SELECT MIN(c.id::text)
+		FROM comments c
+		JOIN comments p ON p.id = c.answer_comment_id
+		WHERE c.id = ANY($1::uuid[])
+		  AND (p.thread_id IS DISTINCT FROM c.thread_id OR p.tenant_id <> c.tenant_id)
+	`, ids).Scan(&orphan)
+	if err != nil {
+		return err
+	}
+	if orphan != nil {
+		return fmt.Errorf("comment %s: %w", *orphan, ErrInvalidParent)
+	}
+
+	for i := range threadIDs {
+		if err := refreshThreadStats(ctx, tx, &threadIDs[i]); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+)
+
+func ids(comments []model.Comment) []string {
+	out := make([]string, len(comments))
+	for i, c := range comments {
+		out[i] = c.ID
+	}
+	return out
+}
+
+func TestPreviewReplies(t *testing.T) {
+	replies := []model.Comment{{ID: "r1"}, {ID: "r2"}, {ID: "r3"}, {ID: "r4"}}
+	cases := []struct {
+		opts model.ListOptions
+		want []string
+	}{
+		{model.ListOptions{Preview: model.ReplyPreviewLatest, ReplyLimit: 2}, []string{"r3", "r4"}},
+		{model.ListOptions{Preview: model.ReplyPreviewEarliest, ReplyLimit: 2}, []string{"r1", "r2"}},
+		{model.ListOptions{Preview: model.ReplyPreviewLatest, ReplyLimit: 10}, []string{"r1", "r2", "r3", "r4"}},
+		{model.ListOptions{Preview: model.ReplyPreviewEarliest, ReplyLimit: 0}, []string{}},
+	}
+	for _, tc := range cases {
+		if got := ids(previewReplies(replies, tc.opts)); !slices.Equal(got, tc.want) {
+			t.Errorf("%+v: replies = %v, want %v", tc.opts, got, tc.want)
+		}
+	}
+	if got := previewReplies(nil, model.ListOptions{ReplyLimit: 3}); got == nil || len(got) != 0 {
+		t.Errorf("no replies: got %v, want empty non-nil slice", got)
+	}
+}
+
+func TestSortThreads(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	roots := func() []model.Comment {
+		return []model.Comment{
+			{ID: "a", CreatedAt: base, RepliesCount: 5, Score: 1, Upvotes: 5, Downvotes: 4},
+			{ID: "b", CreatedAt: base.Add(time.Hour), RepliesCount: 1, Score: 7, Upvotes: 7},
+			{ID: "c", CreatedAt: base.Add(time.Hour), RepliesCount: 1, Score: 1, Upvotes: 1},
+			{ID: "d", CreatedAt: base.Add(-time.Hour), RepliesCount: 5, Score: -2, Upvotes: 1, Downvotes: 3},
+		}
+	}
+	activity := map[string]time.Time{
+		"a": base.Add(3 * time.Hour),
+		"b": base.Add(time.Hour),
+		"c": base.Add(time.Hour),
+		"d": base.Add(5 * time.Hour),
+	}
+
+	cases := []struct {
+		order model.ThreadSort
+		want  []string
+	}{
+		// Равные треды идут по дате корня, а при равной дате — по id
+		{model.ThreadSortCreated, []string{"b", "c", "a", "d"}},
+		{model.ThreadSortActivity, []string{"d", "a", "b", "c"}},
+		{model.ThreadSortReplies, []string{"a", "d", "b", "c"}},
+		{model.ThreadSortTop, []string{"b", "c", "a", "d"}},
+		{model.ThreadSortControversial, []string{"a", "d", "b", "c"}},
+	}
+	for _, tc := range cases {
+		r := roots()
+		sortThreads(r, tc.order, activity)
+		if got := ids(r); !slices.Equal(got, tc.want) {
+			t.Errorf("%s: order = %v, want %v", tc.order, got, tc.want)
+		}
+	}
+}
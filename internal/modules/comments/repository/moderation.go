@@ -0,0 +1,301 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	auditModel "github.com/pksep/comments/internal/modules/audit/model"
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+// Report сохраняет жалобу и, если число открытых жалоб достигло autoHideThreshold,
+// скрывает комментарий. autoHideThreshold <= 0 отключает автоскрытие
+func (r *CommentRepo) Report(ctx context.Context, report *model.Report, autoHideThreshold int) (*model.Report, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM comments
+			WHERE id = $1 AND deleted_at IS NULL AND status <> 'scheduled' AND `+tenantFilter("tenant_id", 2)+`
+		)
+	`, report.CommentID, tenant.ID(ctx)).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	report.ID = uuid.New().String()
+	report.CreatedAt = time.Now()
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO comment_reports (id, comment_id, reporter_id, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (comment_id, reporter_id) DO NOTHING
+	`, report.ID, report.CommentID, report.ReporterID, report.Reason, report.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrAlreadyReported
+	}
+
+	if autoHideThreshold > 0 {
+		var open int
+		err = tx.QueryRow(ctx, `
+			SELECT COUNT(*) FROM comment_reports
+			WHERE comment_id = $1 AND resolved_at IS NULL
+		`, report.CommentID).Scan(&open)
+		if err != nil {
+			return nil, err
+		}
+
+		if open >= autoHideThreshold {
+			if err := r.autoHide(ctx, tx, report.CommentID, open); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// ListModerationQueue возвращает комментарии, требующие внимания модератора:
+// ожидающие одобрения, скрытые и имеющие открытые жалобы
+func (r *CommentRepo) ListModerationQueue(ctx context.Context, filter model.QueueFilter, limit, offset int) ([]model.QueueItem, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT c.id, c.author_id, c.content, c.thread_id, c.answer_comment_id, c.status,
+		       c.moderation_reason, c.moderated_by, c.moderated_at, c.version, c.created_at, c.updated_at,
+		       COUNT(rep.id) AS reports_count
+		FROM comments c
+		LEFT JOIN comment_reports rep ON rep.comment_id = c.id AND rep.resolved_at IS NULL
+		WHERE c.deleted_at IS NULL AND `+tenantFilter("c.tenant_id", 4)+`
+		GROUP BY c.id
+		HAVING CASE $1
+			WHEN 'pending'  THEN c.status = 'pending'
+			WHEN 'hidden'   THEN c.status = 'hidden'
+			WHEN 'reported' THEN COUNT(rep.id) > 0
+			ELSE c.status IN ('pending', 'hidden') OR COUNT(rep.id) > 0
+		END
+		ORDER BY c.created_at ASC, c.id ASC
+		LIMIT $2 OFFSET $3
+	`, string(filter), limit, offset, tenant.ID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []model.QueueItem{}
+	index := make(map[string]int)
+	for rows.Next() {
+		var it model.QueueItem
+		if err := rows.Scan(
+			&it.ID, &it.AuthorID, &it.Content, &it.ThreadID, &it.AnswerCommentID, &it.Status,
+			&it.ModerationReason, &it.ModeratedBy, &it.ModeratedAt, &it.Version, &it.CreatedAt, &it.UpdatedAt,
+			&it.ReportsCount,
+		); err != nil {
+			return nil, err
+		}
+		it.Replies = []model.Comment{}
+		it.Reports = []model.Report{}
+		index[it.ID] = len(items)
+		items = append(items, it)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return items, nil
+	}
+
+	ids := make([]string, 0, len(items))
+	for _, it := range items {
+		ids = append(ids, it.ID)
+	}
+
+	reportRows, err := r.db.Query(ctx, `
+		SELECT id, comment_id, reporter_id, reason, created_at, resolved_at
+		FROM comment_reports
+		WHERE comment_id = ANY($1) AND resolved_at IS NULL
+		ORDER BY created_at ASC
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer reportRows.Close()
+
+	for reportRows.Next() {
+		var rep model.Report
+		if err := reportRows.Scan(&rep.ID, &rep.CommentID, &rep.ReporterID, &rep.Reason, &rep.CreatedAt, &rep.ResolvedAt); err != nil {
+			return nil, err
+		}
+		i := index[rep.CommentID]
+		items[i].Reports = append(items[i].Reports, rep)
+	}
+
+	return items, reportRows.Err()
+}
+
+// autoHide скрывает опубликованный комментарий, набравший reports жалоб
+func (r *CommentRepo) autoHide(ctx context.Context, tx pgx.Tx, id string, reports int) error {
+	before, err := selectComment(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE comments
+		SET status = 'hidden', moderation_reason = $2, version = version + 1
+		WHERE id = $1 AND status IN ('created', 'edited')
+	`, id, fmt.Sprintf("auto-hidden after %d reports", reports))
+	if err != nil || tag.RowsAffected() == 0 {
+		return err
+	}
+
+	after, err := selectComment(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if err := applyThreadStats(ctx, tx, before, after); err != nil {
+		return err
+	}
+
+	return r.recordAudit(ctx, tx, auditModel.ActionHide, auditModel.SystemActor, before, after)
+}
+
+// Approve публикует скрытый или ожидающий модерации комментарий и закрывает жалобы на него
+func (r *CommentRepo) Approve(ctx context.Context, id, moderatorID string, reason *string) (*model.Comment, error) {
+	return r.moderate(ctx, id, auditModel.ActionApprove, moderatorID, func(tx pgx.Tx) (pgconn.CommandTag, error) {
+		return tx.Exec(ctx, `
+			UPDATE comments
+			SET status = CASE WHEN updated_at > created_at THEN 'edited' ELSE 'created' END,
+			    moderation_reason = $3, moderated_by = $2, moderated_at = NOW(), version = version + 1
+			WHERE id = $1 AND deleted_at IS NULL AND status <> 'scheduled'
+			  AND (status IN ('pending', 'hidden')
+			       OR EXISTS (SELECT 1 FROM comment_reports WHERE comment_id = $1 AND resolved_at IS NULL))
+		`, id, moderatorID, reason)
+	})
+}
+
+// Hide скрывает комментарий из публичного чтения. Запланированный комментарий ещё
+// не опубликован: скрыть его нельзя, иначе Approve опубликовал бы его раньше срока
+func (r *CommentRepo) Hide(ctx context.Context, id, moderatorID, reason string) (*model.Comment, error) {
+	return r.moderate(ctx, id, auditModel.ActionHide, moderatorID, func(tx pgx.Tx) (pgconn.CommandTag, error) {
+		return tx.Exec(ctx, `
+			UPDATE comments
+			SET status = 'hidden', moderation_reason = $3, moderated_by = $2, moderated_at = NOW(), version = version + 1
+			WHERE id = $1 AND deleted_at IS NULL AND status <> 'scheduled'
+		`, id, moderatorID, reason)
+	})
+}
+
+// ModeratorDelete удаляет комментарий без проверки авторства
+func (r *CommentRepo) ModeratorDelete(ctx context.Context, id, moderatorID, reason string) (*model.Comment, error) {
+	var isFirstComment bool
+	c, err := r.moderate(ctx, id, auditModel.ActionDelete, moderatorID, func(tx pgx.Tx) (pgconn.CommandTag, error) {
+		var threadID *string
+		err := tx.QueryRow(ctx, `
+			SELECT thread_id FROM comments WHERE id = $1 AND deleted_at IS NULL
+		`, id).Scan(&threadID)
+		if err != nil {
+			return pgconn.CommandTag{}, err
+		}
+		if isFirstComment, err = softDelete(ctx, tx, id, threadID); err != nil {
+			return pgconn.CommandTag{}, err
+		}
+		return tx.Exec(ctx, `
+			UPDATE comments
+			SET moderation_reason = $3, moderated_by = $2, moderated_at = NOW()
+			WHERE id = $1
+		`, id, moderatorID, reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.IsFirstComment = isFirstComment
+	return c, nil
+}
+
+// Restore возвращает удалённому комментарию статус, который был у него до удаления:
+// ожидавший модерации или публикации по расписанию комментарий продолжает ждать.
+// Восстанавливается только сам комментарий, ответы удалённого вместе с ним треда
+// остаются удалёнными
+func (r *CommentRepo) Restore(ctx context.Context, id, moderatorID, reason string) (*model.Comment, error) {
+	return r.moderate(ctx, id, auditModel.ActionRestore, moderatorID, func(tx pgx.Tx) (pgconn.CommandTag, error) {
+		return tx.Exec(ctx, `
+			UPDATE comments
+			SET deleted_at = NULL, status = COALESCE(deleted_status, 'created'), deleted_status = NULL,
+			    moderation_reason = $3, moderated_by = $2, moderated_at = NOW(), updated_at = NOW(),
+			    version = version + 1
+			WHERE id = $1 AND deleted_at IS NOT NULL
+		`, id, moderatorID, reason)
+	})
+}
+
+// moderate выполняет действие модератора в транзакции, закрывает открытые жалобы,
+// пишет аудит и возвращает комментарий в итоговом состоянии
+func (r *CommentRepo) moderate(ctx context.Context, id string, auditAction auditModel.Action, moderatorID string, action func(tx pgx.Tx) (pgconn.CommandTag, error)) (*model.Comment, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := lockComment(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := action(tx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrNotFound
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE comment_reports SET resolved_at = NOW()
+		WHERE comment_id = $1 AND resolved_at IS NULL
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := selectComment(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyThreadStats(ctx, tx, before, c); err != nil {
+		return nil, err
+	}
+
+	if err := r.recordAudit(ctx, tx, auditAction, moderatorID, before, c); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
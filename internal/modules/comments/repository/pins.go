@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/jackc/pgx/v5"
+
+	auditModel "github.com/pksep/comments/internal/modules/audit/model"
+	"github.com/pksep/comments/internal/modules/comments/model"
+)
+
+// Pin закрепляет опубликованный комментарий в треде. Закреплять может только владелец треда.
+// Блокировка строки треда не даёт параллельным запросам превысить maxPinned (0 — без ограничений).
+// Повторное закрепление ничего не меняет
+func (r *CommentRepo) Pin(ctx context.Context, id, actorID string, maxPinned int) (*model.Comment, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := lockForPin(ctx, tx, id, actorID, true)
+	if err != nil {
+		return nil, err
+	}
+	if before.PinnedAt != nil {
+		return before, nil
+	}
+
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM threads WHERE id = $1 FOR UPDATE`, *before.ThreadID); err != nil {
+		return nil, err
+	}
+
+	// Скрытые и ожидающие модерации закреплённые комментарии тоже занимают место:
+	// после одобрения они снова видны закреплёнными
+	if maxPinned > 0 {
+		var pinned int
+		err := tx.QueryRow(ctx, `
+			SELECT COUNT(*) FROM comments
+			WHERE thread_id = $1 AND pinned_at IS NOT NULL AND deleted_at IS NULL`,
+			*before.ThreadID).Scan(&pinned)
+		if err != nil {
+			return nil, err
+		}
+		if pinned >= maxPinned {
+			return nil, fmt.Errorf("%w (max %d)", ErrPinLimit, maxPinned)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE comments
+		SET pinned_at = NOW(), pinned_by = $2, version = version + 1
+		WHERE id = $1
+	`, id, actorID); err != nil {
+		return nil, err
+	}
+
+	return r.finishPin(ctx, tx, auditModel.ActionPin, actorID, before)
+}
+
+// Unpin снимает закрепление. Снять может только владелец треда, в том числе со
+// скрытого или ожидающего модерации комментария
+func (r *CommentRepo) Unpin(ctx context.Context, id, actorID string) (*model.Comment, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := lockForPin(ctx, tx, id, actorID, false)
+	if err != nil {
+		return nil, err
+	}
+	if before.PinnedAt == nil {
+		return before, nil
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE comments
+		SET pinned_at = NULL, pinned_by = NULL, version = version + 1
+		WHERE id = $1
+	`, id); err != nil {
+		return nil, err
+	}
+
+	return r.finishPin(ctx, tx, auditModel.ActionUnpin, actorID, before)
+}
+
+// lockForPin блокирует неудалённый комментарий (при published — только опубликованный)
+// и проверяет, что actorID — владелец треда
+func lockForPin(ctx context.Context, tx pgx.Tx, id, actorID string, published bool) (*model.Comment, error) {
+	c, err := lockComment(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !pinnable(c, published) {
+		return nil, fmt.Errorf("comment with ID %s not found: %w", id, ErrNotFound)
+	}
+
+	_, owner, err := threadRootOf(ctx, tx, *c.ThreadID)
+	if err != nil {
+		return nil, err
+	}
+	if owner != actorID {
+		return nil, fmt.Errorf("only the thread author can pin comments: %w", ErrForbidden)
+	}
+	return c, nil
+}
+
+// pinnable: закрепляется только опубликованный комментарий треда, а снять
+// закрепление можно с любого неудалённого
+func pinnable(c *model.Comment, published bool) bool {
+	if c.ThreadID == nil || c.Status == model.CommentStatusDeleted {
+		return false
+	}
+	return !published || c.Published()
+}
+
+func (r *CommentRepo) finishPin(ctx context.Context, tx pgx.Tx, action auditModel.Action, actorID string, before *model.Comment) (*model.Comment, error) {
+	after, err := selectComment(ctx, tx, before.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.recordAudit(ctx, tx, action, actorID, before, after); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return after, nil
+}
+
+// pinnedOf выбирает закреплённые комментарии в порядке закрепления
+func pinnedOf(comments []model.Comment) []model.Comment {
+	var pinned []model.Comment
+	for _, c := range comments {
+		if c.PinnedAt != nil {
+			pinned = append(pinned, c)
+		}
+	}
+	sort.SliceStable(pinned, func(i, j int) bool {
+		return pinned[i].PinnedAt.Before(*pinned[j].PinnedAt)
+	})
+	return pinned
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+)
+
+func TestPinnedOf(t *testing.T) {
+	at := func(m int) *time.Time {
+		v := time.Date(2026, 1, 1, 0, m, 0, 0, time.UTC)
+		return &v
+	}
+	comments := []model.Comment{
+		{ID: "root"},
+		{ID: "late", PinnedAt: at(5)},
+		{ID: "plain"},
+		{ID: "early", PinnedAt: at(1)},
+	}
+	if got := ids(pinnedOf(comments)); !slices.Equal(got, []string{"early", "late"}) {
+		t.Fatalf("pinned = %v, want in pin order", got)
+	}
+	if got := pinnedOf(comments[:1]); got != nil {
+		t.Fatalf("no pins: got %v, want nil so the field is omitted", got)
+	}
+}
+
+func TestPinnable(t *testing.T) {
+	thread := "t1"
+	cases := []struct {
+		status     model.CommentStatus
+		pin, unpin bool
+	}{
+		{model.CommentStatusCreated, true, true},
+		{model.CommentStatusEdited, true, true},
+		{model.CommentStatusHidden, false, true},
+		{model.CommentStatusPending, false, true},
+		{model.CommentStatusDeleted, false, false},
+	}
+	for _, tc := range cases {
+		c := &model.Comment{ThreadID: &thread, Status: tc.status}
+		if got := pinnable(c, true); got != tc.pin {
+			t.Errorf("%s: pin = %v, want %v", tc.status, got, tc.pin)
+		}
+		if got := pinnable(c, false); got != tc.unpin {
+			t.Errorf("%s: unpin = %v, want %v", tc.status, got, tc.unpin)
+		}
+	}
+}
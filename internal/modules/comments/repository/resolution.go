@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/jackc/pgx/v5"
+	auditModel "github.com/pksep/comments/internal/modules/audit/model"
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+// acceptedVisible — условие «принятый ответ треда threads виден публично».
+// Скрытый или удалённый принятый ответ не делает тред решённым, а после
+// восстановления ответа тред снова считается решённым
+const acceptedVisible = `EXISTS (
+	SELECT 1 FROM comments
+	WHERE comments.id = threads.accepted_comment_id AND ` + publicFilter + `
+)`
+
+// Accept отмечает опубликованный ответ принятым, и тред становится решённым.
+// Выбирает ответ только владелец треда; выбор другого ответа заменяет прежний
+func (r *CommentRepo) Accept(ctx context.Context, id, actorID string) (*model.Resolution, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	c, err := lockComment(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.ThreadID == nil || (c.Status != model.CommentStatusCreated && c.Status != model.CommentStatusEdited) {
+		return nil, fmt.Errorf("comment with ID %s not found: %w", id, ErrNotFound)
+	}
+
+	rootID, owner, err := threadRootOf(ctx, tx, *c.ThreadID)
+	if err != nil {
+		return nil, err
+	}
+	if owner != actorID {
+		return nil, fmt.Errorf("only the thread author can accept an answer: %w", ErrForbidden)
+	}
+	if rootID == c.ID {
+		return nil, ErrNotReply
+	}
+
+	current, err := lockResolution(ctx, tx, *c.ThreadID)
+	if err != nil {
+		return nil, err
+	}
+	if current.AcceptedCommentID != nil && *current.AcceptedCommentID == id {
+		return current, nil
+	}
+
+	res, err := scanResolution(tx.QueryRow(ctx, `
+		UPDATE threads
+		SET accepted_comment_id = $2, resolved_by = $3, resolved_at = NOW()
+		WHERE id = $1
+		RETURNING id, accepted_comment_id, resolved_by, resolved_at
+	`, *c.ThreadID, id, actorID))
+	if err != nil {
+		return nil, err
+	}
+
+	after := *c
+	after.Accepted = true
+	if err := r.recordAudit(ctx, tx, auditModel.ActionAccept, actorID, c, &after); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Unaccept снимает принятый ответ, тред снова становится нерешённым
+func (r *CommentRepo) Unaccept(ctx context.Context, threadID, actorID string) (*model.Resolution, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	current, err := lockResolution(ctx, tx, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, owner, err := threadRootOf(ctx, tx, threadID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("thread %s not found: %w", threadID, ErrNotFound)
+		}
+		return nil, err
+	}
+	if owner != actorID {
+		return nil, fmt.Errorf("only the thread author can unaccept an answer: %w", ErrForbidden)
+	}
+	if current.AcceptedCommentID == nil {
+		return current, nil
+	}
+
+	res, err := scanResolution(tx.QueryRow(ctx, `
+		UPDATE threads
+		SET accepted_comment_id = NULL, resolved_by = NULL, resolved_at = NULL
+		WHERE id = $1
+		RETURNING id, accepted_comment_id, resolved_by, resolved_at
+	`, threadID))
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := selectComment(ctx, tx, *current.AcceptedCommentID)
+	if err != nil {
+		return nil, err
+	}
+	before.Accepted = true
+	after := *before
+	after.Accepted = false
+	if err := r.recordAudit(ctx, tx, auditModel.ActionUnaccept, actorID, before, &after); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// lockResolution читает состояние треда и блокирует строку треда до конца транзакции
+func lockResolution(ctx context.Context, tx pgx.Tx, threadID string) (*model.Resolution, error) {
+	res, err := scanResolution(tx.QueryRow(ctx, `
+		SELECT id, accepted_comment_id, resolved_by, resolved_at
+		FROM threads
+		WHERE id = $1 AND `+tenantFilter("tenant_id", 2)+`
+		FOR UPDATE
+	`, threadID, tenant.ID(ctx)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("thread %s not found: %w", threadID, ErrNotFound)
+		}
+		return nil, err
+	}
+	return res, nil
+}
+
+func scanResolution(row pgx.Row) (*model.Resolution, error) {
+	var res model.Resolution
+	if err := row.Scan(&res.ThreadID, &res.AcceptedCommentID, &res.ResolvedBy, &res.ResolvedAt); err != nil {
+		return nil, err
+	}
+	res.Resolved = res.AcceptedCommentID != nil
+	return &res, nil
+}
+
+// resolutions возвращает состояние решённых тредов из threadIDs по thread_id.
+// Учитываются только видимые публично принятые ответы
+func (r *CommentRepo) resolutions(ctx context.Context, threadIDs []string) (map[string]model.Resolution, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, accepted_comment_id, resolved_by, resolved_at
+		FROM threads
+		WHERE id = ANY($1::uuid[]) AND accepted_comment_id IS NOT NULL AND `+acceptedVisible,
+		threadIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string]model.Resolution)
+	for rows.Next() {
+		res, err := scanResolution(rows)
+		if err != nil {
+			return nil, err
+		}
+		result[res.ThreadID] = *res
+	}
+	return result, rows.Err()
+}
+
+// applyResolution отмечает принятый ответ среди комментариев треда и переносит
+// состояние треда в корень. comments — комментарии одного треда, корень первый
+func applyResolution(comments []model.Comment, res model.Resolution) {
+	if len(comments) == 0 || res.AcceptedCommentID == nil {
+		return
+	}
+	for i := range comments {
+		if comments[i].ID == *res.AcceptedCommentID {
+			comments[i].Accepted = true
+		}
+	}
+	comments[0].AcceptedAnswerID = res.AcceptedCommentID
+	comments[0].ResolvedAt = res.ResolvedAt
+}
+
+// withAccepted добавляет принятый ответ из replies в превью, если его там нет,
+// сохраняя хронологический порядок
+func withAccepted(preview, replies []model.Comment) []model.Comment {
+	for _, c := range preview {
+		if c.Accepted {
+			return preview
+		}
+	}
+	for _, c := range replies {
+		if !c.Accepted {
+			continue
+		}
+		result := make([]model.Comment, 0, len(preview)+1)
+		result = append(result, preview...)
+		result = append(result, c)
+		sort.SliceStable(result, func(i, j int) bool {
+			if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+				return result[i].CreatedAt.Before(result[j].CreatedAt)
+			}
+			return result[i].ID < result[j].ID
+		})
+		return result
+	}
+	return preview
+}
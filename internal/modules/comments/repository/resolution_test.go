@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+)
+
+func TestApplyResolution(t *testing.T) {
+	accepted := "r2"
+	resolvedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	comments := []model.Comment{{ID: "root"}, {ID: "r1"}, {ID: "r2"}}
+
+	applyResolution(comments, model.Resolution{AcceptedCommentID: &accepted, ResolvedAt: &resolvedAt})
+
+	if !comments[2].Accepted || comments[1].Accepted || comments[0].Accepted {
+		t.Fatalf("accepted flags = %v %v %v", comments[0].Accepted, comments[1].Accepted, comments[2].Accepted)
+	}
+	if root := comments[0]; root.AcceptedAnswerID == nil || *root.AcceptedAnswerID != "r2" || root.ResolvedAt == nil {
+		t.Fatalf("root = %+v, want resolved with r2", root)
+	}
+
+	open := []model.Comment{{ID: "root"}, {ID: "r1"}}
+	applyResolution(open, model.Resolution{})
+	if open[0].AcceptedAnswerID != nil || open[0].ResolvedAt != nil {
+		t.Fatal("unresolved thread is marked resolved")
+	}
+}
+
+func TestWithAccepted(t *testing.T) {
+	at := func(m int) time.Time { return time.Date(2026, 1, 1, 0, m, 0, 0, time.UTC) }
+	replies := []model.Comment{
+		{ID: "r1", CreatedAt: at(1)},
+		{ID: "r2", CreatedAt: at(2), Accepted: true},
+		{ID: "r3", CreatedAt: at(3)},
+		{ID: "r4", CreatedAt: at(4)},
+	}
+
+	// Принятый ответ добавляется в превью последних ответов на своё место по времени
+	if got := ids(withAccepted(replies[2:], replies)); !slices.Equal(got, []string{"r2", "r3", "r4"}) {
+		t.Fatalf("preview = %v", got)
+	}
+	// Уже попавший в превью ответ не дублируется
+	if got := ids(withAccepted(replies[1:3], replies)); !slices.Equal(got, []string{"r2", "r3"}) {
+		t.Fatalf("preview = %v", got)
+	}
+	// В треде без принятого ответа превью не меняется
+	if got := ids(withAccepted(replies[3:], replies[2:])); !slices.Equal(got, []string{"r4"}) {
+		t.Fatalf("preview = %v", got)
+	}
+}
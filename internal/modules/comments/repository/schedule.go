@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	auditModel "github.com/pksep/comments/internal/modules/audit/model"
+	"github.com/pksep/comments/internal/modules/comments/model"
+)
+
+// Reschedule переносит время публикации запланированного комментария. Менять его может только автор
+func (r *CommentRepo) Reschedule(ctx context.Context, id, authorID string, publishAt time.Time, expectedVersion *int) (*model.Comment, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := lockComment(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("comment with ID %s not found: %w", id, ErrNotFound)
+		}
+		return nil, err
+	}
+	if before.AuthorID != authorID {
+		return nil, fmt.Errorf("only the author can reschedule this comment: %w", ErrForbidden)
+	}
+	if before.Status != model.CommentStatusScheduled {
+		return nil, ErrNotScheduled
+	}
+	if err := checkVersion(before, expectedVersion); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE comments
+		SET publish_at = $2, created_at = $2, updated_at = NOW(), version = version + 1
+		WHERE id = $1
+	`, id, publishAt)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := selectComment(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.recordAudit(ctx, tx, auditModel.ActionUpdate, authorID, before, after); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return after, nil
+}
+
+// PublishDue публикует до limit комментариев, время публикации которых наступило, и
+// возвращает их. SKIP LOCKED позволяет нескольким репликам разбирать очередь
+// параллельно, не публикуя комментарий дважды. Комментарий, отправленный модерацией
+// на проверку, при публикации переходит в pending. В audit пишется обычный ActionCreate
+func (r *CommentRepo) PublishDue(ctx context.Context, limit int) ([]model.Comment, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		SELECT id FROM comments
+		WHERE status = 'scheduled' AND publish_at <= NOW() AND deleted_at IS NULL
+		ORDER BY publish_at
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	published := make([]*model.Comment, 0, len(ids))
+	for _, id := range ids {
+		_, err := tx.Exec(ctx, `
+			UPDATE comments
+			SET status = CASE WHEN moderation_reason IS NOT NULL THEN 'pending' ELSE 'created' END,
+			    updated_at = created_at, version = version + 1
+			WHERE id = $1
+		`, id)
+		if err != nil {
+			return nil, err
+		}
+
+		c, err := selectComment(ctx, tx, id)
+		if err != nil {
+			return nil, err
+		}
+		if c.FirstByAuthor, err = firstByAuthor(ctx, tx, c); err != nil {
+			return nil, err
+		}
+		if err := r.recordAudit(ctx, tx, auditModel.ActionCreate, c.AuthorID, nil, c); err != nil {
+			return nil, err
+		}
+		published = append(published, c)
+	}
+
+	// Счётчики тредов блокируются в одном порядке, чтобы параллельные публикации не взаимоблокировались
+	thread := func(c *model.Comment) string {
+		if c.ThreadID == nil {
+			return ""
+		}
+		return *c.ThreadID
+	}
+	sort.SliceStable(published, func(i, j int) bool {
+		return thread(published[i]) < thread(published[j])
+	})
+	for _, c := range published {
+		if err := applyThreadStats(ctx, tx, nil, c); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	out := make([]model.Comment, len(published))
+	for i, c := range published {
+		out[i] = *c
+	}
+	return out, nil
+}
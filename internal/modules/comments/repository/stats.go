@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pksep/comments/internal/modules/comments/model"
+)
+
+// statsDelta — на сколько запись комментария меняет число опубликованных комментариев треда
+func statsDelta(before, after *model.Comment) int {
+	switch {
+	case !before.Published() && after.Published():
+		return 1
+	case before.Published() && !after.Published():
+		return -1
+	}
+	return 0
+}
+
+// applyThreadStats переносит в счётчики треда изменение одного комментария в текущей
+// транзакции: before — комментарий до записи (nil для нового), after — после.
+// Меняются только счётчики, затронутые этим комментарием. Первый запрос блокирует
+// строку счётчиков, поэтому следующие видят комментарии, зафиксированные
+// параллельными записями в тот же тред
+func applyThreadStats(ctx context.Context, tx pgx.Tx, before, after *model.Comment) error {
+	delta := statsDelta(before, after)
+	if delta == 0 || after.ThreadID == nil {
+		return nil
+	}
+	threadID := *after.ThreadID
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO thread_stats (thread_id, comment_count, participants_count, updated_at)
+		VALUES ($1, $2, 0, NOW())
+		ON CONFLICT (thread_id) DO UPDATE SET
+			comment_count = thread_stats.comment_count + EXCLUDED.comment_count,
+			updated_at = EXCLUDED.updated_at
+	`, threadID, delta)
+	if err != nil {
+		return err
+	}
+
+	// Участник появляется с первым опубликованным комментарием и уходит с последним
+	_, err = tx.Exec(ctx, `
+		UPDATE thread_stats SET participants_count = participants_count + $2
+		WHERE thread_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM comments
+			WHERE thread_id = $1 AND author_id = $3 AND id <> $4 AND `+publicFilter+`)
+	`, threadID, delta, after.AuthorID, after.ID)
+	if err != nil {
+		return err
+	}
+
+	if delta > 0 {
+		_, err = tx.Exec(ctx, `
+			UPDATE thread_stats SET last_comment_id = $2, last_comment_at = $3
+			WHERE thread_id = $1
+			  AND (last_comment_at IS NULL OR (last_comment_at, last_comment_id) < ($3, $2::uuid))
+		`, threadID, after.ID, after.CreatedAt)
+		return err
+	}
+
+	// Последний комментарий ищется заново, только если уходит он сам
+	_, err = tx.Exec(ctx, `
+		UPDATE thread_stats SET (last_comment_id, last_comment_at) = (
+			SELECT id, created_at FROM comments
+			WHERE thread_id = $1 AND `+publicFilter+`
+			ORDER BY created_at DESC, id DESC LIMIT 1)
+		WHERE thread_id = $1 AND last_comment_id = $2
+	`, threadID, after.ID)
+	return err
+}
+
+// resetThreadStats обнуляет счётчики треда, все комментарии которого удалены
+func resetThreadStats(ctx context.Context, tx pgx.Tx, threadID string) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE thread_stats
+		SET comment_count = 0, participants_count = 0, last_comment_id = NULL, last_comment_at = NULL, updated_at = NOW()
+		WHERE thread_id = $1
+	`, threadID)
+	return err
+}
+
+// refreshThreadStats пересчитывает счётчики треда целиком в текущей транзакции.
+// Нужен импорту, который пишет комментарии пачкой, и для восстановления счётчиков;
+// обычные записи меняют их через applyThreadStats.
+// Блокировка строки треда упорядочивает параллельные записи в один тред,
+// поэтому пересчёт всегда видит изменения предыдущей транзакции
+func refreshThreadStats(ctx context.Context, tx pgx.Tx, threadID *string) error {
+	if threadID == nil {
+		return nil
+	}
+
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM threads WHERE id = $1 FOR UPDATE`, *threadID); err != nil {
+		return err
+	}
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO thread_stats (thread_id, comment_count, participants_count, last_comment_id, last_comment_at, updated_at)
+		SELECT $1,
+		       COUNT(*),
+		       COUNT(DISTINCT author_id),
+		       (SELECT id FROM comments
+		        WHERE thread_id = $1 AND `+publicFilter+`
+		        ORDER BY created_at DESC, id DESC LIMIT 1),
+		       MAX(created_at),
+		       NOW()
+		FROM comments
+		WHERE thread_id = $1 AND `+publicFilter+`
+		ON CONFLICT (thread_id) DO UPDATE SET
+			comment_count = EXCLUDED.comment_count,
+			participants_count = EXCLUDED.participants_count,
+			last_comment_id = EXCLUDED.last_comment_id,
+			last_comment_at = EXCLUDED.last_comment_at,
+			updated_at = EXCLUDED.updated_at
+	`, *threadID)
+	return err
+}
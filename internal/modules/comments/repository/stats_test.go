@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+)
+
+func TestStatsDelta(t *testing.T) {
+	with := func(status model.CommentStatus) *model.Comment {
+		return &model.Comment{Status: status}
+	}
+
+	cases := []struct {
+		name          string
+		before, after *model.Comment
+		want          int
+	}{
+		{"created", nil, with(model.CommentStatusCreated), 1},
+		{"created for review", nil, with(model.CommentStatusPending), 0},
+		{"scheduled", nil, with(model.CommentStatusScheduled), 0},
+		{"edited", with(model.CommentStatusCreated), with(model.CommentStatusEdited), 0},
+		{"edit held for review", with(model.CommentStatusEdited), with(model.CommentStatusPending), -1},
+		{"hidden", with(model.CommentStatusCreated), with(model.CommentStatusHidden), -1},
+		{"approved", with(model.CommentStatusHidden), with(model.CommentStatusEdited), 1},
+		{"deleted", with(model.CommentStatusEdited), with(model.CommentStatusDeleted), -1},
+		{"pending deleted", with(model.CommentStatusPending), with(model.CommentStatusDeleted), 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := statsDelta(tc.before, tc.after); got != tc.want {
+				t.Errorf("statsDelta = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// tenantFilter — условие принадлежности строки тенанту из параметра $arg запроса;
+// column — колонка tenant_id с псевдонимом таблицы, если он нужен. Пустой тенант
+// (фоновые задачи, CLI без тенанта) выборку не ограничивает
+func tenantFilter(column string, arg int) string {
+	return fmt.Sprintf("($%d::text = '' OR %s = $%d)", arg, column, arg)
+}
+
+// ensureThread создаёт тред тенанта tenantID, если его ещё нет. Тред другого тенанта
+// считается несуществующим для вызывающего
+func ensureThread(ctx context.Context, tx pgx.Tx, threadID, tenantID string) error {
+	_, err := tx.Exec(ctx, `INSERT INTO threads (id, tenant_id) VALUES ($1, $2) ON CONFLICT (id) DO NOTHING`, threadID, tenantID)
+	if err != nil {
+		return err
+	}
+
+	var owner string
+	err = tx.QueryRow(ctx, `SELECT tenant_id FROM threads WHERE id = $1`, threadID).Scan(&owner)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	// Под row-level security тред чужого тенанта не виден вовсе
+	if owner != tenantID {
+		return fmt.Errorf("thread with ID %s not found: %w", threadID, ErrNotFound)
+	}
+	return nil
+}
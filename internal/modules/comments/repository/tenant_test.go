@@ -0,0 +1,9 @@
+package repository
+
+import "testing"
+
+func TestTenantFilter(t *testing.T) {
+	if got, want := tenantFilter("c.tenant_id", 5), "($5::text = '' OR c.tenant_id = $5)"; got != want {
+		t.Fatalf("tenantFilter = %q, want %q", got, want)
+	}
+}
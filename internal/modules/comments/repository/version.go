@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+// ThreadVersion возвращает время последнего изменения треда и число его опубликованных
+// комментариев, не читая сами комментарии. nil — треда нет
+func (r *CommentRepo) ThreadVersion(ctx context.Context, threadID string) (*model.ThreadVersion, error) {
+	v := model.ThreadVersion{ThreadID: threadID}
+	err := r.db.QueryRow(ctx, `
+		SELECT t.updated_at,
+		       (SELECT COUNT(*) FROM comments WHERE thread_id = t.id AND `+publicFilter+`)
+		FROM threads t
+		WHERE t.id = $1 AND `+tenantFilter("t.tenant_id", 2),
+		threadID, tenant.ID(ctx)).Scan(&v.UpdatedAt, &v.Count)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &v, nil
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+)
+
+func TestCheckVersion(t *testing.T) {
+	c := &model.Comment{Version: 3}
+	stale, current := 2, 3
+
+	if err := checkVersion(c, nil); err != nil {
+		t.Fatalf("no expected version: %v", err)
+	}
+	if err := checkVersion(c, &current); err != nil {
+		t.Fatalf("current version: %v", err)
+	}
+	var conflict *VersionConflictError
+	if err := checkVersion(c, &stale); !errors.As(err, &conflict) || conflict.Current != 3 {
+		t.Fatalf("stale version: err = %v, want conflict with current 3", err)
+	}
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+)
+
+// Vote ставит, меняет или снимает (Value = 0) голос пользователя. Счётчики в строке
+// комментария меняются на разницу со старым голосом в той же транзакции; блокировка
+// строки комментария упорядочивает параллельные голоса. Версия комментария не меняется
+func (r *CommentRepo) Vote(ctx context.Context, vote model.Vote) (*model.VoteResult, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	c, err := lockComment(ctx, tx, vote.CommentID)
+	if err != nil {
+		return nil, err
+	}
+	if c.Status != model.CommentStatusCreated && c.Status != model.CommentStatusEdited {
+		return nil, fmt.Errorf("comment with ID %s not found: %w", vote.CommentID, ErrNotFound)
+	}
+
+	var previous int
+	err = tx.QueryRow(ctx, `
+		SELECT COALESCE((SELECT value FROM comment_votes WHERE comment_id = $1 AND voter_id = $2), 0)
+	`, vote.CommentID, vote.VoterID).Scan(&previous)
+	if err != nil {
+		return nil, err
+	}
+
+	if vote.Value == 0 {
+		_, err = tx.Exec(ctx, `DELETE FROM comment_votes WHERE comment_id = $1 AND voter_id = $2`, vote.CommentID, vote.VoterID)
+	} else {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO comment_votes (comment_id, voter_id, value, created_at, updated_at)
+			VALUES ($1, $2, $3, NOW(), NOW())
+			ON CONFLICT (comment_id, voter_id) DO UPDATE SET value = EXCLUDED.value, updated_at = NOW()
+		`, vote.CommentID, vote.VoterID, vote.Value)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	up, down := voteDelta(previous, vote.Value)
+	result := &model.VoteResult{CommentID: vote.CommentID}
+	err = tx.QueryRow(ctx, `
+		UPDATE comments
+		SET upvotes = upvotes + $2, downvotes = downvotes + $3, score = score + $2 - $3
+		WHERE id = $1
+		RETURNING upvotes, downvotes, score
+	`, vote.CommentID, up, down).Scan(&result.Upvotes, &result.Downvotes, &result.Score)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	if vote.Value != 0 {
+		result.ViewerVote = &vote.Value
+	}
+	return result, nil
+}
+
+// voteDelta — изменение счётчиков «за» и «против» при смене голоса from на to
+func voteDelta(from, to int) (up, down int) {
+	count := func(v int) (int, int) {
+		switch v {
+		case 1:
+			return 1, 0
+		case -1:
+			return 0, 1
+		default:
+			return 0, 0
+		}
+	}
+	fromUp, fromDown := count(from)
+	toUp, toDown := count(to)
+	return toUp - fromUp, toDown - fromDown
+}
+
+// sortReplies упорядочивает ответы треда; при равенстве — в порядке написания
+func sortReplies(replies []model.Comment, order model.ReplySort) {
+	switch order {
+	case model.ReplySortTop:
+		sort.SliceStable(replies, func(i, j int) bool {
+			return replies[i].Score > replies[j].Score
+		})
+	case model.ReplySortControversial:
+		sort.SliceStable(replies, func(i, j int) bool {
+			return model.Controversy(replies[i].Upvotes, replies[i].Downvotes) >
+				model.Controversy(replies[j].Upvotes, replies[j].Downvotes)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+)
+
+func TestVoteDelta(t *testing.T) {
+	cases := []struct {
+		from, to, up, down int
+	}{
+		{0, 1, 1, 0},
+		{0, -1, 0, 1},
+		{1, -1, -1, 1},
+		{-1, 1, 1, -1},
+		{1, 0, -1, 0},
+		{1, 1, 0, 0},
+	}
+	for _, tc := range cases {
+		if up, down := voteDelta(tc.from, tc.to); up != tc.up || down != tc.down {
+			t.Errorf("voteDelta(%d, %d) = %d, %d; want %d, %d", tc.from, tc.to, up, down, tc.up, tc.down)
+		}
+	}
+}
+
+func TestSortReplies(t *testing.T) {
+	replies := func() []model.Comment {
+		return []model.Comment{
+			{ID: "first", Score: 1, Upvotes: 1},
+			{ID: "best", Score: 10, Upvotes: 12, Downvotes: 2},
+			{ID: "split", Score: 0, Upvotes: 6, Downvotes: 6},
+			{ID: "tied", Score: 1, Upvotes: 3, Downvotes: 2},
+		}
+	}
+	cases := []struct {
+		order model.ReplySort
+		want  []string
+	}{
+		{model.ReplySortCreated, []string{"first", "best", "split", "tied"}},
+		// При равном рейтинге сохраняется порядок написания
+		{model.ReplySortTop, []string{"best", "first", "tied", "split"}},
+		{model.ReplySortControversial, []string{"split", "tied", "best", "first"}},
+	}
+	for _, tc := range cases {
+		r := replies()
+		sortReplies(r, tc.order)
+		if got := ids(r); !slices.Equal(got, tc.want) {
+			t.Errorf("%s: %v, want %v", tc.order, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+// ListAnchored возвращает привязанные к документу комментарии треда с ответами,
+// blockIDs ограничивает выборку блоками документа
+func (s *CommentService) ListAnchored(ctx context.Context, threadID string, blockIDs []string, opts model.ThreadOptions) ([]model.Comment, error) {
+	if err := tenant.Require(ctx, tenant.FeatureAnchors); err != nil {
+		return nil, err
+	}
+	return s.repo.ListAnchored(ctx, threadID, blockIDs, opts)
+}
+
+// RemapAnchors переносит якоря треда после изменения документа и возвращает
+// число изменённых якорей
+func (s *CommentService) RemapAnchors(ctx context.Context, threadID string, remap model.AnchorRemap) (int64, error) {
+	if err := tenant.Require(ctx, tenant.FeatureAnchors); err != nil {
+		return 0, err
+	}
+	if err := remap.Validate(); err != nil {
+		return 0, err
+	}
+	return s.repo.RemapAnchors(ctx, threadID, remap)
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+func TestCreateAnchored(t *testing.T) {
+	parent := "c0"
+	anchor := &model.Anchor{BlockID: "p1", Start: 0, End: 5}
+
+	repo := &fakeRepo{}
+	if _, err := newTestService(repo).Create(context.Background(), model.Comment{AuthorID: "a", Content: "hi", Anchor: anchor}); err != nil {
+		t.Fatal(err)
+	}
+	if repo.created == nil || repo.created.Anchor == nil {
+		t.Fatal("anchor was not saved")
+	}
+
+	cases := []struct {
+		name string
+		ctx  context.Context
+		c    model.Comment
+		want error
+	}{
+		{"reply", context.Background(), model.Comment{AuthorID: "a", Content: "hi", Anchor: anchor, AnswerCommentID: &parent}, model.ErrInvalidAnchor},
+		{"invalid range", context.Background(), model.Comment{AuthorID: "a", Content: "hi", Anchor: &model.Anchor{BlockID: "p1", Start: 5, End: 1}}, model.ErrInvalidAnchor},
+		{"disabled", tenant.WithTenant(context.Background(), tenant.Tenant{ID: "acme", Settings: tenant.Settings{
+			Features: map[tenant.Feature]bool{tenant.FeatureAnchors: false},
+		}}), model.Comment{AuthorID: "a", Content: "hi", Anchor: anchor}, tenant.ErrFeatureDisabled},
+	}
+	for _, tc := range cases {
+		repo := &fakeRepo{}
+		if _, err := newTestService(repo).Create(tc.ctx, tc.c); !errors.Is(err, tc.want) {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.want)
+		}
+		if repo.created != nil {
+			t.Errorf("%s: comment was saved", tc.name)
+		}
+	}
+}
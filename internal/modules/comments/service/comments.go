@@ -2,41 +2,186 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"log"
+	"time"
 
 	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/comments/moderation"
 	"github.com/pksep/comments/internal/modules/comments/repository"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
 )
 
+// DraftRemover удаляет черновик автора в треде после публикации комментария
+type DraftRemover interface {
+	Discard(ctx context.Context, authorID, threadID string) error
+}
+
+// Subscriber подписывает автора на тред после его первого комментария
+type Subscriber interface {
+	AutoFollow(ctx context.Context, threadID, userID string) error
+}
+
 type CommentService struct {
-	repo repository.CommentRepoInterface
+	repo          repository.CommentRepoInterface
+	moderation    *moderation.Pipeline
+	drafts        DraftRemover
+	subscriptions Subscriber
+	// число жалоб для автоскрытия комментария, 0 — отключено
+	autoHideReports int
+	// максимум закреплённых комментариев в треде, 0 — без ограничений
+	maxPinned int
 }
 
 // NewCommentService создаёт новый сервис комментариев
-func NewCommentService(repo repository.CommentRepoInterface) *CommentService {
-	return &CommentService{repo: repo}
+func NewCommentService(repo repository.CommentRepoInterface, moderation *moderation.Pipeline, drafts DraftRemover, subscriptions Subscriber, autoHideReports, maxPinned int) *CommentService {
+	return &CommentService{
+		repo:            repo,
+		moderation:      moderation,
+		drafts:          drafts,
+		subscriptions:   subscriptions,
+		autoHideReports: autoHideReports,
+		maxPinned:       maxPinned,
+	}
 }
 
 // Create создаёт новый комментарий
 func (s *CommentService) Create(ctx context.Context, c model.Comment) (*model.Comment, error) {
-	return s.repo.Create(ctx, &c)
+	if err := checkContentLength(ctx, c.Content); err != nil {
+		return nil, err
+	}
+	if c.Anchor != nil {
+		if err := tenant.Require(ctx, tenant.FeatureAnchors); err != nil {
+			return nil, err
+		}
+		if c.AnswerCommentID != nil {
+			return nil, fmt.Errorf("%w: only top-level comments can be anchored", model.ErrInvalidAnchor)
+		}
+		if err := c.Anchor.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	decision, err := s.moderate(ctx, moderation.Input{
+		AuthorID: c.AuthorID,
+		ThreadID: c.ThreadID,
+		Content:  c.Content,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if decision.Verdict == moderation.VerdictPending {
+		c.Status = model.CommentStatusPending
+		c.ModerationReason = &decision.Reason
+	}
+	// Комментарий с publish_at в будущем ждёт планировщика; решение модерации
+	// сохраняется в moderation_reason и применяется при публикации
+	if c.PublishAt != nil && c.PublishAt.After(time.Now()) {
+		if err := tenant.Require(ctx, tenant.FeatureScheduling); err != nil {
+			return nil, err
+		}
+		c.Status = model.CommentStatusScheduled
+	} else {
+		c.PublishAt = nil
+	}
+	created, err := s.repo.Create(ctx, &c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Запланированный комментарий появится в треде позже: побочные эффекты
+	// выполнит планировщик при публикации
+	if created.Status != model.CommentStatusScheduled {
+		s.afterPublish(ctx, created)
+	}
+	return created, nil
+}
+
+// afterPublish выполняется, когда комментарий появился в треде: сразу при создании
+// или при публикации по расписанию. Комментарий уже сохранён, поэтому ошибки удаления
+// черновика и автоподписки только логируются: черновик в худшем случае истечёт сам
+func (s *CommentService) afterPublish(ctx context.Context, c *model.Comment) {
+	if c.ThreadID == nil {
+		return
+	}
+	if err := s.drafts.Discard(ctx, c.AuthorID, *c.ThreadID); err != nil {
+		log.Printf("Ошибка удаления черновика автора %s в треде %s: %v", c.AuthorID, *c.ThreadID, err)
+	}
+	if !c.FirstByAuthor {
+		return
+	}
+	if err := s.subscriptions.AutoFollow(ctx, *c.ThreadID, c.AuthorID); err != nil {
+		log.Printf("Ошибка автоподписки автора %s на тред %s: %v", c.AuthorID, *c.ThreadID, err)
+	}
+}
+
+// GetByID возвращает комментарий по threadId с ответами в порядке opts.Sort
+func (s *CommentService) GetByID(ctx context.Context, threadId string, opts model.ThreadOptions) (*model.Comment, error) {
+	return s.repo.GetByID(ctx, threadId, opts)
 }
 
-// GetByID возвращает комментарий по threadId
-func (s *CommentService) GetByID(ctx context.Context, threadId string) (*model.Comment, error) {
-	return s.repo.GetByID(ctx, threadId)
+// ThreadVersion возвращает слепок состояния треда для проверки условных запросов
+func (s *CommentService) ThreadVersion(ctx context.Context, threadID string) (*model.ThreadVersion, error) {
+	return s.repo.ThreadVersion(ctx, threadID)
 }
 
-// UpdateContent обновляет контент комментария
-func (s *CommentService) UpdateContent(ctx context.Context, id string, content string, authorId string) (*model.Comment, error) {
-	return s.repo.Update(ctx, id, content, authorId)
+// GetComment возвращает один комментарий по его id
+func (s *CommentService) GetComment(ctx context.Context, id string) (*model.Comment, error) {
+	return s.repo.GetComment(ctx, id)
+}
+
+// GetCommentContext возвращает комментарий с ancestors родителями и siblings соседями с каждой стороны
+func (s *CommentService) GetCommentContext(ctx context.Context, id string, ancestors, siblings int) (*model.CommentWithContext, error) {
+	return s.repo.GetCommentContext(ctx, id, ancestors, siblings)
+}
+
+// UpdateContent обновляет контент комментария. expectedVersion (если задан)
+// защищает от перезаписи чужих изменений
+func (s *CommentService) UpdateContent(ctx context.Context, id string, content string, authorId string, expectedVersion *int) (*model.Comment, error) {
+	if err := checkContentLength(ctx, content); err != nil {
+		return nil, err
+	}
+	decision, err := s.moderate(ctx, moderation.Input{
+		CommentID: id,
+		AuthorID:  authorId,
+		Content:   content,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	status := model.CommentStatusEdited
+	var reason *string
+	if decision.Verdict == moderation.VerdictPending {
+		status = model.CommentStatusPending
+		reason = &decision.Reason
+	}
+	return s.repo.Update(ctx, id, content, authorId, status, reason, expectedVersion)
 }
 
 // Delete удаляет комментарий
-func (s *CommentService) Delete(ctx context.Context, id string, authorId string) (*model.Comment, error) {
-	return s.repo.Delete(ctx, id, authorId)
+func (s *CommentService) Delete(ctx context.Context, id string, authorId string, expectedVersion *int) (*model.Comment, error) {
+	return s.repo.Delete(ctx, id, authorId, expectedVersion)
+}
+
+// ListWithReplies возвращает root-комменты с превью ответов согласно opts
+func (s *CommentService) ListWithReplies(ctx context.Context, ids []string, opts model.ListOptions) ([]model.Comment, error) {
+	return s.repo.ListWithReplies(ctx, ids, opts)
+}
+
+// ListByAuthor возвращает комментарии автора для страницы профиля
+func (s *CommentService) ListByAuthor(ctx context.Context, filter model.AuthorFilter) ([]model.AuthorComment, error) {
+	return s.repo.ListByAuthor(ctx, filter)
 }
 
-// ListWithReplies возвращает root-комменты с ограничением replyLimit реплаев
-func (s *CommentService) ListWithReplies(ctx context.Context, ids []string, replyLimit int) ([]model.Comment, error) {
-	return s.repo.ListWithReplies(ctx, ids, replyLimit)
+// moderate прогоняет текст через конвейер модерации; отклонённый текст
+// превращается в *moderation.RejectedError
+func (s *CommentService) moderate(ctx context.Context, in moderation.Input) (moderation.Decision, error) {
+	decision, err := s.moderation.Check(ctx, in)
+	if err != nil {
+		return decision, err
+	}
+	if decision.Verdict == moderation.VerdictReject {
+		return decision, &moderation.RejectedError{Reason: decision.Reason}
+	}
+	return decision, nil
 }
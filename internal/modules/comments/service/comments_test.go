@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/comments/moderation"
+	"github.com/pksep/comments/internal/modules/comments/repository"
+)
+
+// fakeRepo запоминает, с чем сервис обратился к хранилищу. Методы, которые
+// тест не переопределил, паникуют через встроенный nil-интерфейс
+type fakeRepo struct {
+	repository.CommentRepoInterface
+	// seen — авторы, уже комментировавшие тред
+	seen    map[string]bool
+	created *model.Comment
+	updated struct {
+		status model.CommentStatus
+		reason *string
+	}
+}
+
+func (f *fakeRepo) Create(ctx context.Context, c *model.Comment) (*model.Comment, error) {
+	f.created = c
+	if c.ThreadID == nil {
+		id := "thread-1"
+		c.ThreadID = &id
+	}
+	c.FirstByAuthor = !f.seen[c.AuthorID]
+	if f.seen == nil {
+		f.seen = map[string]bool{}
+	}
+	f.seen[c.AuthorID] = true
+	return c, nil
+}
+
+func (f *fakeRepo) Update(ctx context.Context, id, content, authorID string, status model.CommentStatus, reason *string, expectedVersion *int) (*model.Comment, error) {
+	f.updated.status, f.updated.reason = status, reason
+	return &model.Comment{ID: id, Content: content, Status: status, ModerationReason: reason}, nil
+}
+
+type nopDrafts struct{}
+
+func (nopDrafts) Discard(ctx context.Context, authorID, threadID string) error { return nil }
+
+type nopSubscriber struct{}
+
+func (nopSubscriber) AutoFollow(ctx context.Context, threadID, userID string) error { return nil }
+
+func newTestService(repo *fakeRepo, checkers ...moderation.Checker) *CommentService {
+	return NewCommentService(repo, moderation.NewPipeline(checkers...), nopDrafts{}, nopSubscriber{}, 0, 0)
+}
+
+func decide(v moderation.Verdict, reason string) moderation.Checker {
+	return moderation.CheckerFunc(func(context.Context, moderation.Input) (moderation.Decision, error) {
+		return moderation.Decision{Verdict: v, Reason: reason}, nil
+	})
+}
+
+func TestCreateAppliesModerationVerdict(t *testing.T) {
+	repo := &fakeRepo{}
+	c, err := newTestService(repo, decide(moderation.VerdictPending, "too many links")).
+		Create(context.Background(), model.Comment{AuthorID: "a", Content: "hi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Status != model.CommentStatusPending || c.ModerationReason == nil || *c.ModerationReason != "too many links" {
+		t.Fatalf("status = %q, reason = %v; want pending with reason", c.Status, c.ModerationReason)
+	}
+
+	repo = &fakeRepo{}
+	_, err = newTestService(repo, decide(moderation.VerdictReject, "blocked word")).
+		Create(context.Background(), model.Comment{AuthorID: "a", Content: "hi"})
+	var rejected *moderation.RejectedError
+	if !errors.As(err, &rejected) || rejected.Reason != "blocked word" {
+		t.Fatalf("err = %v, want RejectedError", err)
+	}
+	if repo.created != nil {
+		t.Fatal("rejected comment was saved")
+	}
+}
+
+func TestUpdateAppliesModerationVerdict(t *testing.T) {
+	repo := &fakeRepo{}
+	if _, err := newTestService(repo).UpdateContent(context.Background(), "c1", "hi", "a", nil); err != nil {
+		t.Fatal(err)
+	}
+	if repo.updated.status != model.CommentStatusEdited || repo.updated.reason != nil {
+		t.Fatalf("accepted edit: status = %q, reason = %v", repo.updated.status, repo.updated.reason)
+	}
+
+	repo = &fakeRepo{}
+	if _, err := newTestService(repo, decide(moderation.VerdictPending, "held")).UpdateContent(context.Background(), "c1", "hi", "a", nil); err != nil {
+		t.Fatal(err)
+	}
+	if repo.updated.status != model.CommentStatusPending || repo.updated.reason == nil || *repo.updated.reason != "held" {
+		t.Fatalf("held edit: status = %q, reason = %v", repo.updated.status, repo.updated.reason)
+	}
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+)
+
+type draftLog struct {
+	discarded [][2]string
+	err       error
+}
+
+func (d *draftLog) Discard(ctx context.Context, authorID, threadID string) error {
+	d.discarded = append(d.discarded, [2]string{authorID, threadID})
+	return d.err
+}
+
+func TestCreateDiscardsDraft(t *testing.T) {
+	thread := "t1"
+	drafts := &draftLog{}
+	s := NewCommentService(&fakeRepo{}, nil, drafts, nopSubscriber{}, 0, 0)
+	if _, err := s.Create(context.Background(), model.Comment{AuthorID: "a", Content: "hi", ThreadID: &thread}); err != nil {
+		t.Fatal(err)
+	}
+	if len(drafts.discarded) != 1 || drafts.discarded[0] != [2]string{"a", "t1"} {
+		t.Fatalf("discarded = %v, want the author's draft in t1", drafts.discarded)
+	}
+
+	// Комментарий уже сохранён: ошибка удаления черновика не проваливает создание
+	drafts = &draftLog{err: errors.New("db down")}
+	s = NewCommentService(&fakeRepo{}, nil, drafts, nopSubscriber{}, 0, 0)
+	if _, err := s.Create(context.Background(), model.Comment{AuthorID: "a", Content: "hi", ThreadID: &thread}); err != nil {
+		t.Fatalf("create failed because of the draft: %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"io"
+
+	"github.com/pksep/comments/internal/modules/comments/export"
+	"github.com/pksep/comments/internal/modules/comments/model"
+)
+
+// ExportThread выгружает тред в w в указанном формате, передавая комментарии
+// из базы по одному. Пока не найден первый комментарий, в w ничего не пишется,
+// поэтому при ErrNotFound ответ ещё можно заменить на ошибку
+func (s *CommentService) ExportThread(ctx context.Context, threadID string, format export.Format, includeHidden bool, w io.Writer) error {
+	ew, err := export.NewWriter(format, w)
+	if err != nil {
+		return err
+	}
+
+	started := false
+	err = s.repo.StreamThread(ctx, threadID, includeHidden, func(c model.Comment, depth int) error {
+		if !started {
+			started = true
+			if err := ew.Begin(threadID); err != nil {
+				return err
+			}
+		}
+		return ew.Write(c, depth)
+	})
+	if err != nil {
+		return err
+	}
+	return ew.End()
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pksep/comments/internal/modules/comments/export"
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/comments/repository"
+)
+
+type streamRepo struct {
+	fakeRepo
+	thread []model.Comment
+	hidden bool
+}
+
+func (r *streamRepo) StreamThread(ctx context.Context, threadID string, includeHidden bool, fn func(c model.Comment, depth int) error) error {
+	r.hidden = includeHidden
+	if len(r.thread) == 0 {
+		return repository.ErrNotFound
+	}
+	for _, c := range r.thread {
+		if err := fn(c, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestExportThreadWritesNothingForMissingThread(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewCommentService(&streamRepo{}, nil, nopDrafts{}, nopSubscriber{}, 0, 0)
+	err := s.ExportThread(context.Background(), "t1", export.FormatJSON, false, &buf)
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("wrote %q before the first comment", buf.String())
+	}
+}
+
+func TestExportThread(t *testing.T) {
+	var buf bytes.Buffer
+	repo := &streamRepo{thread: []model.Comment{{ID: "c1"}}}
+	s := NewCommentService(repo, nil, nopDrafts{}, nopSubscriber{}, 0, 0)
+	if err := s.ExportThread(context.Background(), "t1", export.FormatCSV, true, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !repo.hidden {
+		t.Fatal("includeHidden is not passed to the repository")
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("c1,")) {
+		t.Fatalf("export = %q", buf.String())
+	}
+
+	if err := s.ExportThread(context.Background(), "t1", "xml", false, &buf); !errors.Is(err, export.ErrUnknownFormat) {
+		t.Fatalf("err = %v, want ErrUnknownFormat", err)
+	}
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+// Report регистрирует жалобу пользователя на комментарий
+func (s *CommentService) Report(ctx context.Context, commentID, reporterID, reason string) (*model.Report, error) {
+	if err := tenant.Require(ctx, tenant.FeatureReports); err != nil {
+		return nil, err
+	}
+	return s.repo.Report(ctx, &model.Report{
+		CommentID:  commentID,
+		ReporterID: reporterID,
+		Reason:     reason,
+	}, s.autoHideThreshold(ctx))
+}
+
+// ModerationQueue возвращает очередь модерации
+func (s *CommentService) ModerationQueue(ctx context.Context, filter model.QueueFilter, limit, offset int) ([]model.QueueItem, error) {
+	return s.repo.ListModerationQueue(ctx, filter, limit, offset)
+}
+
+// GetThreadForModerator возвращает тред со скрытыми комментариями
+func (s *CommentService) GetThreadForModerator(ctx context.Context, threadID string) (*model.Comment, error) {
+	return s.repo.GetThreadForModerator(ctx, threadID)
+}
+
+// Approve публикует комментарий и закрывает жалобы на него
+func (s *CommentService) Approve(ctx context.Context, id, moderatorID string, reason *string) (*model.Comment, error) {
+	return s.repo.Approve(ctx, id, moderatorID, reason)
+}
+
+// Hide скрывает комментарий из публичного чтения
+func (s *CommentService) Hide(ctx context.Context, id, moderatorID, reason string) (*model.Comment, error) {
+	return s.repo.Hide(ctx, id, moderatorID, reason)
+}
+
+// ModeratorDelete удаляет комментарий от имени модератора
+func (s *CommentService) ModeratorDelete(ctx context.Context, id, moderatorID, reason string) (*model.Comment, error) {
+	return s.repo.ModeratorDelete(ctx, id, moderatorID, reason)
+}
+
+// Restore восстанавливает удалённый комментарий
+func (s *CommentService) Restore(ctx context.Context, id, moderatorID, reason string) (*model.Comment, error) {
+	return s.repo.Restore(ctx, id, moderatorID, reason)
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+type reportRepo struct {
+	fakeRepo
+	threshold int
+	reported  bool
+}
+
+func (r *reportRepo) Report(ctx context.Context, report *model.Report, autoHideThreshold int) (*model.Report, error) {
+	r.reported, r.threshold = true, autoHideThreshold
+	return report, nil
+}
+
+func TestReportAutoHideThreshold(t *testing.T) {
+	zero, five := 0, 5
+	cases := []struct {
+		name     string
+		settings *tenant.Settings
+		want     int
+	}{
+		{"global threshold outside a tenant", nil, 3},
+		{"global threshold when tenant keeps default", &tenant.Settings{}, 3},
+		{"tenant threshold", &tenant.Settings{AutoHideReports: &five}, 5},
+		{"tenant disables auto-hide", &tenant.Settings{AutoHideReports: &zero}, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.settings != nil {
+				ctx = tenant.WithTenant(ctx, tenant.Tenant{ID: "acme", Settings: *tc.settings})
+			}
+			repo := &reportRepo{}
+			s := NewCommentService(repo, nil, nopDrafts{}, nopSubscriber{}, 3, 0)
+			if _, err := s.Report(ctx, "c1", "u1", "spam"); err != nil {
+				t.Fatal(err)
+			}
+			if repo.threshold != tc.want {
+				t.Errorf("threshold = %d, want %d", repo.threshold, tc.want)
+			}
+		})
+	}
+}
+
+func TestReportDisabledForTenant(t *testing.T) {
+	ctx := tenant.WithTenant(context.Background(), tenant.Tenant{ID: "acme", Settings: tenant.Settings{
+		Features: map[tenant.Feature]bool{tenant.FeatureReports: false},
+	}})
+	repo := &reportRepo{}
+	_, err := NewCommentService(repo, nil, nopDrafts{}, nopSubscriber{}, 3, 0).Report(ctx, "c1", "u1", "spam")
+	if !errors.Is(err, tenant.ErrFeatureDisabled) {
+		t.Fatalf("err = %v, want ErrFeatureDisabled", err)
+	}
+	if repo.reported {
+		t.Fatal("report was saved")
+	}
+}
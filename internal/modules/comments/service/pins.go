@@ -0,0 +1,21 @@
+package service
+
+import (
+	"context"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+// Pin закрепляет комментарий в треде от имени владельца треда
+func (s *CommentService) Pin(ctx context.Context, id, actorID string) (*model.Comment, error) {
+	if err := tenant.Require(ctx, tenant.FeaturePins); err != nil {
+		return nil, err
+	}
+	return s.repo.Pin(ctx, id, actorID, s.pinLimit(ctx))
+}
+
+// Unpin снимает закрепление комментария
+func (s *CommentService) Unpin(ctx context.Context, id, actorID string) (*model.Comment, error) {
+	return s.repo.Unpin(ctx, id, actorID)
+}
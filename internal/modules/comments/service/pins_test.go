@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+type pinRepo struct {
+	fakeRepo
+	limit  int
+	pinned bool
+}
+
+func (r *pinRepo) Pin(ctx context.Context, id, actorID string, maxPinned int) (*model.Comment, error) {
+	r.pinned, r.limit = true, maxPinned
+	return &model.Comment{ID: id}, nil
+}
+
+func TestPinLimit(t *testing.T) {
+	one := 1
+	cases := []struct {
+		name string
+		ctx  context.Context
+		want int
+	}{
+		{"global limit", context.Background(), 3},
+		{"tenant keeps global limit", tenant.WithTenant(context.Background(), tenant.Tenant{ID: "acme"}), 3},
+		{"tenant limit", tenant.WithTenant(context.Background(), tenant.Tenant{ID: "acme", Settings: tenant.Settings{MaxPinnedPerThread: &one}}), 1},
+	}
+	for _, tc := range cases {
+		repo := &pinRepo{}
+		if _, err := NewCommentService(repo, nil, nopDrafts{}, nopSubscriber{}, 0, 3).Pin(tc.ctx, "c1", "owner"); err != nil {
+			t.Fatal(err)
+		}
+		if repo.limit != tc.want {
+			t.Errorf("%s: limit = %d, want %d", tc.name, repo.limit, tc.want)
+		}
+	}
+}
+
+func TestPinDisabledForTenant(t *testing.T) {
+	ctx := tenant.WithTenant(context.Background(), tenant.Tenant{ID: "acme", Settings: tenant.Settings{
+		Features: map[tenant.Feature]bool{tenant.FeaturePins: false},
+	}})
+	repo := &pinRepo{}
+	if _, err := NewCommentService(repo, nil, nopDrafts{}, nopSubscriber{}, 0, 3).Pin(ctx, "c1", "owner"); !errors.Is(err, tenant.ErrFeatureDisabled) {
+		t.Fatalf("err = %v, want ErrFeatureDisabled", err)
+	}
+	if repo.pinned {
+		t.Fatal("comment was pinned")
+	}
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+// Accept отмечает ответ принятым от имени владельца треда, тред становится решённым
+func (s *CommentService) Accept(ctx context.Context, id, actorID string) (*model.Resolution, error) {
+	if err := tenant.Require(ctx, tenant.FeatureAnswers); err != nil {
+		return nil, err
+	}
+	return s.repo.Accept(ctx, id, actorID)
+}
+
+// Unaccept снимает принятый ответ треда
+func (s *CommentService) Unaccept(ctx context.Context, threadID, actorID string) (*model.Resolution, error) {
+	if err := tenant.Require(ctx, tenant.FeatureAnswers); err != nil {
+		return nil, err
+	}
+	return s.repo.Unaccept(ctx, threadID, actorID)
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+// publishBatchSize — сколько запланированных комментариев публикуется в одной транзакции
+const publishBatchSize = 100
+
+// Reschedule переносит публикацию запланированного комментария на publishAt
+func (s *CommentService) Reschedule(ctx context.Context, id, authorID string, publishAt time.Time, expectedVersion *int) (*model.Comment, error) {
+	if err := tenant.Require(ctx, tenant.FeatureScheduling); err != nil {
+		return nil, err
+	}
+	if !publishAt.After(time.Now()) {
+		return nil, model.ErrInvalidPublishAt
+	}
+	return s.repo.Reschedule(ctx, id, authorID, publishAt, expectedVersion)
+}
+
+// RunScheduler периодически публикует комментарии, время которых наступило, пока не отменён ctx.
+// Может работать одновременно на нескольких репликах
+func (s *CommentService) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.publishDue(ctx)
+		}
+	}
+}
+
+// publishDue разбирает очередь пачками, пока она не опустеет
+func (s *CommentService) publishDue(ctx context.Context) {
+	for {
+		published, err := s.repo.PublishDue(ctx, publishBatchSize)
+		if err != nil {
+			log.Printf("Ошибка публикации запланированных комментариев: %v", err)
+			return
+		}
+		for i := range published {
+			s.afterPublish(ctx, &published[i])
+		}
+		n := len(published)
+		if n > 0 {
+			log.Printf("Опубликовано запланированных комментариев: %d", n)
+		}
+		if n < publishBatchSize {
+			return
+		}
+	}
+}
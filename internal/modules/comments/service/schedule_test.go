@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+func TestCreateSchedulesFutureComments(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	repo := &fakeRepo{}
+	c, err := newTestService(repo).Create(context.Background(), model.Comment{AuthorID: "a", Content: "hi", PublishAt: &future})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Status != model.CommentStatusScheduled || c.PublishAt == nil {
+		t.Fatalf("status = %q, publish_at = %v; want scheduled", c.Status, c.PublishAt)
+	}
+
+	// Время в прошлом означает немедленную публикацию
+	repo = &fakeRepo{}
+	c, err = newTestService(repo).Create(context.Background(), model.Comment{AuthorID: "a", Content: "hi", PublishAt: &past})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Status == model.CommentStatusScheduled || c.PublishAt != nil {
+		t.Fatalf("status = %q, publish_at = %v; want published now", c.Status, c.PublishAt)
+	}
+
+	ctx := tenant.WithTenant(context.Background(), tenant.Tenant{ID: "acme", Settings: tenant.Settings{
+		Features: map[tenant.Feature]bool{tenant.FeatureScheduling: false},
+	}})
+	repo = &fakeRepo{}
+	if _, err := newTestService(repo).Create(ctx, model.Comment{AuthorID: "a", Content: "hi", PublishAt: &future}); !errors.Is(err, tenant.ErrFeatureDisabled) {
+		t.Fatalf("err = %v, want ErrFeatureDisabled", err)
+	}
+	if repo.created != nil {
+		t.Fatal("comment was saved with scheduling disabled")
+	}
+}
+
+type scheduleRepo struct {
+	fakeRepo
+	rescheduled bool
+	due         []int
+	calls       int
+}
+
+func (r *scheduleRepo) Reschedule(ctx context.Context, id, authorID string, publishAt time.Time, expectedVersion *int) (*model.Comment, error) {
+	r.rescheduled = true
+	return &model.Comment{ID: id, Status: model.CommentStatusScheduled, PublishAt: &publishAt}, nil
+}
+
+func (r *scheduleRepo) PublishDue(ctx context.Context, limit int) ([]model.Comment, error) {
+	thread := "t1"
+	published := make([]model.Comment, r.due[r.calls])
+	for i := range published {
+		published[i] = model.Comment{AuthorID: "a", ThreadID: &thread, FirstByAuthor: i == 0}
+	}
+	r.calls++
+	return published, nil
+}
+
+func TestReschedule(t *testing.T) {
+	repo := &scheduleRepo{}
+	s := NewCommentService(repo, nil, nopDrafts{}, nopSubscriber{}, 0, 0)
+
+	if _, err := s.Reschedule(context.Background(), "c1", "a", time.Now().Add(-time.Minute), nil); !errors.Is(err, model.ErrInvalidPublishAt) {
+		t.Fatalf("err = %v, want ErrInvalidPublishAt", err)
+	}
+	if repo.rescheduled {
+		t.Fatal("past time reached the repository")
+	}
+	if _, err := s.Reschedule(context.Background(), "c1", "a", time.Now().Add(time.Hour), nil); err != nil || !repo.rescheduled {
+		t.Fatalf("err = %v, rescheduled = %v", err, repo.rescheduled)
+	}
+}
+
+func TestPublishDueDrainsQueue(t *testing.T) {
+	cases := []struct {
+		due  []int
+		want int
+	}{
+		{[]int{0}, 1},
+		{[]int{publishBatchSize - 1}, 1},
+		// Полная пачка значит, что в очереди могло остаться ещё
+		{[]int{publishBatchSize, publishBatchSize, 3}, 3},
+		{[]int{publishBatchSize, 0}, 2},
+	}
+	for _, tc := range cases {
+		repo := &scheduleRepo{due: tc.due}
+		NewCommentService(repo, nil, nopDrafts{}, nopSubscriber{}, 0, 0).publishDue(context.Background())
+		if repo.calls != tc.want {
+			t.Errorf("due %v: %d batches, want %d", tc.due, repo.calls, tc.want)
+		}
+	}
+}
+
+func TestScheduledSideEffectsOnPublish(t *testing.T) {
+	thread := "t1"
+	future := time.Now().Add(time.Hour)
+	drafts, subs := &draftLog{}, &followLog{}
+	repo := &scheduleRepo{due: []int{2}}
+	s := NewCommentService(repo, nil, drafts, subs, 0, 0)
+
+	if _, err := s.Create(context.Background(), model.Comment{AuthorID: "a", Content: "hi", ThreadID: &thread, PublishAt: &future}); err != nil {
+		t.Fatal(err)
+	}
+	if len(drafts.discarded) != 0 || len(subs.followed) != 0 {
+		t.Fatalf("scheduled comment: discarded %v, followed %v; want nothing until publication", drafts.discarded, subs.followed)
+	}
+
+	s.publishDue(context.Background())
+	if len(drafts.discarded) != 2 || len(subs.followed) != 1 {
+		t.Fatalf("after publication: discarded %v, followed %v; want a discard per comment and one follow", drafts.discarded, subs.followed)
+	}
+}
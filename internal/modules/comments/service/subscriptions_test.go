@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+)
+
+type followLog struct {
+	followed [][2]string
+}
+
+func (f *followLog) AutoFollow(ctx context.Context, threadID, userID string) error {
+	f.followed = append(f.followed, [2]string{threadID, userID})
+	return nil
+}
+
+func TestCreateAutoFollowsThread(t *testing.T) {
+	thread := "t1"
+	subs := &followLog{}
+	s := NewCommentService(&fakeRepo{}, nil, nopDrafts{}, subs, 0, 0)
+	// Второй комментарий не подписывает снова: отписка после первого сохраняется
+	for range 2 {
+		if _, err := s.Create(context.Background(), model.Comment{AuthorID: "a", Content: "hi", ThreadID: &thread}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(subs.followed) != 1 || subs.followed[0] != [2]string{"t1", "a"} {
+		t.Fatalf("followed = %v, want the author on t1 once", subs.followed)
+	}
+}
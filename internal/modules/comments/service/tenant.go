@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+// autoHideThreshold — порог автоскрытия: настройка тенанта запроса или глобальная
+func (s *CommentService) autoHideThreshold(ctx context.Context) int {
+	if t, ok := tenant.FromContext(ctx); ok && t.Settings.AutoHideReports != nil {
+		return *t.Settings.AutoHideReports
+	}
+	return s.autoHideReports
+}
+
+// pinLimit — лимит закреплённых: настройка тенанта запроса или глобальная
+func (s *CommentService) pinLimit(ctx context.Context) int {
+	if t, ok := tenant.FromContext(ctx); ok && t.Settings.MaxPinnedPerThread != nil {
+		return *t.Settings.MaxPinnedPerThread
+	}
+	return s.maxPinned
+}
+
+// checkContentLength проверяет длину текста по лимиту тенанта запроса
+func checkContentLength(ctx context.Context, content string) error {
+	t, ok := tenant.FromContext(ctx)
+	if !ok || t.Settings.MaxContentLength == 0 {
+		return nil
+	}
+	if n := utf8.RuneCountInString(content); n > t.Settings.MaxContentLength {
+		return fmt.Errorf("%w: %d > %d characters", model.ErrContentTooLong, n, t.Settings.MaxContentLength)
+	}
+	return nil
+}
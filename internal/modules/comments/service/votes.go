@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+
+	"github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+// Vote ставит голос пользователя за комментарий (1 или -1) или снимает его (0)
+func (s *CommentService) Vote(ctx context.Context, vote model.Vote) (*model.VoteResult, error) {
+	if err := tenant.Require(ctx, tenant.FeatureVotes); err != nil {
+		return nil, err
+	}
+	return s.repo.Vote(ctx, vote)
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pksep/comments/internal/modules/digest/service"
+)
+
+type DigestHandler struct {
+	service *service.DigestService
+}
+
+func NewDigestHandler(service *service.DigestService) *DigestHandler {
+	return &DigestHandler{service: service}
+}
+
+func (h *DigestHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	digests := rg.Group("/digests")
+	{
+		digests.GET("/:userId/preview", h.Preview) // сводка и письмо без отправки
+		digests.POST("/run", h.Run)                // внеочередная рассылка
+	}
+}
+
+func (h *DigestHandler) Preview(c *gin.Context) {
+	digest, msg, err := h.service.Preview(c, c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"digest": digest, "message": msg})
+}
+
+func (h *DigestHandler) Run(c *gin.Context) {
+	sent, err := h.service.RunOnce(c)
+	if err != nil {
+		if errors.Is(err, service.ErrNotifierDisabled) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "sent": sent})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"sent": sent})
+}
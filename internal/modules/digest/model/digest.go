@@ -0,0 +1,55 @@
+package model
+
+import "time"
+
+// ItemKind — почему комментарий попал в сводку пользователя
+type ItemKind string
+
+const (
+	// ItemReply — ответ на комментарий пользователя
+	ItemReply ItemKind = "reply"
+	// ItemMention — упоминание пользователя через @id
+	ItemMention ItemKind = "mention"
+	// ItemThread — новый комментарий в треде, где пользователь участвовал
+	ItemThread ItemKind = "thread"
+)
+
+// Item — комментарий в сводке
+type Item struct {
+	Kind      ItemKind  `json:"kind"`
+	CommentID string    `json:"comment_id"`
+	ThreadID  string    `json:"thread_id"`
+	AuthorID  string    `json:"author_id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Digest — сводка для пользователя за окно (Since, Until]. Items ограничены
+// сверху, Total — полное число комментариев в окне
+type Digest struct {
+	UserID string    `json:"user_id"`
+	Since  time.Time `json:"since"`
+	Until  time.Time `json:"until"`
+	Items  []Item    `json:"items"`
+	Total  int       `json:"total"`
+}
+
+// Empty сообщает, что в окне нет ничего нового
+func (d *Digest) Empty() bool {
+	return d.Total == 0
+}
+
+// State — состояние рассылки пользователя
+type State struct {
+	UserID      string     `json:"user_id" db:"user_id"`
+	LastSentAt  *time.Time `json:"last_sent_at,omitempty" db:"last_sent_at"`
+	DigestsSent int        `json:"digests_sent" db:"digests_sent"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Message — отрисованная сводка, которую доставляет Notifier
+type Message struct {
+	UserID  string `json:"user_id"`
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+}
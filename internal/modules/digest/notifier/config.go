@@ -0,0 +1,30 @@
+package notifier
+
+import (
+	"fmt"
+
+	"github.com/pksep/comments/internal/config"
+)
+
+// NewNotifierFromConfig создаёт Notifier согласно конфигурации.
+// Пустой DIGEST_NOTIFIER отключает рассылку, тогда возвращается nil
+func NewNotifierFromConfig(cfg config.DigestConfig) (Notifier, error) {
+	switch cfg.Notifier {
+	case "":
+		return nil, nil
+	case "log":
+		return NewLogNotifier(), nil
+	case "file":
+		if cfg.File == "" {
+			return nil, fmt.Errorf("DIGEST_FILE is required for file notifier")
+		}
+		return NewFileNotifier(cfg.File)
+	case "smtp":
+		if cfg.SMTP.Addr == "" || cfg.SMTP.From == "" {
+			return nil, fmt.Errorf("SMTP_ADDR and SMTP_FROM are required for smtp notifier")
+		}
+		return NewSMTPNotifier(cfg.SMTP.Addr, cfg.SMTP.From, cfg.SMTP.Username, cfg.SMTP.Password, cfg.RecipientFormat), nil
+	default:
+		return nil, fmt.Errorf("unknown digest notifier %q", cfg.Notifier)
+	}
+}
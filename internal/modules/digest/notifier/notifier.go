@@ -0,0 +1,59 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+
+	"github.com/pksep/comments/internal/modules/digest/model"
+)
+
+// Notifier — точка расширения доставки сводок: почта, мессенджеры, очереди.
+// Ошибка означает, что сводка не доставлена и её нужно отправить повторно
+type Notifier interface {
+	Notify(ctx context.Context, msg model.Message) error
+}
+
+// NotifierFunc позволяет использовать обычную функцию как Notifier
+type NotifierFunc func(ctx context.Context, msg model.Message) error
+
+func (f NotifierFunc) Notify(ctx context.Context, msg model.Message) error {
+	return f(ctx, msg)
+}
+
+// WriterNotifier пишет сводки в текстовом виде в io.Writer. Подходит для локальной
+// отладки: в журнал сервиса или в файл
+type WriterNotifier struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterNotifier создаёт Notifier, пишущий в w
+func NewWriterNotifier(w io.Writer) *WriterNotifier {
+	return &WriterNotifier{w: w}
+}
+
+// NewLogNotifier создаёт Notifier, пишущий в журнал сервиса
+func NewLogNotifier() *WriterNotifier {
+	return NewWriterNotifier(log.Writer())
+}
+
+// NewFileNotifier создаёт Notifier, дописывающий сводки в файл path
+func NewFileNotifier(path string) (*WriterNotifier, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open digest file: %w", err)
+	}
+	return NewWriterNotifier(f), nil
+}
+
+func (n *WriterNotifier) Notify(ctx context.Context, msg model.Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	_, err := fmt.Fprintf(n.w, "=== digest for %s\nSubject: %s\n\n%s\n", msg.UserID, msg.Subject, msg.Text)
+	return err
+}
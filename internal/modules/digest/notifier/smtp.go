@@ -0,0 +1,121 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/pksep/comments/internal/modules/digest/model"
+)
+
+// SMTPNotifier отправляет сводки письмом. STARTTLS используется, если сервер его
+// поддерживает; без Username письмо отправляется без авторизации (локальный тестовый сервер)
+type SMTPNotifier struct {
+	addr     string
+	from     string
+	username string
+	password string
+	// recipientFormat — формат адреса получателя, %s заменяется на id пользователя
+	recipientFormat string
+	timeout         time.Duration
+}
+
+// NewSMTPNotifier создаёт Notifier для SMTP-сервера addr (host:port)
+func NewSMTPNotifier(addr, from, username, password, recipientFormat string) *SMTPNotifier {
+	return &SMTPNotifier{
+		addr:            addr,
+		from:            from,
+		username:        username,
+		password:        password,
+		recipientFormat: recipientFormat,
+		timeout:         30 * time.Second,
+	}
+}
+
+func (n *SMTPNotifier) Notify(ctx context.Context, msg model.Message) error {
+	to := fmt.Sprintf(n.recipientFormat, msg.UserID)
+	body, err := n.compose(to, msg)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, n.timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", n.addr)
+	if err != nil {
+		return fmt.Errorf("smtp dial: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	host, _, _ := net.SplitHostPort(n.addr)
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp handshake: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return fmt.Errorf("smtp starttls: %w", err)
+		}
+	}
+	if n.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", n.username, n.password, host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+	if err := client.Mail(n.from); err != nil {
+		return fmt.Errorf("smtp mail from: %w", err)
+	}
+	if err := client.Rcpt(to); err != nil {
+		return fmt.Errorf("smtp rcpt to: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("smtp write: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	return client.Quit()
+}
+
+// compose собирает письмо: заголовки и текст в quoted-printable, тема в кодировке RFC 2047
+func (n *SMTPNotifier) compose(to string, msg model.Message) ([]byte, error) {
+	var buf bytes.Buffer
+	headers := []string{
+		"From: " + n.from,
+		"To: " + to,
+		"Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=utf-8",
+		"Content-Transfer-Encoding: quoted-printable",
+	}
+	buf.WriteString(strings.Join(headers, "\r\n"))
+	buf.WriteString("\r\n\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(msg.Text, "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,136 @@
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/pksep/comments/internal/modules/digest/model"
+)
+
+// envelope — то, что фейковый SMTP-сервер получил от клиента
+type envelope struct {
+	from string
+	to   []string
+	data string
+}
+
+// serveSMTP принимает одно соединение и отвечает минимальным диалогом SMTP без расширений
+func serveSMTP(t *testing.T, ln net.Listener, got chan<- envelope) {
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Error(err)
+		close(got)
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+	var env envelope
+
+	reply("220 fake ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Error(err)
+			close(got)
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 fake")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			env.from = line[len("MAIL FROM:"):]
+			reply("250 ok")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			env.to = append(env.to, line[len("RCPT TO:"):])
+			reply("250 ok")
+		case cmd == "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					t.Error(err)
+					close(got)
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(l, "."))
+			}
+			env.data = data.String()
+			reply("250 queued")
+		case cmd == "QUIT":
+			reply("221 bye")
+			got <- env
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func TestSMTPNotifierSendsDigest(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	got := make(chan envelope, 1)
+	go serveSMTP(t, ln, got)
+
+	n := NewSMTPNotifier(ln.Addr().String(), "digest@example.com", "", "", "%s@users.example.com")
+	msg := model.Message{
+		UserID:  "u1",
+		Subject: "Новые ответы",
+		Text:    "Первая строка\nВторая строка",
+	}
+	if err := n.Notify(context.Background(), msg); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	env, ok := <-got
+	if !ok {
+		t.FailNow()
+	}
+	if env.from != "<digest@example.com>" {
+		t.Errorf("MAIL FROM = %q", env.from)
+	}
+	if len(env.to) != 1 || env.to[0] != "<u1@users.example.com>" {
+		t.Errorf("RCPT TO = %q", env.to)
+	}
+
+	m, err := mail.ReadMessage(strings.NewReader(env.data))
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+	if to := m.Header.Get("To"); to != "u1@users.example.com" {
+		t.Errorf("To = %q", to)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject"))
+	if err != nil || subject != msg.Subject {
+		t.Errorf("Subject = %q (%v), want %q", subject, err, msg.Subject)
+	}
+	if ct := m.Header.Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	body, err := io.ReadAll(quotedprintable.NewReader(m.Body))
+	if err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if want := "Первая строка\r\nВторая строка"; strings.TrimRight(string(body), "\r\n") != want {
+		t.Errorf("body = %q, want %q", body, want)
+	}
+}
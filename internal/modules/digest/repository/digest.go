@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pksep/comments/internal/modules/digest/model"
+)
+
+// visibleFilter — опубликованные комментарии, как в публичном чтении тредов
+const visibleFilter = `c.deleted_at IS NULL AND c.status IN ('created', 'edited')`
+
+type DigestRepoInterface interface {
+	Due(ctx context.Context, dueBefore time.Time, afterUserID string, limit int) ([]model.State, error)
+	GetState(ctx context.Context, userID string) (*model.State, error)
+	Items(ctx context.Context, userID string, since, until time.Time, limit int) ([]model.Item, int, error)
+	Claim(ctx context.Context, userID string, prev *time.Time, until time.Time) (bool, error)
+	Release(ctx context.Context, userID string, claimed time.Time, prev *time.Time) error
+	MarkSent(ctx context.Context, userID string) error
+}
+
+type DigestRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewDigestRepo(db *pgxpool.Pool) *DigestRepo {
+	return &DigestRepo{db: db}
+}
+
+// Due возвращает участников обсуждений, чья последняя сводка отправлена не позже dueBefore
+// или ещё не отправлялась. Пользователи перебираются по возрастанию id начиная после afterUserID
+func (r *DigestRepo) Due(ctx context.Context, dueBefore time.Time, afterUserID string, limit int) ([]model.State, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT p.author_id, s.last_sent_at, COALESCE(s.digests_sent, 0), COALESCE(s.updated_at, NOW())
+		FROM (
+			SELECT DISTINCT c.author_id
+			FROM comments c
+			WHERE c.author_id > $2 AND `+visibleFilter+`
+		) p
+		LEFT JOIN digest_state s ON s.user_id = p.author_id
+		WHERE s.last_sent_at IS NULL OR s.last_sent_at <= $1
+		ORDER BY p.author_id
+		LIMIT $3
+	`, dueBefore, afterUserID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var states []model.State
+	for rows.Next() {
+		var s model.State
+		if err := rows.Scan(&s.UserID, &s.LastSentAt, &s.DigestsSent, &s.UpdatedAt); err != nil {
+			return nil, err
+		}
+		states = append(states, s)
+	}
+	return states, rows.Err()
+}
+
+// GetState возвращает состояние рассылки пользователя или nil, если сводок ещё не было
+func (r *DigestRepo) GetState(ctx context.Context, userID string) (*model.State, error) {
+	var s model.State
+	err := r.db.QueryRow(ctx, `
+		SELECT user_id, last_sent_at, digests_sent, updated_at
+		FROM digest_state
+		WHERE user_id = $1
+	`, userID).Scan(&s.UserID, &s.LastSentAt, &s.DigestsSent, &s.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Items возвращает до limit чужих комментариев окна (since, until], интересных пользователю:
+// ответы на его комментарии, упоминания @userID и новое в тредах, где он участвовал.
+// Заглушённые треды пропускаются. Каждый комментарий попадает в сводку один раз
+// с самым сильным поводом. Второе значение — полное число таких комментариев
+func (r *DigestRepo) Items(ctx context.Context, userID string, since, until time.Time, limit int) ([]model.Item, int, error) {
+	// После id не должно идти продолжение id, иначе @ann совпало бы в @anna
+	mention := `@` + regexp.QuoteMeta(userID) + `([^[:alnum:]_.-]|$)`
+
+	rows, err := r.db.Query(ctx, `
+		WITH items AS (
+			SELECT c.id, c.thread_id, c.author_id, c.content, c.created_at,
+			       CASE
+			           WHEN parent.author_id = $1 THEN 'reply'
+			           WHEN c.content ~ $4 THEN 'mention'
+			           ELSE 'thread'
+			       END AS kind
+			FROM comments c
+			LEFT JOIN comments parent ON parent.id = c.answer_comment_id
+			WHERE c.created_at > $2 AND c.created_at <= $3
+			  AND c.author_id <> $1 AND c.thread_id IS NOT NULL AND `+visibleFilter+`
+			  AND NOT EXISTS (
+			      SELECT 1 FROM thread_subscriptions m
+			      WHERE m.thread_id = c.thread_id AND m.user_id = $1 AND m.state = 'muted')
+			  AND (parent.author_id = $1
+			       OR c.content ~ $4
+			       OR c.thread_id IN (
+			           SELECT own.thread_id FROM comments own
+			           WHERE own.author_id = $1 AND own.deleted_at IS NULL AND own.status IN ('created', 'edited')))
+		)
+		SELECT id, thread_id, author_id, content, created_at, kind, COUNT(*) OVER ()
+		FROM items
+		ORDER BY created_at, id
+		LIMIT $5
+	`, userID, since, until, mention, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := []model.Item{}
+	total := 0
+	for rows.Next() {
+		var it model.Item
+		if err := rows.Scan(&it.CommentID, &it.ThreadID, &it.AuthorID, &it.Content, &it.CreatedAt, &it.Kind, &total); err != nil {
+			return nil, 0, err
+		}
+		items = append(items, it)
+	}
+	return items, total, rows.Err()
+}
+
+// Claim сдвигает конец окна пользователя с prev на until, если его не сдвинул
+// другой обработчик. Только успешно занявший окно отправляет сводку
+func (r *DigestRepo) Claim(ctx context.Context, userID string, prev *time.Time, until time.Time) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		INSERT INTO digest_state (user_id, last_sent_at, updated_at)
+		VALUES ($1, $3, NOW())
+		ON CONFLICT (user_id) DO UPDATE SET last_sent_at = EXCLUDED.last_sent_at, updated_at = NOW()
+		WHERE digest_state.last_sent_at IS NOT DISTINCT FROM $2
+	`, userID, prev, until)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// Release возвращает окно пользователя к prev, если сводку не удалось доставить
+func (r *DigestRepo) Release(ctx context.Context, userID string, claimed time.Time, prev *time.Time) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE digest_state SET last_sent_at = $3, updated_at = NOW()
+		WHERE user_id = $1 AND last_sent_at = $2
+	`, userID, claimed, prev)
+	return err
+}
+
+// MarkSent учитывает доставленную сводку
+func (r *DigestRepo) MarkSent(ctx context.Context, userID string) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE digest_state SET digests_sent = digests_sent + 1, updated_at = NOW()
+		WHERE user_id = $1
+	`, userID)
+	return err
+}
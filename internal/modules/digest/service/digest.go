@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/pksep/comments/internal/modules/digest/model"
+	"github.com/pksep/comments/internal/modules/digest/notifier"
+	"github.com/pksep/comments/internal/modules/digest/repository"
+)
+
+const (
+	// maxItems — сколько комментариев попадает в одну сводку, остальные только считаются
+	maxItems = 50
+	// dueBatchSize — сколько пользователей выбирается за один запрос очереди
+	dueBatchSize = 200
+)
+
+// ErrNotifierDisabled — рассылка не настроена (DIGEST_NOTIFIER пуст)
+var ErrNotifierDisabled = errors.New("digest notifier is not configured")
+
+type DigestService struct {
+	repo     repository.DigestRepoInterface
+	notifier notifier.Notifier
+	// interval — период сводки
+	interval time.Duration
+}
+
+// NewDigestService создаёт сервис сводок. notifier может быть nil — тогда
+// сводки доступны только для предпросмотра
+func NewDigestService(repo repository.DigestRepoInterface, notifier notifier.Notifier, interval time.Duration) *DigestService {
+	return &DigestService{repo: repo, notifier: notifier, interval: interval}
+}
+
+// Preview собирает и отрисовывает сводку, которую пользователь получил бы сейчас, не отправляя её
+func (s *DigestService) Preview(ctx context.Context, userID string) (*model.Digest, *model.Message, error) {
+	state, err := s.repo.GetState(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var prev *time.Time
+	if state != nil {
+		prev = state.LastSentAt
+	}
+
+	now := time.Now()
+	d, err := s.build(ctx, userID, s.since(prev, now), now)
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, err := Render(d)
+	if err != nil {
+		return nil, nil, err
+	}
+	return d, &msg, nil
+}
+
+// RunOnce отправляет сводки всем пользователям, у которых наступил срок, и возвращает
+// число отправленных. Окно пользователя занимается до отправки, поэтому несколько реплик
+// не отправят одну сводку дважды; при ошибке доставки окно освобождается для повтора
+func (s *DigestService) RunOnce(ctx context.Context) (int, error) {
+	if s.notifier == nil {
+		return 0, ErrNotifierDisabled
+	}
+
+	// Postgres хранит время с точностью до микросекунд; окно сравнивается точно
+	now := time.Now().Truncate(time.Microsecond)
+	sent := 0
+	after := ""
+	for {
+		due, err := s.repo.Due(ctx, now.Add(-s.interval), after, dueBatchSize)
+		if err != nil {
+			return sent, err
+		}
+		for _, state := range due {
+			ok, err := s.send(ctx, state, now)
+			if err != nil {
+				log.Printf("Ошибка отправки сводки пользователю %s: %v", state.UserID, err)
+				continue
+			}
+			if ok {
+				sent++
+			}
+		}
+		if len(due) < dueBatchSize {
+			return sent, nil
+		}
+		after = due[len(due)-1].UserID
+	}
+}
+
+// RunWorker периодически рассылает сводки, пока не отменён ctx
+func (s *DigestService) RunWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.RunOnce(ctx)
+			if err != nil {
+				log.Printf("Ошибка рассылки сводок: %v", err)
+			} else if n > 0 {
+				log.Printf("Отправлено сводок: %d", n)
+			}
+		}
+	}
+}
+
+// send занимает окно пользователя и отправляет сводку. Пустая сводка не отправляется,
+// но окно всё равно сдвигается. Возвращает true, если сводка доставлена
+func (s *DigestService) send(ctx context.Context, state model.State, now time.Time) (bool, error) {
+	claimed, err := s.repo.Claim(ctx, state.UserID, state.LastSentAt, now)
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	d, err := s.build(ctx, state.UserID, s.since(state.LastSentAt, now), now)
+	if err == nil && d.Empty() {
+		return false, nil
+	}
+	if err == nil {
+		var msg model.Message
+		if msg, err = Render(d); err == nil {
+			err = s.notifier.Notify(ctx, msg)
+		}
+	}
+	if err != nil {
+		if releaseErr := s.repo.Release(ctx, state.UserID, now, state.LastSentAt); releaseErr != nil {
+			log.Printf("Ошибка освобождения окна сводки пользователя %s: %v", state.UserID, releaseErr)
+		}
+		return false, err
+	}
+
+	if err := s.repo.MarkSent(ctx, state.UserID); err != nil {
+		log.Printf("Ошибка учёта сводки пользователя %s: %v", state.UserID, err)
+	}
+	return true, nil
+}
+
+func (s *DigestService) build(ctx context.Context, userID string, since, until time.Time) (*model.Digest, error) {
+	items, total, err := s.repo.Items(ctx, userID, since, until, maxItems)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Digest{UserID: userID, Since: since, Until: until, Items: items, Total: total}, nil
+}
+
+// since — начало окна: конец прошлой сводки, а для первой — один период назад
+func (s *DigestService) since(prev *time.Time, now time.Time) time.Time {
+	if prev != nil {
+		return *prev
+	}
+	return now.Add(-s.interval)
+}
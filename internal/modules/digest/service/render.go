@@ -0,0 +1,85 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pksep/comments/internal/modules/digest/model"
+)
+
+// excerptLength — сколько символов комментария показывается в сводке
+const excerptLength = 200
+
+var digestTemplate = template.Must(template.New("digest").Funcs(template.FuncMap{
+	"excerpt": excerpt,
+	"ts":      func(t time.Time) string { return t.Format("02.01.2006 15:04") },
+}).Parse(`Здравствуйте, {{.UserID}}!
+
+Новое в обсуждениях с {{ts .Since}} по {{ts .Until}}.
+{{range .Sections}}
+{{.Title}} ({{len .Items}}):
+{{range .Items}}
+  {{ts .CreatedAt}} {{.AuthorID}} в треде {{.ThreadID}}:
+  {{excerpt .Content}}
+{{end}}{{end}}{{if .Hidden}}
+…и ещё {{.Hidden}} в полной ленте.
+{{end}}`))
+
+type section struct {
+	Title string
+	Items []model.Item
+}
+
+// Render превращает сводку в письмо. Разделы идут в порядке важности: ответы,
+// упоминания, остальное в тредах пользователя
+func Render(d *model.Digest) (model.Message, error) {
+	titles := []struct {
+		kind  model.ItemKind
+		title string
+	}{
+		{model.ItemReply, "Ответы на ваши комментарии"},
+		{model.ItemMention, "Упоминания"},
+		{model.ItemThread, "Новое в ваших обсуждениях"},
+	}
+
+	var sections []section
+	for _, t := range titles {
+		s := section{Title: t.title}
+		for _, it := range d.Items {
+			if it.Kind == t.kind {
+				s.Items = append(s.Items, it)
+			}
+		}
+		if len(s.Items) > 0 {
+			sections = append(sections, s)
+		}
+	}
+
+	var buf strings.Builder
+	err := digestTemplate.Execute(&buf, struct {
+		*model.Digest
+		Sections []section
+		Hidden   int
+	}{d, sections, d.Total - len(d.Items)})
+	if err != nil {
+		return model.Message{}, err
+	}
+
+	return model.Message{
+		UserID:  d.UserID,
+		Subject: fmt.Sprintf("Сводка обсуждений: новых комментариев — %d", d.Total),
+		Text:    buf.String(),
+	}, nil
+}
+
+// excerpt обрезает текст до excerptLength символов и склеивает строки
+func excerpt(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if utf8.RuneCountInString(s) <= excerptLength {
+		return s
+	}
+	return string([]rune(s)[:excerptLength]) + "…"
+}
@@ -0,0 +1,27 @@
+package dto
+
+// SaveDraftDTO — тело POST /drafts/save
+type SaveDraftDTO struct {
+	AuthorID        string  `json:"author_id" binding:"required"`
+	ThreadID        string  `json:"thread_id" binding:"required,uuid"`
+	AnswerCommentID *string `json:"answer_comment_id,omitempty" binding:"omitempty,uuid"`
+	Content         string  `json:"content" binding:"required,max=20000"`
+}
+
+// DiscardDraftDTO — тело POST /drafts/discard
+type DiscardDraftDTO struct {
+	AuthorID string `json:"author_id" binding:"required"`
+	ThreadID string `json:"thread_id" binding:"required,uuid"`
+}
+
+// PutDraftDTO — тело PUT /v2/threads/:id/draft
+type PutDraftDTO struct {
+	AuthorID        string  `json:"author_id" binding:"required"`
+	AnswerCommentID *string `json:"answer_comment_id,omitempty" binding:"omitempty,uuid"`
+	Content         string  `json:"content" binding:"required,max=20000"`
+}
+
+// AuthorQuery — параметр author_id чтения и удаления черновика
+type AuthorQuery struct {
+	AuthorID string `form:"author_id" binding:"required"`
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pksep/comments/internal/modules/drafts/api/dto"
+	"github.com/pksep/comments/internal/modules/drafts/model"
+	"github.com/pksep/comments/internal/modules/drafts/service"
+)
+
+type DraftHandler struct {
+	service *service.DraftService
+}
+
+func NewDraftHandler(service *service.DraftService) *DraftHandler {
+	return &DraftHandler{service: service}
+}
+
+func (h *DraftHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	drafts := rg.Group("/drafts")
+	{
+		drafts.POST("/save", h.Save)
+		drafts.GET("/:threadId", h.Get) // ?author_id=
+		drafts.POST("/discard", h.Discard)
+	}
+}
+
+// RegisterRoutesV2 регистрирует черновик как ресурс треда
+func (h *DraftHandler) RegisterRoutesV2(rg *gin.RouterGroup) {
+	threads := rg.Group("/threads")
+	{
+		threads.GET("/:id/draft", h.GetV2) // ?author_id=
+		threads.PUT("/:id/draft", h.PutV2)
+		threads.DELETE("/:id/draft", h.DeleteV2) // ?author_id=
+	}
+}
+
+func (h *DraftHandler) Save(c *gin.Context) {
+	var body dto.SaveDraftDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	draft, err := h.service.Save(c, model.Draft{
+		AuthorID:        body.AuthorID,
+		ThreadID:        body.ThreadID,
+		AnswerCommentID: body.AnswerCommentID,
+		Content:         body.Content,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, draft)
+}
+
+func (h *DraftHandler) Get(c *gin.Context) {
+	h.get(c, "threadId")
+}
+
+func (h *DraftHandler) Discard(c *gin.Context) {
+	var body dto.DiscardDraftDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.Discard(c, body.AuthorID, body.ThreadID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *DraftHandler) GetV2(c *gin.Context) {
+	h.get(c, "id")
+}
+
+func (h *DraftHandler) PutV2(c *gin.Context) {
+	var body dto.PutDraftDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	threadID, ok := parseThreadID(c, "id")
+	if !ok {
+		return
+	}
+
+	draft, err := h.service.Save(c, model.Draft{
+		AuthorID:        body.AuthorID,
+		ThreadID:        threadID,
+		AnswerCommentID: body.AnswerCommentID,
+		Content:         body.Content,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, draft)
+}
+
+func (h *DraftHandler) DeleteV2(c *gin.Context) {
+	var query dto.AuthorQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	threadID, ok := parseThreadID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.Discard(c, query.AuthorID, threadID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *DraftHandler) get(c *gin.Context, param string) {
+	var query dto.AuthorQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	threadID, ok := parseThreadID(c, param)
+	if !ok {
+		return
+	}
+
+	draft, err := h.service.Get(c, query.AuthorID, threadID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if draft == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "draft not found"})
+		return
+	}
+	c.JSON(http.StatusOK, draft)
+}
+
+// parseThreadID читает id треда из параметра пути param; thread_id в черновиках — UUID
+func parseThreadID(c *gin.Context, param string) (string, bool) {
+	threadID := c.Param(param)
+	if _, err := uuid.Parse(threadID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid thread id"})
+		return "", false
+	}
+	return threadID, true
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// Draft — черновик комментария пользователя в треде. У пользователя не больше
+// одного черновика на тред; AnswerCommentID — комментарий, на который он отвечает
+type Draft struct {
+	AuthorID        string    `json:"author_id" db:"author_id"`
+	ThreadID        string    `json:"thread_id" db:"thread_id"`
+	AnswerCommentID *string   `json:"answer_comment_id,omitempty" db:"answer_comment_id"`
+	Content         string    `json:"content" db:"content"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+	ExpiresAt       time.Time `json:"expires_at" db:"expires_at"`
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pksep/comments/internal/modules/drafts/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+type DraftRepoInterface interface {
+	Save(ctx context.Context, draft *model.Draft) (*model.Draft, error)
+	Get(ctx context.Context, authorID, threadID string) (*model.Draft, error)
+	Discard(ctx context.Context, authorID, threadID string) error
+	DeleteByAuthor(ctx context.Context, authorID string) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type DraftRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewDraftRepo(db *pgxpool.Pool) *DraftRepo {
+	return &DraftRepo{db: db}
+}
+
+const draftColumns = `author_id, thread_id, answer_comment_id, content, created_at, updated_at, expires_at`
+
+func scanDraft(row interface{ Scan(...any) error }) (*model.Draft, error) {
+	var d model.Draft
+	err := row.Scan(&d.AuthorID, &d.ThreadID, &d.AnswerCommentID, &d.Content, &d.CreatedAt, &d.UpdatedAt, &d.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// Save создаёт или перезаписывает черновик пользователя в треде.
+// Время создания сохраняется от первой записи, срок жизни продлевается
+func (r *DraftRepo) Save(ctx context.Context, draft *model.Draft) (*model.Draft, error) {
+	return scanDraft(r.db.QueryRow(ctx, `
+		INSERT INTO comment_drafts (`+draftColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $5, $6)
+		ON CONFLICT (author_id, thread_id) DO UPDATE SET
+			answer_comment_id = EXCLUDED.answer_comment_id,
+			content = EXCLUDED.content,
+			updated_at = EXCLUDED.updated_at,
+			expires_at = EXCLUDED.expires_at
+		RETURNING `+draftColumns,
+		draft.AuthorID, draft.ThreadID, draft.AnswerCommentID, draft.Content, draft.UpdatedAt, draft.ExpiresAt))
+}
+
+// Get возвращает действующий черновик или nil, если его нет или он истёк
+func (r *DraftRepo) Get(ctx context.Context, authorID, threadID string) (*model.Draft, error) {
+	d, err := scanDraft(r.db.QueryRow(ctx, `
+		SELECT `+draftColumns+`
+		FROM comment_drafts
+		WHERE author_id = $1 AND thread_id = $2 AND expires_at > NOW()
+	`, authorID, threadID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return d, nil
+}
+
+// Discard удаляет черновик; отсутствие черновика ошибкой не считается
+func (r *DraftRepo) Discard(ctx context.Context, authorID, threadID string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM comment_drafts WHERE author_id = $1 AND thread_id = $2`, authorID, threadID)
+	return err
+}
+
+// DeleteByAuthor удаляет все черновики автора, кроме черновиков в тредах других тенантов.
+// Черновики к ещё не созданным тредам удаляются всегда
+func (r *DraftRepo) DeleteByAuthor(ctx context.Context, authorID string) error {
+	_, err := r.db.Exec(ctx, `
+		DELETE FROM comment_drafts
+		WHERE author_id = $1
+		  AND ($2::text = '' OR NOT EXISTS (
+		      SELECT 1 FROM threads t WHERE t.id = thread_id AND t.tenant_id <> $2))
+	`, authorID, tenant.ID(ctx))
+	return err
+}
+
+// DeleteExpired удаляет просроченные черновики
+func (r *DraftRepo) DeleteExpired(ctx context.Context) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM comment_drafts WHERE expires_at < $1`, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
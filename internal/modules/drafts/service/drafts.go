@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/pksep/comments/internal/modules/drafts/model"
+	"github.com/pksep/comments/internal/modules/drafts/repository"
+)
+
+type DraftService struct {
+	repo repository.DraftRepoInterface
+	ttl  time.Duration
+}
+
+// NewDraftService создаёт сервис черновиков; ttl — срок жизни черновика с последнего сохранения
+func NewDraftService(repo repository.DraftRepoInterface, ttl time.Duration) *DraftService {
+	return &DraftService{repo: repo, ttl: ttl}
+}
+
+// Save сохраняет черновик (автосохранение) и продлевает его срок жизни
+func (s *DraftService) Save(ctx context.Context, draft model.Draft) (*model.Draft, error) {
+	draft.UpdatedAt = time.Now()
+	draft.ExpiresAt = draft.UpdatedAt.Add(s.ttl)
+	return s.repo.Save(ctx, &draft)
+}
+
+// Get возвращает черновик пользователя в треде или nil, если его нет
+func (s *DraftService) Get(ctx context.Context, authorID, threadID string) (*model.Draft, error) {
+	return s.repo.Get(ctx, authorID, threadID)
+}
+
+// Discard удаляет черновик пользователя в треде
+func (s *DraftService) Discard(ctx context.Context, authorID, threadID string) error {
+	return s.repo.Discard(ctx, authorID, threadID)
+}
+
+// RunSweeper периодически удаляет просроченные черновики, пока не отменён ctx
+func (s *DraftService) RunSweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.repo.DeleteExpired(ctx)
+			if err != nil {
+				log.Printf("Ошибка очистки черновиков: %v", err)
+			} else if n > 0 {
+				log.Printf("Удалено просроченных черновиков: %d", n)
+			}
+		}
+	}
+}
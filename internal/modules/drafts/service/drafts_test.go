@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pksep/comments/internal/modules/drafts/model"
+	"github.com/pksep/comments/internal/modules/drafts/repository"
+)
+
+// memDrafts хранит не больше одного черновика на автора и тред, как уникальный ключ таблицы
+type memDrafts struct {
+	repository.DraftRepoInterface
+	drafts map[[2]string]model.Draft
+}
+
+func (m *memDrafts) Save(ctx context.Context, d *model.Draft) (*model.Draft, error) {
+	key := [2]string{d.AuthorID, d.ThreadID}
+	if prev, ok := m.drafts[key]; ok {
+		d.CreatedAt = prev.CreatedAt
+	} else {
+		d.CreatedAt = d.UpdatedAt
+	}
+	m.drafts[key] = *d
+	return d, nil
+}
+
+func (m *memDrafts) Get(ctx context.Context, authorID, threadID string) (*model.Draft, error) {
+	d, ok := m.drafts[[2]string{authorID, threadID}]
+	if !ok {
+		return nil, nil
+	}
+	return &d, nil
+}
+
+func (m *memDrafts) Discard(ctx context.Context, authorID, threadID string) error {
+	delete(m.drafts, [2]string{authorID, threadID})
+	return nil
+}
+
+func TestSaveExtendsExpiry(t *testing.T) {
+	repo := &memDrafts{drafts: map[[2]string]model.Draft{}}
+	s := NewDraftService(repo, time.Hour)
+	ctx := context.Background()
+
+	first, err := s.Save(ctx, model.Draft{AuthorID: "a", ThreadID: "t1", Content: "he"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := first.ExpiresAt.Sub(first.UpdatedAt); got != time.Hour {
+		t.Fatalf("expires after %v, want the ttl", got)
+	}
+
+	second, _ := s.Save(ctx, model.Draft{AuthorID: "a", ThreadID: "t1", Content: "hello"})
+	if second.ExpiresAt.Before(first.ExpiresAt) {
+		t.Fatal("autosave shortened the draft's life")
+	}
+	if len(repo.drafts) != 1 {
+		t.Fatalf("drafts = %d, want one per author and thread", len(repo.drafts))
+	}
+	if d, _ := s.Get(ctx, "a", "t1"); d == nil || d.Content != "hello" {
+		t.Fatalf("draft = %+v, want the latest autosave", d)
+	}
+
+	if err := s.Discard(ctx, "a", "t1"); err != nil {
+		t.Fatal(err)
+	}
+	if d, _ := s.Get(ctx, "a", "t1"); d != nil {
+		t.Fatalf("discarded draft = %+v", d)
+	}
+}
@@ -0,0 +1,15 @@
+package dto
+
+type StartErasureDTO struct {
+	AuthorID    string `json:"author_id" binding:"required"`
+	RequestedBy string `json:"requested_by" binding:"required"`
+}
+
+type JobsQuery struct {
+	Limit  int `form:"limit,default=50" binding:"min=1,max=500"`
+	Offset int `form:"offset" binding:"min=0"`
+}
+
+type ExportQuery struct {
+	Format string `form:"format,default=json" binding:"oneof=json csv"`
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pksep/comments/internal/modules/gdpr/api/dto"
+	"github.com/pksep/comments/internal/modules/gdpr/model"
+	"github.com/pksep/comments/internal/modules/gdpr/repository"
+	"github.com/pksep/comments/internal/modules/gdpr/service"
+)
+
+type GDPRHandler struct {
+	service *service.GDPRService
+}
+
+func NewGDPRHandler(service *service.GDPRService) *GDPRHandler {
+	return &GDPRHandler{service: service}
+}
+
+func (h *GDPRHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	gdpr := rg.Group("/gdpr")
+	{
+		gdpr.GET("/export/:authorId", h.Export) // zip-архив, format=json|csv
+		gdpr.POST("/erasures", h.StartErasure)
+		gdpr.GET("/erasures", h.ListJobs)
+		gdpr.GET("/erasures/:id", h.GetJob)
+	}
+}
+
+func (h *GDPRHandler) Export(c *gin.Context) {
+	var query dto.ExportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	authorID := c.Param("authorId")
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="author-%s-%s.zip"`, authorID, query.Format))
+	c.Status(http.StatusOK)
+
+	if err := h.service.Export(c, authorID, model.ExportFormat(query.Format), c.Writer); err != nil {
+		// Заголовки уже отправлены, поэтому прерываем поток
+		_ = c.Error(err)
+		c.Abort()
+	}
+}
+
+func (h *GDPRHandler) StartErasure(c *gin.Context) {
+	var dto dto.StartErasureDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	job, err := h.service.StartErasure(c, dto.AuthorID, dto.RequestedBy)
+	if err != nil {
+		if errors.Is(err, repository.ErrJobActive) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, job)
+}
+
+func (h *GDPRHandler) ListJobs(c *gin.Context) {
+	var query dto.JobsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	jobs, err := h.service.ListJobs(c, query.Limit, query.Offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, jobs)
+}
+
+func (h *GDPRHandler) GetJob(c *gin.Context) {
+	job, err := h.service.GetJob(c, c.Param("id"))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
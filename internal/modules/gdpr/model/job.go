@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+type JobStatus string
+
+const (
+	JobStatusQueued    JobStatus = "queued"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusCompleted JobStatus = "completed"
+	JobStatusFailed    JobStatus = "failed"
+)
+
+// ErasureJob — фоновое задание на обезличивание данных автора в тенанте.
+// Processed/Total показывают прогресс по комментариям
+type ErasureJob struct {
+	ID          string     `json:"id" db:"id"`
+	AuthorID    string     `json:"author_id" db:"author_id"`
+	TenantID    string     `json:"tenant_id" db:"tenant_id"`
+	Pseudonym   string     `json:"pseudonym" db:"pseudonym"`
+	RequestedBy string     `json:"requested_by" db:"requested_by"`
+	Status      JobStatus  `json:"status" db:"status"`
+	Total       int        `json:"total" db:"total"`
+	Processed   int        `json:"processed" db:"processed"`
+	Attempts    int        `json:"attempts" db:"attempts"`
+	Error       *string    `json:"error,omitempty" db:"error"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty" db:"finished_at"`
+}
+
+// ExportFormat — формат файлов в архиве выгрузки
+type ExportFormat string
+
+const (
+	ExportJSON ExportFormat = "json"
+	ExportCSV  ExportFormat = "csv"
+)
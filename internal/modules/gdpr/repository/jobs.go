@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pksep/comments/internal/modules/gdpr/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+var (
+	// ErrNotFound — задание не найдено
+	ErrNotFound = errors.New("job not found")
+	// ErrJobActive — для автора уже есть незавершённое задание
+	ErrJobActive = errors.New("erasure job for this author is already in progress")
+)
+
+type JobRepoInterface interface {
+	Create(ctx context.Context, job *model.ErasureJob) (*model.ErasureJob, error)
+	Get(ctx context.Context, id string) (*model.ErasureJob, error)
+	List(ctx context.Context, limit, offset int) ([]model.ErasureJob, error)
+	Claim(ctx context.Context, lease time.Duration, maxAttempts int) (*model.ErasureJob, error)
+	Progress(ctx context.Context, id string, processed int, lease time.Duration) error
+	Complete(ctx context.Context, id string) error
+	Fail(ctx context.Context, id string, cause error, retryAfter time.Duration, final bool) error
+}
+
+type JobRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewJobRepo(db *pgxpool.Pool) *JobRepo {
+	return &JobRepo{db: db}
+}
+
+const jobColumns = `id, author_id, tenant_id, pseudonym, requested_by, status, total, processed, attempts, error, created_at, updated_at, finished_at`
+
+func scanJob(row interface{ Scan(...any) error }, j *model.ErasureJob) error {
+	return row.Scan(&j.ID, &j.AuthorID, &j.TenantID, &j.Pseudonym, &j.RequestedBy, &j.Status, &j.Total, &j.Processed,
+		&j.Attempts, &j.Error, &j.CreatedAt, &j.UpdatedAt, &j.FinishedAt)
+}
+
+// Create ставит задание в очередь в тенанте запроса
+func (r *JobRepo) Create(ctx context.Context, job *model.ErasureJob) (*model.ErasureJob, error) {
+	job.ID = uuid.New().String()
+	job.TenantID = tenant.IDOrDefault(ctx)
+	job.Status = model.JobStatusQueued
+	now := time.Now()
+	job.CreatedAt = now
+	job.UpdatedAt = now
+
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO gdpr_jobs (id, author_id, tenant_id, pseudonym, requested_by, status, total, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, job.ID, job.AuthorID, job.TenantID, job.Pseudonym, job.RequestedBy, job.Status, job.Total, job.CreatedAt, job.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrJobActive
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+func (r *JobRepo) Get(ctx context.Context, id string) (*model.ErasureJob, error) {
+	var j model.ErasureJob
+	err := scanJob(r.db.QueryRow(ctx, `
+		SELECT `+jobColumns+` FROM gdpr_jobs
+		WHERE id = $1 AND ($2::text = '' OR tenant_id = $2)
+	`, id, tenant.ID(ctx)), &j)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &j, nil
+}
+
+func (r *JobRepo) List(ctx context.Context, limit, offset int) ([]model.ErasureJob, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+jobColumns+` FROM gdpr_jobs
+		WHERE $3::text = '' OR tenant_id = $3
+		ORDER BY created_at DESC, id DESC
+		LIMIT $1 OFFSET $2
+	`, limit, offset, tenant.ID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []model.ErasureJob{}
+	for rows.Next() {
+		var j model.ErasureJob
+		if err := scanJob(rows, &j); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, rows.Err()
+}
+
+// Claim берёт в работу одно незавершённое задание, на которое нет действующей аренды.
+// Аренда (locked_until) защищает от одновременной обработки несколькими репликами
+// и позволяет продолжить задание, если обработчик упал
+func (r *JobRepo) Claim(ctx context.Context, lease time.Duration, maxAttempts int) (*model.ErasureJob, error) {
+	var j model.ErasureJob
+	err := scanJob(r.db.QueryRow(ctx, `
+		UPDATE gdpr_jobs
+		SET status = 'running', attempts = attempts + 1,
+		    locked_until = NOW() + $1::interval, updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM gdpr_jobs
+			WHERE status IN ('queued', 'running')
+			  AND attempts < $2
+			  AND (locked_until IS NULL OR locked_until < NOW())
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+jobColumns, lease, maxAttempts), &j)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &j, nil
+}
+
+// Progress добавляет обработанные комментарии и продлевает аренду
+func (r *JobRepo) Progress(ctx context.Context, id string, processed int, lease time.Duration) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE gdpr_jobs
+		SET processed = processed + $2, locked_until = NOW() + $3::interval, updated_at = NOW()
+		WHERE id = $1
+	`, id, processed, lease)
+	return err
+}
+
+func (r *JobRepo) Complete(ctx context.Context, id string) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE gdpr_jobs
+		SET status = 'completed', error = NULL, locked_until = NULL, updated_at = NOW(), finished_at = NOW()
+		WHERE id = $1
+	`, id)
+	return err
+}
+
+// Fail сохраняет ошибку. Если final — задание помечается проваленным,
+// иначе будет повторено не раньше чем через retryAfter
+func (r *JobRepo) Fail(ctx context.Context, id string, cause error, retryAfter time.Duration, final bool) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE gdpr_jobs
+		SET status = CASE WHEN $4 THEN 'failed' ELSE status END,
+		    error = $2,
+		    locked_until = CASE WHEN $4 THEN NULL ELSE NOW() + $3::interval END,
+		    finished_at = CASE WHEN $4 THEN NOW() ELSE NULL END,
+		    updated_at = NOW()
+		WHERE id = $1
+	`, id, cause.Error(), retryAfter, final)
+	return err
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	auditModel "github.com/pksep/comments/internal/modules/audit/model"
+	commentModel "github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/gdpr/model"
+)
+
+// Export записывает в w zip-архив со всеми комментариями автора (comments.<format>)
+// и историей их изменений из журнала аудита (history.<format>)
+func (s *GDPRService) Export(ctx context.Context, authorID string, format model.ExportFormat, w io.Writer) error {
+	if format != model.ExportJSON && format != model.ExportCSV {
+		return ErrInvalidFormat
+	}
+
+	zw := zip.NewWriter(w)
+
+	f, err := zw.Create("comments." + string(format))
+	if err != nil {
+		return err
+	}
+	if err := s.exportComments(ctx, authorID, format, f); err != nil {
+		return err
+	}
+
+	f, err = zw.Create("history." + string(format))
+	if err != nil {
+		return err
+	}
+	if err := s.exportHistory(ctx, authorID, format, f); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+func (s *GDPRService) exportComments(ctx context.Context, authorID string, format model.ExportFormat, w io.Writer) error {
+	if format == model.ExportJSON {
+		arr := newJSONArray(w)
+		if err := s.comments.StreamByAuthor(ctx, authorID, func(c commentModel.Comment) error {
+			return arr.write(c)
+		}); err != nil {
+			return err
+		}
+		return arr.close()
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"id", "thread_id", "answer_comment_id", "author_id", "status", "content", "version", "created_at", "updated_at"}); err != nil {
+		return err
+	}
+	if err := s.comments.StreamByAuthor(ctx, authorID, func(c commentModel.Comment) error {
+		return cw.Write([]string{
+			c.ID, deref(c.ThreadID), deref(c.AnswerCommentID), c.AuthorID, string(c.Status), c.Content,
+			strconv.Itoa(c.Version), c.CreatedAt.Format(time.RFC3339Nano), c.UpdatedAt.Format(time.RFC3339Nano),
+		})
+	}); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (s *GDPRService) exportHistory(ctx context.Context, authorID string, format model.ExportFormat, w io.Writer) error {
+	filter := auditModel.Filter{SubjectAuthorID: authorID}
+
+	if format == model.ExportJSON {
+		arr := newJSONArray(w)
+		if err := s.audit.Stream(ctx, filter, func(e auditModel.Entry) error {
+			return arr.write(e)
+		}); err != nil {
+			return err
+		}
+		return arr.close()
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"id", "comment_id", "thread_id", "actor_id", "action", "before", "after", "created_at"}); err != nil {
+		return err
+	}
+	if err := s.audit.Stream(ctx, filter, func(e auditModel.Entry) error {
+		return cw.Write([]string{
+			e.ID, e.CommentID, deref(e.ThreadID), e.ActorID, string(e.Action),
+			string(e.Before), string(e.After), e.CreatedAt.Format(time.RFC3339Nano),
+		})
+	}); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// jsonArray пишет JSON-массив поэлементно, не накапливая его в памяти
+type jsonArray struct {
+	w     io.Writer
+	count int
+}
+
+func newJSONArray(w io.Writer) *jsonArray {
+	return &jsonArray{w: w}
+}
+
+func (a *jsonArray) write(v any) error {
+	sep := ","
+	if a.count == 0 {
+		sep = "["
+	}
+	a.count++
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(a.w, sep); err != nil {
+		return err
+	}
+	_, err = a.w.Write(data)
+	return err
+}
+
+func (a *jsonArray) close() error {
+	if a.count == 0 {
+		_, err := io.WriteString(a.w, "[]")
+		return err
+	}
+	_, err := io.WriteString(a.w, "]")
+	return err
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
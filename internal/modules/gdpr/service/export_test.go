@@ -0,0 +1,141 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	auditModel "github.com/pksep/comments/internal/modules/audit/model"
+	commentModel "github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/gdpr/model"
+)
+
+type authorComments struct {
+	fakeComments
+	comments []commentModel.Comment
+}
+
+func (f *authorComments) StreamByAuthor(ctx context.Context, authorID string, fn func(commentModel.Comment) error) error {
+	for _, c := range f.comments {
+		if c.AuthorID != authorID {
+			continue
+		}
+		if err := fn(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type authorHistory struct {
+	fakeAudit
+	filter  auditModel.Filter
+	entries []auditModel.Entry
+}
+
+func (f *authorHistory) Stream(ctx context.Context, filter auditModel.Filter, fn func(auditModel.Entry) error) error {
+	f.filter = filter
+	for _, e := range f.entries {
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unzip возвращает содержимое файлов архива по именам
+func unzip(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name], _ = io.ReadAll(r)
+		r.Close()
+	}
+	return files
+}
+
+func newExportService(comments []commentModel.Comment, entries []auditModel.Entry) (*GDPRService, *authorHistory) {
+	history := &authorHistory{entries: entries}
+	return NewGDPRService(nil, &authorComments{comments: comments}, history, nil, nil, nil), history
+}
+
+func TestExportJSON(t *testing.T) {
+	s, history := newExportService(
+		[]commentModel.Comment{
+			{ID: "c1", AuthorID: "alice", Content: "first"},
+			{ID: "c2", AuthorID: "bob", Content: "not hers"},
+			{ID: "c3", AuthorID: "alice", Content: "second"},
+		},
+		[]auditModel.Entry{{ID: "e1", CommentID: "c1", Action: auditModel.ActionCreate}},
+	)
+
+	var buf bytes.Buffer
+	if err := s.Export(context.Background(), "alice", model.ExportJSON, &buf); err != nil {
+		t.Fatal(err)
+	}
+	files := unzip(t, buf.Bytes())
+
+	var comments []commentModel.Comment
+	if err := json.Unmarshal(files["comments.json"], &comments); err != nil {
+		t.Fatalf("comments.json: %v (%s)", err, files["comments.json"])
+	}
+	if len(comments) != 2 || comments[0].ID != "c1" || comments[1].ID != "c3" {
+		t.Fatalf("comments = %+v", comments)
+	}
+
+	var entries []auditModel.Entry
+	if err := json.Unmarshal(files["history.json"], &entries); err != nil || len(entries) != 1 {
+		t.Fatalf("history.json = %s (%v)", files["history.json"], err)
+	}
+	if history.filter.SubjectAuthorID != "alice" {
+		t.Fatalf("history filter = %+v, want the author's comments", history.filter)
+	}
+}
+
+func TestExportCSVAndEmpty(t *testing.T) {
+	s, _ := newExportService([]commentModel.Comment{{ID: "c1", AuthorID: "alice", Content: "a, \"quoted\"\nline"}}, nil)
+
+	var buf bytes.Buffer
+	if err := s.Export(context.Background(), "alice", model.ExportCSV, &buf); err != nil {
+		t.Fatal(err)
+	}
+	files := unzip(t, buf.Bytes())
+	rows, err := csv.NewReader(bytes.NewReader(files["comments.csv"])).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[0][0] != "id" || rows[1][5] != "a, \"quoted\"\nline" {
+		t.Fatalf("comments.csv rows = %q", rows)
+	}
+	if history, _ := csv.NewReader(bytes.NewReader(files["history.csv"])).ReadAll(); len(history) != 1 {
+		t.Fatalf("history.csv rows = %q, want header only", history)
+	}
+
+	buf.Reset()
+	if err := s.Export(context.Background(), "nobody", model.ExportJSON, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(unzip(t, buf.Bytes())["comments.json"]); got != "[]" {
+		t.Fatalf("empty export = %q, want []", got)
+	}
+}
+
+func TestExportRejectsUnknownFormat(t *testing.T) {
+	s, _ := newExportService(nil, nil)
+	if err := s.Export(context.Background(), "alice", "xml", io.Discard); !errors.Is(err, ErrInvalidFormat) {
+		t.Fatalf("err = %v, want ErrInvalidFormat", err)
+	}
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	auditModel "github.com/pksep/comments/internal/modules/audit/model"
+	commentModel "github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/gdpr/model"
+	"github.com/pksep/comments/internal/modules/gdpr/repository"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+const (
+	// erasureBatchSize — сколько комментариев обезличивается в одной транзакции
+	erasureBatchSize = 200
+	// jobLease — на сколько задание закрепляется за обработчиком; продлевается после каждой пачки
+	jobLease = 5 * time.Minute
+	// maxAttempts — после стольких неудачных попыток задание помечается проваленным
+	maxAttempts = 5
+	retryAfter  = time.Minute
+)
+
+// ErrInvalidFormat — неизвестный формат выгрузки
+var ErrInvalidFormat = errors.New("format must be json or csv")
+
+// CommentStore — операции над комментариями, нужные для выгрузки и удаления данных
+type CommentStore interface {
+	StreamByAuthor(ctx context.Context, authorID string, fn func(commentModel.Comment) error) error
+	CountByAuthor(ctx context.Context, authorID string) (int, error)
+	AnonymizeAuthorBatch(ctx context.Context, authorID, pseudonym, actorID string, limit int) (int, error)
+	AnonymizeReferences(ctx context.Context, authorID, pseudonym string) error
+}
+
+// AuditStore — операции над журналом аудита, нужные для выгрузки и удаления данных
+type AuditStore interface {
+	Stream(ctx context.Context, filter auditModel.Filter, fn func(auditModel.Entry) error) error
+	Redact(ctx context.Context, authorID, pseudonym, marker string) (int64, error)
+}
+
+// DraftStore — черновики автора, удаляются вместе с остальными данными
+type DraftStore interface {
+	DeleteByAuthor(ctx context.Context, authorID string) error
+}
+
+// SubscriptionStore — подписки автора на треды, удаляются вместе с остальными данными
+type SubscriptionStore interface {
+	DeleteByUser(ctx context.Context, userID string) error
+}
+
+// IdempotencyStore — сохранённые ответы на повторяемые запросы, в них остаются данные автора
+type IdempotencyStore interface {
+	DeleteByAuthor(ctx context.Context, authorID string) (int64, error)
+}
+
+type GDPRService struct {
+	jobs     repository.JobRepoInterface
+	comments CommentStore
+	audit    AuditStore
+	drafts   DraftStore
+	subs     SubscriptionStore
+	replays  IdempotencyStore
+}
+
+func NewGDPRService(jobs repository.JobRepoInterface, comments CommentStore, audit AuditStore, drafts DraftStore, subs SubscriptionStore, replays IdempotencyStore) *GDPRService {
+	return &GDPRService{jobs: jobs, comments: comments, audit: audit, drafts: drafts, subs: subs, replays: replays}
+}
+
+// StartErasure ставит в очередь задание на обезличивание всех данных автора в тенанте запроса
+func (s *GDPRService) StartErasure(ctx context.Context, authorID, requestedBy string) (*model.ErasureJob, error) {
+	total, err := s.comments.CountByAuthor(ctx, authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.jobs.Create(ctx, &model.ErasureJob{
+		AuthorID:    authorID,
+		Pseudonym:   "erased-" + uuid.New().String(),
+		RequestedBy: requestedBy,
+		Total:       total,
+	})
+}
+
+func (s *GDPRService) GetJob(ctx context.Context, id string) (*model.ErasureJob, error) {
+	return s.jobs.Get(ctx, id)
+}
+
+func (s *GDPRService) ListJobs(ctx context.Context, limit, offset int) ([]model.ErasureJob, error) {
+	return s.jobs.List(ctx, limit, offset)
+}
+
+// RunWorker периодически забирает и выполняет задания на удаление данных, пока не отменён ctx.
+// Задание, прерванное перезапуском, подхватывается снова после истечения аренды
+// и продолжается с необработанных комментариев
+func (s *GDPRService) RunWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				job, err := s.jobs.Claim(ctx, jobLease, maxAttempts)
+				if err != nil {
+					log.Printf("Ошибка получения задания на удаление данных: %v", err)
+					break
+				}
+				if job == nil {
+					break
+				}
+				s.process(ctx, job)
+			}
+		}
+	}
+}
+
+func (s *GDPRService) process(ctx context.Context, job *model.ErasureJob) {
+	// Данные удаляются только в тенанте, где задание поставлено
+	if err := s.erase(tenant.WithTenant(ctx, tenant.Tenant{ID: job.TenantID}), job); err != nil {
+		log.Printf("Ошибка выполнения задания на удаление данных %s: %v", job.ID, err)
+		if ferr := s.jobs.Fail(ctx, job.ID, err, retryAfter, job.Attempts >= maxAttempts); ferr != nil {
+			log.Printf("Ошибка сохранения статуса задания %s: %v", job.ID, ferr)
+		}
+		return
+	}
+	if err := s.jobs.Complete(ctx, job.ID); err != nil {
+		log.Printf("Ошибка завершения задания %s: %v", job.ID, err)
+	}
+}
+
+func (s *GDPRService) erase(ctx context.Context, job *model.ErasureJob) error {
+	actor := "gdpr:" + job.RequestedBy
+	for {
+		n, err := s.comments.AnonymizeAuthorBatch(ctx, job.AuthorID, job.Pseudonym, actor, erasureBatchSize)
+		if err != nil {
+			return fmt.Errorf("anonymize comments: %w", err)
+		}
+		if n == 0 {
+			break
+		}
+		if err := s.jobs.Progress(ctx, job.ID, n, jobLease); err != nil {
+			return fmt.Errorf("save progress: %w", err)
+		}
+	}
+
+	if err := s.comments.AnonymizeReferences(ctx, job.AuthorID, job.Pseudonym); err != nil {
+		return fmt.Errorf("anonymize references: %w", err)
+	}
+	if err := s.drafts.DeleteByAuthor(ctx, job.AuthorID); err != nil {
+		return fmt.Errorf("delete drafts: %w", err)
+	}
+	if err := s.subs.DeleteByUser(ctx, job.AuthorID); err != nil {
+		return fmt.Errorf("delete subscriptions: %w", err)
+	}
+	if _, err := s.replays.DeleteByAuthor(ctx, job.AuthorID); err != nil {
+		return fmt.Errorf("purge idempotency responses: %w", err)
+	}
+	if _, err := s.audit.Redact(ctx, job.AuthorID, job.Pseudonym, commentModel.RedactedContent); err != nil {
+		return fmt.Errorf("redact audit log: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	auditModel "github.com/pksep/comments/internal/modules/audit/model"
+	commentModel "github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/gdpr/model"
+	"github.com/pksep/comments/internal/modules/gdpr/repository"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+// tenantLog запоминает тенант контекста каждого вызова хранилищ
+type tenantLog map[string][]string
+
+func (l tenantLog) see(ctx context.Context, op string) {
+	l[op] = append(l[op], tenant.ID(ctx))
+}
+
+type fakeComments struct {
+	log     tenantLog
+	pending int
+}
+
+func (f *fakeComments) StreamByAuthor(ctx context.Context, authorID string, fn func(commentModel.Comment) error) error {
+	return nil
+}
+
+func (f *fakeComments) CountByAuthor(ctx context.Context, authorID string) (int, error) {
+	return f.pending, nil
+}
+
+func (f *fakeComments) AnonymizeAuthorBatch(ctx context.Context, authorID, pseudonym, actorID string, limit int) (int, error) {
+	f.log.see(ctx, "anonymize")
+	n := min(f.pending, limit)
+	f.pending -= n
+	return n, nil
+}
+
+func (f *fakeComments) AnonymizeReferences(ctx context.Context, authorID, pseudonym string) error {
+	f.log.see(ctx, "references")
+	return nil
+}
+
+type fakeAudit struct{ log tenantLog }
+
+func (f *fakeAudit) Stream(ctx context.Context, filter auditModel.Filter, fn func(auditModel.Entry) error) error {
+	return nil
+}
+
+func (f *fakeAudit) Redact(ctx context.Context, authorID, pseudonym, marker string) (int64, error) {
+	f.log.see(ctx, "redact")
+	return 0, nil
+}
+
+type fakeDrafts struct{ log tenantLog }
+
+func (f *fakeDrafts) DeleteByAuthor(ctx context.Context, authorID string) error {
+	f.log.see(ctx, "drafts")
+	return nil
+}
+
+type fakeSubs struct{ log tenantLog }
+
+func (f *fakeSubs) DeleteByUser(ctx context.Context, userID string) error {
+	f.log.see(ctx, "subscriptions")
+	return nil
+}
+
+type fakeReplays struct{ log tenantLog }
+
+func (f *fakeReplays) DeleteByAuthor(ctx context.Context, authorID string) (int64, error) {
+	f.log.see(ctx, "idempotency")
+	return 0, nil
+}
+
+type fakeJobs struct {
+	repository.JobRepoInterface
+	processed int
+	completed bool
+}
+
+func (f *fakeJobs) Progress(ctx context.Context, id string, processed int, lease time.Duration) error {
+	f.processed += processed
+	return nil
+}
+
+func (f *fakeJobs) Complete(ctx context.Context, id string) error {
+	f.completed = true
+	return nil
+}
+
+func TestProcessErasesWithinJobTenant(t *testing.T) {
+	log := tenantLog{}
+	comments := &fakeComments{log: log, pending: 2*erasureBatchSize + 1}
+	jobs := &fakeJobs{}
+	s := NewGDPRService(jobs, comments, &fakeAudit{log: log}, &fakeDrafts{log: log}, &fakeSubs{log: log}, &fakeReplays{log: log})
+
+	s.process(context.Background(), &model.ErasureJob{ID: "job", AuthorID: "alice", TenantID: "acme"})
+
+	if !jobs.completed {
+		t.Fatal("job is not completed")
+	}
+	if jobs.processed != 2*erasureBatchSize+1 {
+		t.Fatalf("processed = %d, want %d", jobs.processed, 2*erasureBatchSize+1)
+	}
+	for _, op := range []string{"anonymize", "references", "drafts", "subscriptions", "idempotency", "redact"} {
+		if len(log[op]) == 0 {
+			t.Fatalf("%s was not called", op)
+		}
+		for _, id := range log[op] {
+			if id != "acme" {
+				t.Fatalf("%s ran in tenant %q, want acme", op, id)
+			}
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pksep/comments/internal/modules/idempotency/service"
+	"github.com/pksep/comments/internal/modules/shared/auth"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+const (
+	// Header — заголовок с ключом идемпотентности
+	Header = "Idempotency-Key"
+	// ReplayedHeader выставляется на ответах, отданных из сохранённого результата
+	ReplayedHeader = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+)
+
+// replaySkipHeaders — заголовки, которые относятся к конкретному ответу и не повторяются
+var replaySkipHeaders = []string{"Content-Length", "Date", "X-Request-Id"}
+
+// Middleware делает POST-запрос идемпотентным по заголовку Idempotency-Key:
+// повтор с тем же адресом и телом получает сохранённый ответ с его заголовками,
+// с другим — 422. Ключи клиентов и тенантов не пересекаются
+func Middleware(s *service.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(Header)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key is too long"})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		scope := requestScope(c)
+		sum := sha256.Sum256(append([]byte(c.Request.URL.RequestURI()+"\n"), body...))
+		hash := hex.EncodeToString(sum[:])
+
+		stored, err := s.Begin(c, scope, key, hash)
+		switch {
+		case errors.Is(err, service.ErrMismatch):
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, service.ErrInProgress):
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		case stored != nil:
+			for name, values := range stored.ResponseHeaders {
+				c.Writer.Header()[name] = values
+			}
+			c.Header(ReplayedHeader, "true")
+			contentType := stored.ResponseHeaders.Get("Content-Type")
+			if contentType == "" {
+				contentType = "application/json; charset=utf-8"
+			}
+			c.Data(*stored.StatusCode, contentType, stored.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		// Ответ уже отправлен клиенту; сохраняем его даже если запрос отменён
+		ctx := context.WithoutCancel(c.Request.Context())
+
+		// Ключ освобождается, если ответ не сохранён: ошибка сервера, паника в хэндлере
+		completed := false
+		defer func() {
+			if !completed {
+				_ = s.Release(ctx, scope, key)
+			}
+		}()
+
+		writer := &captureWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		if writer.Status() >= http.StatusInternalServerError {
+			return
+		}
+		headers := writer.Header().Clone()
+		for _, name := range replaySkipHeaders {
+			headers.Del(name)
+		}
+		if err := s.Complete(ctx, scope, key, writer.Status(), headers, writer.body.Bytes()); err != nil {
+			_ = c.Error(err)
+			return
+		}
+		completed = true
+	}
+}
+
+// requestScope — пространство ключей: маршрут, тенант и API-ключ клиента.
+// Один и тот же Idempotency-Key разных клиентов или тенантов — разные ключи
+func requestScope(c *gin.Context) string {
+	client := ""
+	if p, ok := auth.FromContext(c.Request.Context()); ok {
+		client = p.KeyID
+	}
+	return strings.Join([]string{c.Request.Method, c.FullPath(), tenant.ID(c.Request.Context()), client}, " ")
+}
+
+// captureWriter дублирует тело ответа в буфер
+type captureWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
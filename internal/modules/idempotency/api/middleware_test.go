@@ -0,0 +1,163 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pksep/comments/internal/modules/idempotency/model"
+	"github.com/pksep/comments/internal/modules/idempotency/service"
+	"github.com/pksep/comments/internal/modules/shared/auth"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+// memoryKeys — хранилище ключей в памяти с той же семантикой уникальности (scope, key)
+type memoryKeys struct {
+	mu   sync.Mutex
+	keys map[string]*model.Key
+}
+
+func (m *memoryKeys) Reserve(ctx context.Context, key *model.Key) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := key.Scope + "|" + key.Key
+	if _, ok := m.keys[id]; ok {
+		return false, nil
+	}
+	k := *key
+	m.keys[id] = &k
+	return true, nil
+}
+
+func (m *memoryKeys) Get(ctx context.Context, scope, key string) (*model.Key, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	k, ok := m.keys[scope+"|"+key]
+	if !ok {
+		return nil, nil
+	}
+	c := *k
+	return &c, nil
+}
+
+func (m *memoryKeys) Complete(ctx context.Context, scope, key string, statusCode int, headers http.Header, body []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	k := m.keys[scope+"|"+key]
+	k.StatusCode = &statusCode
+	k.ResponseHeaders = headers
+	k.ResponseBody = body
+	return nil
+}
+
+func (m *memoryKeys) Release(ctx context.Context, scope, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if k, ok := m.keys[scope+"|"+key]; ok && !k.Completed() {
+		delete(m.keys, scope+"|"+key)
+	}
+	return nil
+}
+
+func (m *memoryKeys) DeleteExpired(ctx context.Context) (int64, error) { return 0, nil }
+
+func (m *memoryKeys) DeleteByAuthor(ctx context.Context, authorID string) (int64, error) {
+	return 0, nil
+}
+
+func newTestRouter(created *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	s := service.NewIdempotencyService(&memoryKeys{keys: map[string]*model.Key{}}, time.Hour)
+
+	r := gin.New()
+	// Тенант и клиент запроса берутся из заголовков, как их выставили бы middleware API
+	r.Use(func(c *gin.Context) {
+		ctx := tenant.WithTenant(c.Request.Context(), tenant.Tenant{ID: c.GetHeader("X-Tenant-ID")})
+		if keyID := c.GetHeader("X-Test-Key"); keyID != "" {
+			ctx = auth.WithPrincipal(ctx, auth.Principal{KeyID: keyID})
+		}
+		c.Request = c.Request.WithContext(ctx)
+	})
+	r.POST("/threads/:id/comments", Middleware(s), func(c *gin.Context) {
+		*created++
+		id := fmt.Sprintf("c%d", *created)
+		c.Header("ETag", `"1"`)
+		c.Header("Location", "/comments/"+id)
+		c.JSON(http.StatusCreated, gin.H{"id": id, "thread_id": c.Param("id")})
+	})
+	return r
+}
+
+func post(r *gin.Engine, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set(Header, "key-1")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddlewareReplaysResponseWithHeaders(t *testing.T) {
+	created := 0
+	r := newTestRouter(&created)
+	tenantA := map[string]string{"X-Tenant-ID": "a"}
+
+	first := post(r, "/threads/t1/comments", `{"content":"hi"}`, tenantA)
+	replay := post(r, "/threads/t1/comments", `{"content":"hi"}`, tenantA)
+
+	if created != 1 {
+		t.Fatalf("handler ran %d times, want 1", created)
+	}
+	if replay.Code != http.StatusCreated || replay.Body.String() != first.Body.String() {
+		t.Fatalf("replay = %d %s, want %d %s", replay.Code, replay.Body, first.Code, first.Body)
+	}
+	if replay.Header().Get(ReplayedHeader) != "true" {
+		t.Fatal("replay is not marked as replayed")
+	}
+	for _, name := range []string{"ETag", "Location", "Content-Type"} {
+		if got, want := replay.Header().Get(name), first.Header().Get(name); got != want {
+			t.Errorf("replayed %s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestMiddlewareRejectsDifferentRequest(t *testing.T) {
+	created := 0
+	r := newTestRouter(&created)
+
+	post(r, "/threads/t1/comments", `{"content":"hi"}`, nil)
+	if w := post(r, "/threads/t1/comments", `{"content":"other"}`, nil); w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("different body: status %d, want 422", w.Code)
+	}
+	// Тот же маршрут и тело, но другой тред в адресе — это другой запрос
+	if w := post(r, "/threads/t2/comments", `{"content":"hi"}`, nil); w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("different path: status %d, want 422", w.Code)
+	}
+	if created != 1 {
+		t.Fatalf("handler ran %d times, want 1", created)
+	}
+}
+
+func TestMiddlewareSeparatesTenantsAndClients(t *testing.T) {
+	created := 0
+	r := newTestRouter(&created)
+
+	a := post(r, "/threads/t1/comments", `{"content":"hi"}`, map[string]string{"X-Tenant-ID": "a"})
+	b := post(r, "/threads/t1/comments", `{"content":"hi"}`, map[string]string{"X-Tenant-ID": "b"})
+	k := post(r, "/threads/t1/comments", `{"content":"hi"}`, map[string]string{"X-Tenant-ID": "a", "X-Test-Key": "k2"})
+
+	if created != 3 {
+		t.Fatalf("handler ran %d times, want 3: keys of other tenants or clients must not replay", created)
+	}
+	if a.Body.String() == b.Body.String() || a.Body.String() == k.Body.String() {
+		t.Fatal("a response of another tenant or client was replayed")
+	}
+}
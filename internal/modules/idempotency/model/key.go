@@ -0,0 +1,25 @@
+package model
+
+import (
+	"net/http"
+	"time"
+)
+
+// Key — сохранённый результат запроса с заголовком Idempotency-Key.
+// Пока запрос обрабатывается, StatusCode, ResponseHeaders и ResponseBody пустые
+type Key struct {
+	Scope           string      `json:"scope" db:"scope"`
+	Key             string      `json:"key" db:"key"`
+	TenantID        string      `json:"tenant_id" db:"tenant_id"`
+	RequestHash     string      `json:"request_hash" db:"request_hash"`
+	StatusCode      *int        `json:"status_code,omitempty" db:"status_code"`
+	ResponseHeaders http.Header `json:"-" db:"response_headers"`
+	ResponseBody    []byte      `json:"-" db:"response_body"`
+	CreatedAt       time.Time   `json:"created_at" db:"created_at"`
+	ExpiresAt       time.Time   `json:"expires_at" db:"expires_at"`
+}
+
+// Completed сообщает, сохранён ли ответ
+func (k *Key) Completed() bool {
+	return k.StatusCode != nil
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pksep/comments/internal/modules/idempotency/model"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+type KeyRepoInterface interface {
+	Reserve(ctx context.Context, key *model.Key) (bool, error)
+	Get(ctx context.Context, scope, key string) (*model.Key, error)
+	Complete(ctx context.Context, scope, key string, statusCode int, headers http.Header, body []byte) error
+	Release(ctx context.Context, scope, key string) error
+	DeleteExpired(ctx context.Context) (int64, error)
+	DeleteByAuthor(ctx context.Context, authorID string) (int64, error)
+}
+
+type KeyRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewKeyRepo(db *pgxpool.Pool) *KeyRepo {
+	return &KeyRepo{db: db}
+}
+
+// Reserve пытается занять ключ. Уникальный (scope, key) гарантирует, что из
+// параллельных одинаковых запросов ключ получит ровно один. Просроченная запись
+// с тем же ключом предварительно удаляется
+func (r *KeyRepo) Reserve(ctx context.Context, key *model.Key) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM idempotency_keys
+		WHERE scope = $1 AND key = $2 AND expires_at < NOW()
+	`, key.Scope, key.Key)
+	if err != nil {
+		return false, err
+	}
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO idempotency_keys (scope, key, tenant_id, request_hash, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (scope, key) DO NOTHING
+	`, key.Scope, key.Key, key.TenantID, key.RequestHash, key.CreatedAt, key.ExpiresAt)
+	if err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// Get возвращает сохранённый ключ или nil, если его нет
+func (r *KeyRepo) Get(ctx context.Context, scope, key string) (*model.Key, error) {
+	var k model.Key
+	err := r.db.QueryRow(ctx, `
+		SELECT scope, key, tenant_id, request_hash, status_code, response_headers, response_body, created_at, expires_at
+		FROM idempotency_keys
+		WHERE scope = $1 AND key = $2
+	`, scope, key).Scan(&k.Scope, &k.Key, &k.TenantID, &k.RequestHash, &k.StatusCode, &k.ResponseHeaders, &k.ResponseBody, &k.CreatedAt, &k.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &k, nil
+}
+
+// Complete сохраняет ответ для повторов
+func (r *KeyRepo) Complete(ctx context.Context, scope, key string, statusCode int, headers http.Header, body []byte) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE idempotency_keys
+		SET status_code = $3, response_headers = $4, response_body = $5
+		WHERE scope = $1 AND key = $2
+	`, scope, key, statusCode, headers, body)
+	return err
+}
+
+// Release освобождает ключ, если запрос завершился ошибкой сервера и его можно повторить
+func (r *KeyRepo) Release(ctx context.Context, scope, key string) error {
+	_, err := r.db.Exec(ctx, `
+		DELETE FROM idempotency_keys
+		WHERE scope = $1 AND key = $2 AND status_code IS NULL
+	`, scope, key)
+	return err
+}
+
+// DeleteExpired удаляет просроченные ключи
+func (r *KeyRepo) DeleteExpired(ctx context.Context) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at < $1`, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// DeleteByAuthor удаляет ключи тенанта, в сохранённом ответе которых есть данные автора.
+// Ответы пишутся encoding/json, поэтому поле author_id в них выглядит ровно как authorPattern
+func (r *KeyRepo) DeleteByAuthor(ctx context.Context, authorID string) (int64, error) {
+	tag, err := r.db.Exec(ctx, `
+		DELETE FROM idempotency_keys
+		WHERE ($2::text = '' OR tenant_id = $2)
+		  AND position($1::bytea IN response_body) > 0
+	`, authorPattern(authorID), tenant.ID(ctx))
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// authorPattern — поле author_id с указанным значением в JSON-ответе
+func authorPattern(authorID string) []byte {
+	id, _ := json.Marshal(authorID)
+	return append([]byte(`"author_id":`), id...)
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	commentModel "github.com/pksep/comments/internal/modules/comments/model"
+)
+
+func TestAuthorPatternMatchesStoredResponse(t *testing.T) {
+	for _, authorID := range []string{"alice", `a"<b>&c`, "юзер"} {
+		body, err := json.Marshal(commentModel.Comment{ID: "c1", AuthorID: authorID, Content: "hi"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(body, authorPattern(authorID)) {
+			t.Errorf("pattern for %q not found in %s", authorID, body)
+		}
+		if bytes.Contains(body, authorPattern(authorID+"x")) {
+			t.Errorf("pattern for %q matches another author", authorID+"x")
+		}
+	}
+}
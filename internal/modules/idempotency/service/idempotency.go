@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/pksep/comments/internal/modules/idempotency/model"
+	"github.com/pksep/comments/internal/modules/idempotency/repository"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+var (
+	// ErrMismatch — ключ уже использован с другим телом запроса
+	ErrMismatch = errors.New("idempotency key was already used with a different request")
+	// ErrInProgress — запрос с этим ключом ещё обрабатывается
+	ErrInProgress = errors.New("request with this idempotency key is still in progress")
+)
+
+type IdempotencyService struct {
+	repo repository.KeyRepoInterface
+	ttl  time.Duration
+}
+
+func NewIdempotencyService(repo repository.KeyRepoInterface, ttl time.Duration) *IdempotencyService {
+	return &IdempotencyService{repo: repo, ttl: ttl}
+}
+
+// Begin начинает обработку запроса с ключом. Возвращает nil, если ключ занят
+// вызывающим и запрос нужно выполнить, либо сохранённый ответ для повтора.
+// Ключ принадлежит тенанту запроса
+func (s *IdempotencyService) Begin(ctx context.Context, scope, key, requestHash string) (*model.Key, error) {
+	// Вторая попытка нужна, если ключ освободили между Reserve и Get
+	for attempt := 0; attempt < 2; attempt++ {
+		now := time.Now()
+		reserved, err := s.repo.Reserve(ctx, &model.Key{
+			Scope:       scope,
+			Key:         key,
+			TenantID:    tenant.IDOrDefault(ctx),
+			RequestHash: requestHash,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(s.ttl),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if reserved {
+			return nil, nil
+		}
+
+		existing, err := s.repo.Get(ctx, scope, key)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			continue
+		}
+		if existing.RequestHash != requestHash {
+			return nil, ErrMismatch
+		}
+		if !existing.Completed() {
+			return nil, ErrInProgress
+		}
+		return existing, nil
+	}
+	return nil, ErrInProgress
+}
+
+// Complete сохраняет ответ на запрос вместе с заголовками
+func (s *IdempotencyService) Complete(ctx context.Context, scope, key string, statusCode int, headers http.Header, body []byte) error {
+	return s.repo.Complete(ctx, scope, key, statusCode, headers, body)
+}
+
+// Release освобождает ключ без сохранения ответа
+func (s *IdempotencyService) Release(ctx context.Context, scope, key string) error {
+	return s.repo.Release(ctx, scope, key)
+}
+
+// RunSweeper периодически удаляет просроченные ключи, пока не отменён ctx
+func (s *IdempotencyService) RunSweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.repo.DeleteExpired(ctx)
+			if err != nil {
+				log.Printf("Ошибка очистки ключей идемпотентности: %v", err)
+			} else if n > 0 {
+				log.Printf("Удалено просроченных ключей идемпотентности: %d", n)
+			}
+		}
+	}
+}
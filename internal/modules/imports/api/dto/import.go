@@ -0,0 +1,7 @@
+package dto
+
+type ImportQuery struct {
+	Format    string `form:"format,default=jsonl" binding:"oneof=jsonl csv"`
+	Source    string `form:"source" binding:"required"`
+	BatchSize int    `form:"batch_size,default=500" binding:"min=1,max=10000"`
+}
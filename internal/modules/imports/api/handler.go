@@ -0,0 +1,46 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pksep/comments/internal/modules/imports/api/dto"
+	"github.com/pksep/comments/internal/modules/imports/model"
+	"github.com/pksep/comments/internal/modules/imports/service"
+)
+
+type ImportHandler struct {
+	service *service.ImportService
+}
+
+func NewImportHandler(service *service.ImportService) *ImportHandler {
+	return &ImportHandler{service: service}
+}
+
+func (h *ImportHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.POST("/import", h.Import) // тело — файл JSONL или CSV
+}
+
+func (h *ImportHandler) Import(c *gin.Context) {
+	var query dto.ImportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.service.Import(c, c.Request.Body, model.Options{
+		Format:    model.Format(query.Format),
+		Source:    query.Source,
+		BatchSize: query.BatchSize,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidFile) || errors.Is(err, service.ErrInvalidFormat) || errors.Is(err, service.ErrSourceRequired) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
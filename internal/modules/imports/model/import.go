@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+type Format string
+
+const (
+	FormatJSONL Format = "jsonl"
+	FormatCSV   Format = "csv"
+)
+
+// Record — одна строка импорта в терминах исходной системы.
+// ID, ParentID и ThreadKey — исходные ключи: UUID переносятся как есть,
+// остальные значения детерминированно отображаются в UUID с учётом источника,
+// поэтому повторный импорт того же файла не создаёт дублей
+type Record struct {
+	ID        string     `json:"id"`
+	ThreadKey string     `json:"thread_key"`
+	ParentID  string     `json:"parent_id"`
+	AuthorID  string     `json:"author_id"`
+	Content   string     `json:"content"`
+	Status    string     `json:"status"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+// Options — параметры запуска импорта
+type Options struct {
+	Format Format
+	// Source — имя исходной системы, пространство имён для отображения ключей в UUID
+	Source string
+	// BatchSize — сколько комментариев вставляется одной транзакцией
+	BatchSize int
+}
+
+// RowError — ошибка одной строки; остальные строки при этом импортируются
+type RowError struct {
+	Line  int    `json:"line"`
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error"`
+}
+
+// Report — итог импорта
+type Report struct {
+	Total    int `json:"total"`
+	Imported int `json:"imported"`
+	// Skipped — строки, комментарии которых уже есть в базе (повторный импорт)
+	Skipped int        `json:"skipped"`
+	Failed  int        `json:"failed"`
+	Errors  []RowError `json:"errors"`
+}
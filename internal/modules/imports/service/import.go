@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/google/uuid"
+	commentModel "github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/imports/model"
+)
+
+const (
+	DefaultBatchSize = 500
+	// lookupChunk — сколько id проверяется в базе одним запросом
+	lookupChunk = 5000
+)
+
+var (
+	// ErrInvalidFormat — неизвестный формат файла
+	ErrInvalidFormat = errors.New("format must be jsonl or csv")
+	// ErrSourceRequired — не указано имя исходной системы
+	ErrSourceRequired = errors.New("source is required")
+	// ErrInvalidFile — файл не удалось прочитать целиком (например, нет заголовка CSV)
+	ErrInvalidFile = errors.New("invalid import file")
+)
+
+// importNamespace — пространство имён UUIDv5 для ключей, которые не являются UUID
+var importNamespace = uuid.NewSHA1(uuid.NameSpaceOID, []byte("pksep/comments/import"))
+
+// CommentStore — операции над комментариями, нужные для импорта
+type CommentStore interface {
+	ThreadsOf(ctx context.Context, ids []string) (map[string]*string, error)
+	ImportBatch(ctx context.Context, comments []commentModel.Comment) error
+}
+
+type ImportService struct {
+	comments CommentStore
+}
+
+func NewImportService(comments CommentStore) *ImportService {
+	return &ImportService{comments: comments}
+}
+
+// entry — строка, прошедшая проверку, вместе с комментарием для вставки
+type entry struct {
+	line     int
+	sourceID string
+	comment  commentModel.Comment
+	// parentID — id родителя после отображения ключа, пусто для корневых комментариев
+	parentID       string
+	sourceParentID string
+	depth          int
+}
+
+// Import читает комментарии из r и вставляет их пачками. Ошибки отдельных строк
+// попадают в отчёт и не прерывают импорт; ответы на не импортированные
+// комментарии тоже отклоняются. Ошибка возвращается, только если файл
+// не удалось прочитать или база недоступна
+func (s *ImportService) Import(ctx context.Context, r io.Reader, opts model.Options) (*model.Report, error) {
+	if opts.Source == "" {
+		return nil, ErrSourceRequired
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+
+	rows, err := readRows(r, opts.Format)
+	if err != nil {
+		if errors.Is(err, ErrInvalidFormat) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+
+	report := &model.Report{Total: len(rows), Errors: []model.RowError{}}
+	fail := func(line int, id string, err error) {
+		report.Failed++
+		report.Errors = append(report.Errors, model.RowError{Line: line, ID: id, Error: err.Error()})
+	}
+
+	// 1. Проверяем строки и отображаем исходные ключи в UUID
+	entries := make(map[string]*entry, len(rows))
+	for _, row := range rows {
+		if row.err != nil {
+			fail(row.line, row.record.ID, row.err)
+			continue
+		}
+		e, err := newEntry(row, opts.Source)
+		if err != nil {
+			fail(row.line, row.record.ID, err)
+			continue
+		}
+		if _, dup := entries[e.comment.ID]; dup {
+			fail(row.line, row.record.ID, fmt.Errorf("duplicate id %q", row.record.ID))
+			continue
+		}
+		entries[e.comment.ID] = e
+	}
+
+	// 2. Ищем в базе уже импортированные комментарии и внешних родителей
+	lookup := make([]string, 0, len(entries))
+	for id, e := range entries {
+		lookup = append(lookup, id)
+		if e.parentID != "" && entries[e.parentID] == nil {
+			lookup = append(lookup, e.parentID)
+		}
+	}
+	existing, err := s.threadsOf(ctx, lookup)
+	if err != nil {
+		return nil, err
+	}
+	for id := range entries {
+		if _, ok := existing[id]; ok {
+			report.Skipped++
+			delete(entries, id)
+		}
+	}
+
+	// 3. Разрешаем родителей в любом порядке и наследуем тред
+	resolver := &resolver{entries: entries, existing: existing, state: map[string]int{}, errs: map[string]error{}}
+	pending := make([]*entry, 0, len(entries))
+	for id, e := range entries {
+		if err := resolver.resolve(id); err != nil {
+			fail(e.line, e.sourceID, err)
+			continue
+		}
+		pending = append(pending, e)
+	}
+
+	// Родители вставляются раньше ответов
+	sort.Slice(pending, func(i, j int) bool {
+		a, b := pending[i], pending[j]
+		if a.depth != b.depth {
+			return a.depth < b.depth
+		}
+		if !a.comment.CreatedAt.Equal(b.comment.CreatedAt) {
+			return a.comment.CreatedAt.Before(b.comment.CreatedAt)
+		}
+		return a.comment.ID < b.comment.ID
+	})
+
+	// 4. Вставляем пачками; пачку с ошибкой повторяем построчно, чтобы найти виноватые строки
+	failed := map[string]bool{}
+	for start := 0; start < len(pending); start += opts.BatchSize {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		end := min(start+opts.BatchSize, len(pending))
+		batch := make([]*entry, 0, end-start)
+		for _, e := range pending[start:end] {
+			if failed[e.parentID] {
+				failed[e.comment.ID] = true
+				fail(e.line, e.sourceID, fmt.Errorf("parent %q was not imported", e.sourceParentID))
+				continue
+			}
+			batch = append(batch, e)
+		}
+		if len(batch) == 0 {
+			continue
+		}
+
+		if err := s.comments.ImportBatch(ctx, comments(batch)); err == nil {
+			report.Imported += len(batch)
+			continue
+		}
+
+		for _, e := range batch {
+			if failed[e.parentID] {
+				failed[e.comment.ID] = true
+				fail(e.line, e.sourceID, fmt.Errorf("parent %q was not imported", e.sourceParentID))
+				continue
+			}
+			if err := s.comments.ImportBatch(ctx, []commentModel.Comment{e.comment}); err != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				failed[e.comment.ID] = true
+				fail(e.line, e.sourceID, err)
+				continue
+			}
+			report.Imported++
+		}
+	}
+
+	sort.SliceStable(report.Errors, func(i, j int) bool {
+		return report.Errors[i].Line < report.Errors[j].Line
+	})
+	return report, nil
+}
+
+func (s *ImportService) threadsOf(ctx context.Context, ids []string) (map[string]*string, error) {
+	result := make(map[string]*string)
+	for start := 0; start < len(ids); start += lookupChunk {
+		chunk, err := s.comments.ThreadsOf(ctx, ids[start:min(start+lookupChunk, len(ids))])
+		if err != nil {
+			return nil, err
+		}
+		for id, threadID := range chunk {
+			result[id] = threadID
+		}
+	}
+	return result, nil
+}
+
+func newEntry(row row, source string) (*entry, error) {
+	rec := row.record
+	switch {
+	case rec.ID == "":
+		return nil, errors.New("id is required")
+	case rec.AuthorID == "":
+		return nil, errors.New("author_id is required")
+	case rec.Content == "":
+		return nil, errors.New("content is required")
+	case rec.CreatedAt.IsZero():
+		return nil, errors.New("created_at is required")
+	case rec.ParentID == rec.ID:
+		return nil, errors.New("comment cannot be its own parent")
+	}
+
+	status := commentModel.CommentStatus(rec.Status)
+	switch status {
+	case "":
+		status = commentModel.CommentStatusCreated
+	case commentModel.CommentStatusCreated, commentModel.CommentStatusEdited, commentModel.CommentStatusDeleted,
+		commentModel.CommentStatusPending, commentModel.CommentStatusHidden:
+	default:
+		return nil, fmt.Errorf("unknown status %q", rec.Status)
+	}
+
+	updatedAt := rec.CreatedAt
+	if rec.UpdatedAt != nil && !rec.UpdatedAt.IsZero() {
+		if rec.UpdatedAt.Before(rec.CreatedAt) {
+			return nil, errors.New("updated_at is before created_at")
+		}
+		updatedAt = *rec.UpdatedAt
+	}
+
+	e := &entry{
+		line:     row.line,
+		sourceID: rec.ID,
+		comment: commentModel.Comment{
+			ID:        mapKey(source, "comment", rec.ID),
+			AuthorID:  rec.AuthorID,
+			Content:   rec.Content,
+			Status:    status,
+			Version:   1,
+			CreatedAt: rec.CreatedAt.UTC(),
+			UpdatedAt: updatedAt.UTC(),
+		},
+	}
+	if rec.ThreadKey != "" {
+		threadID := mapKey(source, "thread", rec.ThreadKey)
+		e.comment.ThreadID = &threadID
+	}
+	if rec.ParentID != "" {
+		e.parentID = mapKey(source, "comment", rec.ParentID)
+		e.sourceParentID = rec.ParentID
+		e.comment.AnswerCommentID = &e.parentID
+	}
+	return e, nil
+}
+
+// mapKey отображает исходный ключ в UUID. UUID сохраняются как есть
+func mapKey(source, kind, key string) string {
+	if id, err := uuid.Parse(key); err == nil {
+		return id.String()
+	}
+	return uuid.NewSHA1(importNamespace, []byte(source+"\x00"+kind+"\x00"+key)).String()
+}
+
+func comments(entries []*entry) []commentModel.Comment {
+	result := make([]commentModel.Comment, len(entries))
+	for i, e := range entries {
+		result[i] = e.comment
+	}
+	return result
+}
+
+const (
+	stateVisiting = iota + 1
+	stateDone
+)
+
+// resolver проставляет комментариям тред и глубину по цепочке родителей
+type resolver struct {
+	entries  map[string]*entry
+	existing map[string]*string
+	state    map[string]int
+	errs     map[string]error
+}
+
+func (r *resolver) resolve(id string) error {
+	switch r.state[id] {
+	case stateDone:
+		return r.errs[id]
+	case stateVisiting:
+		return errors.New("cycle in parent references")
+	}
+	r.state[id] = stateVisiting
+	err := r.link(r.entries[id])
+	r.state[id] = stateDone
+	r.errs[id] = err
+	return err
+}
+
+func (r *resolver) link(e *entry) error {
+	if e.parentID == "" {
+		if e.comment.ThreadID == nil {
+			return errors.New("thread_key is required for a comment without parent_id")
+		}
+		return nil
+	}
+
+	var parentThread *string
+	if parent, ok := r.entries[e.parentID]; ok {
+		if err := r.resolve(e.parentID); err != nil {
+			return fmt.Errorf("parent %q was not imported: %w", e.sourceParentID, err)
+		}
+		parentThread = parent.comment.ThreadID
+		e.depth = parent.depth + 1
+	} else if threadID, ok := r.existing[e.parentID]; ok {
+		parentThread = threadID
+	} else {
+		return fmt.Errorf("parent %q not found", e.sourceParentID)
+	}
+
+	if e.comment.ThreadID == nil {
+		e.comment.ThreadID = parentThread
+	} else if parentThread == nil || *parentThread != *e.comment.ThreadID {
+		return errors.New("thread_key differs from the parent's thread")
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	commentModel "github.com/pksep/comments/internal/modules/comments/model"
+	"github.com/pksep/comments/internal/modules/imports/model"
+)
+
+// memStore вставляет пачки целиком или не вставляет вовсе, как транзакция ImportBatch
+type memStore struct {
+	threads  map[string]*string
+	inserted []commentModel.Comment
+	batches  int
+}
+
+func newMemStore() *memStore {
+	return &memStore{threads: map[string]*string{}}
+}
+
+func (s *memStore) ThreadsOf(ctx context.Context, ids []string) (map[string]*string, error) {
+	found := map[string]*string{}
+	for _, id := range ids {
+		if thread, ok := s.threads[id]; ok {
+			found[id] = thread
+		}
+	}
+	return found, nil
+}
+
+func (s *memStore) ImportBatch(ctx context.Context, comments []commentModel.Comment) error {
+	s.batches++
+	for _, c := range comments {
+		if c.Content == "poison" {
+			return errors.New("constraint violation")
+		}
+		if c.AnswerCommentID != nil {
+			if _, ok := s.threads[*c.AnswerCommentID]; !ok {
+				return errors.New("parent is not inserted yet")
+			}
+		}
+	}
+	for _, c := range comments {
+		s.threads[c.ID] = c.ThreadID
+		s.inserted = append(s.inserted, c)
+	}
+	return nil
+}
+
+func importJSONL(t *testing.T, store *memStore, lines ...string) *model.Report {
+	t.Helper()
+	report, err := NewImportService(store).Import(context.Background(), strings.NewReader(strings.Join(lines, "\n")),
+		model.Options{Format: model.FormatJSONL, Source: "legacy", BatchSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return report
+}
+
+func TestImportResolvesParentsInAnyOrder(t *testing.T) {
+	store := newMemStore()
+	report := importJSONL(t, store,
+		`{"id":"3","parent_id":"2","author_id":"a","content":"deep","created_at":"2020-01-01T00:03:00Z"}`,
+		`{"id":"2","parent_id":"1","author_id":"b","content":"reply","created_at":"2020-01-01T00:02:00Z"}`,
+		`{"id":"1","thread_key":"T","author_id":"a","content":"root","created_at":"2020-01-01T00:01:00Z"}`,
+	)
+	if report.Imported != 3 || report.Failed != 0 {
+		t.Fatalf("report = %+v", report)
+	}
+
+	root := mapKey("legacy", "comment", "1")
+	thread := mapKey("legacy", "thread", "T")
+	for i, c := range store.inserted {
+		if c.ThreadID == nil || *c.ThreadID != thread {
+			t.Errorf("%s: thread = %v, want inherited %s", c.ID, c.ThreadID, thread)
+		}
+		if i == 0 && c.ID != root {
+			t.Errorf("first inserted %s, want root %s", c.ID, root)
+		}
+	}
+	if got := store.inserted[2].CreatedAt.Format("15:04"); got != "00:03" {
+		t.Errorf("created_at is not preserved: %s", got)
+	}
+
+	// Повторный импорт того же файла даёт те же id и ничего не вставляет
+	again := importJSONL(t, store,
+		`{"id":"1","thread_key":"T","author_id":"a","content":"root","created_at":"2020-01-01T00:01:00Z"}`,
+	)
+	if again.Skipped != 1 || again.Imported != 0 {
+		t.Fatalf("re-import report = %+v", again)
+	}
+}
+
+func TestImportReportsRowErrors(t *testing.T) {
+	store := newMemStore()
+	report := importJSONL(t, store,
+		`{"id":"1","thread_key":"T","author_id":"a","content":"ok","created_at":"2020-01-01T00:00:00Z"}`,
+		`{"id":"2","thread_key":"T","content":"no author","created_at":"2020-01-01T00:00:00Z"}`,
+		`{"id":"3","parent_id":"404","author_id":"a","content":"orphan","created_at":"2020-01-01T00:00:00Z"}`,
+		`{"id":"4","parent_id":"5","author_id":"a","content":"cycle","created_at":"2020-01-01T00:00:00Z"}`,
+		`{"id":"5","parent_id":"4","author_id":"a","content":"cycle","created_at":"2020-01-01T00:00:00Z"}`,
+		`{"id":"6","parent_id":"1","thread_key":"other","author_id":"a","content":"moved","created_at":"2020-01-01T00:00:00Z"}`,
+		`not json`,
+		`{"id":"1","thread_key":"T","author_id":"a","content":"dup","created_at":"2020-01-01T00:00:00Z"}`,
+	)
+	if report.Total != 8 || report.Imported != 1 || report.Failed != 7 {
+		t.Fatalf("report = %+v", report)
+	}
+	for i := 1; i < len(report.Errors); i++ {
+		if report.Errors[i-1].Line > report.Errors[i].Line {
+			t.Fatalf("errors are not sorted by line: %+v", report.Errors)
+		}
+	}
+	if e := report.Errors[0]; e.Line != 2 || e.ID != "2" || !strings.Contains(e.Error, "author_id") {
+		t.Fatalf("first error = %+v", e)
+	}
+}
+
+func TestImportRetriesFailedBatchRowByRow(t *testing.T) {
+	store := newMemStore()
+	report := importJSONL(t, store,
+		`{"id":"1","thread_key":"T","author_id":"a","content":"root","created_at":"2020-01-01T00:01:00Z"}`,
+		`{"id":"2","thread_key":"U","author_id":"a","content":"poison","created_at":"2020-01-01T00:02:00Z"}`,
+		`{"id":"3","parent_id":"2","author_id":"b","content":"reply to poison","created_at":"2020-01-01T00:03:00Z"}`,
+		`{"id":"4","parent_id":"1","author_id":"b","content":"fine","created_at":"2020-01-01T00:04:00Z"}`,
+	)
+	if report.Imported != 2 || report.Failed != 2 {
+		t.Fatalf("report = %+v", report)
+	}
+	if report.Errors[0].ID != "2" || report.Errors[1].ID != "3" || !strings.Contains(report.Errors[1].Error, "not imported") {
+		t.Fatalf("errors = %+v", report.Errors)
+	}
+}
+
+func TestImportCSV(t *testing.T) {
+	store := newMemStore()
+	csv := "id,thread_key,parent_id,author_id,content,created_at,updated_at\n" +
+		"1,T,,a,\"root, with comma\",2020-01-01T00:00:00Z,\n" +
+		"2,,1,b,reply,2020-01-01T00:01:00Z,2020-01-01T00:05:00Z\n" +
+		"3,,1,b,bad time,yesterday,\n"
+	report, err := NewImportService(store).Import(context.Background(), strings.NewReader(csv),
+		model.Options{Format: model.FormatCSV, Source: "legacy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Imported != 2 || report.Failed != 1 || report.Errors[0].Line != 4 {
+		t.Fatalf("report = %+v", report)
+	}
+	if store.inserted[0].Content != "root, with comma" || !store.inserted[1].UpdatedAt.After(store.inserted[1].CreatedAt) {
+		t.Fatalf("inserted = %+v", store.inserted)
+	}
+
+	_, err = NewImportService(store).Import(context.Background(), strings.NewReader("id,content\n"),
+		model.Options{Format: model.FormatCSV, Source: "legacy"})
+	if !errors.Is(err, ErrInvalidFile) {
+		t.Fatalf("missing columns: err = %v, want ErrInvalidFile", err)
+	}
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pksep/comments/internal/modules/imports/model"
+)
+
+// maxLineSize — максимальная длина строки JSONL
+const maxLineSize = 16 << 20
+
+// row — разобранная строка файла; err заполняется, если строку не удалось прочитать
+type row struct {
+	line   int
+	record model.Record
+	err    error
+}
+
+// readRows читает файл целиком: родители могут идти после ответов,
+// поэтому связи разрешаются только после чтения всех строк
+func readRows(r io.Reader, format model.Format) ([]row, error) {
+	switch format {
+	case model.FormatJSONL:
+		return readJSONL(r)
+	case model.FormatCSV:
+		return readCSV(r)
+	default:
+		return nil, ErrInvalidFormat
+	}
+}
+
+func readJSONL(r io.Reader) ([]row, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+
+	var rows []row
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var rec model.Record
+		err := json.Unmarshal([]byte(text), &rec)
+		rows = append(rows, row{line: line, record: rec, err: err})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("line %d: %w", line+1, err)
+	}
+	return rows, nil
+}
+
+var requiredColumns = []string{"id", "author_id", "content", "created_at"}
+
+func readCSV(r io.Reader) ([]row, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range requiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing required column %q", name)
+		}
+	}
+
+	var rows []row
+	for {
+		fields, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line, _ := cr.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, err
+			}
+			rows = append(rows, row{line: parseErr.StartLine, err: err})
+			continue
+		}
+
+		get := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(fields) {
+				return ""
+			}
+			return fields[i]
+		}
+
+		rec := model.Record{
+			ID:        get("id"),
+			ThreadKey: get("thread_key"),
+			ParentID:  get("parent_id"),
+			AuthorID:  get("author_id"),
+			Content:   get("content"),
+			Status:    get("status"),
+		}
+		rec.CreatedAt, err = parseTime(get("created_at"))
+		if err == nil && get("updated_at") != "" {
+			var updatedAt time.Time
+			updatedAt, err = parseTime(get("updated_at"))
+			rec.UpdatedAt = &updatedAt
+		}
+		rows = append(rows, row{line: line, record: rec, err: err})
+	}
+	return rows, nil
+}
+
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+// Scope — право API-ключа
+type Scope string
+
+const (
+	// ScopeRead — чтение тредов и комментариев
+	ScopeRead Scope = "read"
+	// ScopeWrite — создание и изменение комментариев, голоса, подписки, черновики
+	ScopeWrite Scope = "write"
+	// ScopeModerate — очередь и действия модерации
+	ScopeModerate Scope = "moderate"
+	// ScopeAdmin — служебные маршруты /admin; включает все остальные права
+	ScopeAdmin Scope = "admin"
+)
+
+// Valid сообщает, известен ли scope
+func (s Scope) Valid() bool {
+	switch s {
+	case ScopeRead, ScopeWrite, ScopeModerate, ScopeAdmin:
+		return true
+	}
+	return false
+}
+
+// Principal — клиент, прошедший проверку API-ключа
+type Principal struct {
+	KeyID  string
+	Name   string
+	Scopes []Scope
+	// TenantID — единственный тенант, в котором работает ключ; nil — любой
+	TenantID *string
+}
+
+// Has сообщает, есть ли у клиента право scope
+func (p Principal) Has(scope Scope) bool {
+	return slices.Contains(p.Scopes, scope) || slices.Contains(p.Scopes, ScopeAdmin)
+}
+
+// AllowsTenant сообщает, может ли клиент работать с тенантом id
+func (p Principal) AllowsTenant(id string) bool {
+	return p.TenantID == nil || *p.TenantID == id
+}
+
+type ctxKey struct{}
+
+// WithPrincipal кладёт клиента запроса в контекст
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, ctxKey{}, p)
+}
+
+// FromContext достаёт клиента запроса; false — запрос без API-ключа
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(ctxKey{}).(Principal)
+	return p, ok
+}
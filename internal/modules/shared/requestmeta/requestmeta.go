@@ -0,0 +1,23 @@
+package requestmeta
+
+import "context"
+
+// Meta — сведения о входящем HTTP-запросе, нужные ниже по стеку (аудит, логи)
+type Meta struct {
+	RequestID string
+	IP        string
+	UserAgent string
+}
+
+type ctxKey struct{}
+
+// WithMeta кладёт сведения о запросе в контекст
+func WithMeta(ctx context.Context, m Meta) context.Context {
+	return context.WithValue(ctx, ctxKey{}, m)
+}
+
+// FromContext достаёт сведения о запросе; вне HTTP-запроса возвращает пустую структуру
+func FromContext(ctx context.Context) Meta {
+	m, _ := ctx.Value(ctxKey{}).(Meta)
+	return m
+}
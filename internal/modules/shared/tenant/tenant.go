@@ -0,0 +1,103 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Default — тенант запросов без явного тенанта и данных, созданных до разделения на тенанты
+const Default = "default"
+
+// Feature — отключаемая для тенанта возможность
+type Feature string
+
+const (
+	FeatureVotes      Feature = "votes"
+	FeaturePins       Feature = "pins"
+	FeatureAnswers    Feature = "answers"
+	FeatureAnchors    Feature = "anchors"
+	FeatureScheduling Feature = "scheduling"
+	FeatureReports    Feature = "reports"
+)
+
+// ErrFeatureDisabled — возможность отключена в настройках тенанта
+var ErrFeatureDisabled = errors.New("feature is disabled for this tenant")
+
+// Settings — лимиты и возможности тенанта. Незаданные лимиты берутся из глобальной
+// конфигурации, возможности включены, пока не выключены явно
+type Settings struct {
+	// Максимум закреплённых комментариев в треде (0 — без ограничений)
+	MaxPinnedPerThread *int `json:"max_pinned_per_thread,omitempty"`
+	// Число жалоб для автоскрытия комментария (0 — не скрывать)
+	AutoHideReports *int `json:"auto_hide_reports,omitempty"`
+	// Максимальная длина комментария в символах (0 — без ограничений)
+	MaxContentLength int              `json:"max_content_length,omitempty"`
+	Features         map[Feature]bool `json:"features,omitempty"`
+}
+
+// ErrInvalidSettings — отрицательный лимит в настройках тенанта
+var ErrInvalidSettings = errors.New("tenant limits must not be negative")
+
+// Validate проверяет лимиты тенанта
+func (s Settings) Validate() error {
+	if (s.MaxPinnedPerThread != nil && *s.MaxPinnedPerThread < 0) ||
+		(s.AutoHideReports != nil && *s.AutoHideReports < 0) ||
+		s.MaxContentLength < 0 {
+		return ErrInvalidSettings
+	}
+	return nil
+}
+
+// Enabled сообщает, включена ли возможность
+func (s Settings) Enabled(f Feature) bool {
+	on, ok := s.Features[f]
+	return !ok || on
+}
+
+// Tenant — продукт или клиент, чьи треды и комментарии изолированы от остальных
+type Tenant struct {
+	ID        string    `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	Settings  Settings  `json:"settings" db:"settings"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type ctxKey struct{}
+
+// WithTenant кладёт тенант запроса в контекст
+func WithTenant(ctx context.Context, t Tenant) context.Context {
+	return context.WithValue(ctx, ctxKey{}, t)
+}
+
+// FromContext достаёт тенант запроса; вне HTTP-запроса (фоновые задачи) — false
+func FromContext(ctx context.Context) (Tenant, bool) {
+	t, ok := ctx.Value(ctxKey{}).(Tenant)
+	return t, ok
+}
+
+// ID возвращает id тенанта запроса или пустую строку вне запроса.
+// Пустой id не ограничивает выборку: фоновые задачи работают со всеми тенантами
+func ID(ctx context.Context) string {
+	t, _ := FromContext(ctx)
+	return t.ID
+}
+
+// IDOrDefault возвращает id тенанта для записи новых данных
+func IDOrDefault(ctx context.Context) string {
+	if id := ID(ctx); id != "" {
+		return id
+	}
+	return Default
+}
+
+// Require проверяет, что возможность включена у тенанта запроса
+func Require(ctx context.Context, f Feature) error {
+	t, ok := FromContext(ctx)
+	if ok && !t.Settings.Enabled(f) {
+		return fmt.Errorf("%w: %s", ErrFeatureDisabled, f)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRequire(t *testing.T) {
+	ctx := WithTenant(context.Background(), Tenant{ID: "acme", Settings: Settings{
+		Features: map[Feature]bool{FeatureVotes: false, FeaturePins: true},
+	}})
+
+	cases := []struct {
+		name string
+		ctx  context.Context
+		f    Feature
+		want error
+	}{
+		{"disabled", ctx, FeatureVotes, ErrFeatureDisabled},
+		{"enabled", ctx, FeaturePins, nil},
+		{"not set", ctx, FeatureAnchors, nil},
+		// Фоновые задачи работают без тенанта и без ограничений
+		{"no tenant", context.Background(), FeatureVotes, nil},
+	}
+	for _, tc := range cases {
+		if err := Require(tc.ctx, tc.f); !errors.Is(err, tc.want) {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestIDOrDefault(t *testing.T) {
+	if got := IDOrDefault(context.Background()); got != Default {
+		t.Errorf("no tenant: %q, want %q", got, Default)
+	}
+	if got := ID(context.Background()); got != "" {
+		t.Errorf("ID without tenant = %q, want empty", got)
+	}
+	if got := IDOrDefault(WithTenant(context.Background(), Tenant{ID: "acme"})); got != "acme" {
+		t.Errorf("acme: %q", got)
+	}
+}
+
+func TestSettingsValidate(t *testing.T) {
+	negative, zero := -1, 0
+	cases := []struct {
+		name string
+		s    Settings
+		want error
+	}{
+		{"empty", Settings{}, nil},
+		{"zero limits", Settings{MaxPinnedPerThread: &zero, AutoHideReports: &zero}, nil},
+		{"negative pins", Settings{MaxPinnedPerThread: &negative}, ErrInvalidSettings},
+		{"negative reports", Settings{AutoHideReports: &negative}, ErrInvalidSettings},
+		{"negative length", Settings{MaxContentLength: -1}, ErrInvalidSettings},
+	}
+	for _, tc := range cases {
+		if err := tc.s.Validate(); !errors.Is(err, tc.want) {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
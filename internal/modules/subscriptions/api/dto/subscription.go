@@ -0,0 +1,26 @@
+package dto
+
+// SubscriptionDTO — тело POST /subscriptions/follow|mute|unfollow
+type SubscriptionDTO struct {
+	ThreadID string `json:"thread_id" binding:"required,uuid"`
+	UserID   string `json:"user_id" binding:"required"`
+}
+
+// PutSubscriptionDTO — тело PUT /v2/threads/:id/subscription
+type PutSubscriptionDTO struct {
+	UserID string `json:"user_id" binding:"required"`
+	State  string `json:"state" binding:"required,oneof=following muted"`
+}
+
+// ListQuery — параметры GET /subscriptions
+type ListQuery struct {
+	UserID string `form:"user_id" binding:"required"`
+	State  string `form:"state" binding:"omitempty,oneof=following muted"`
+	Limit  int    `form:"limit,default=50" binding:"min=1,max=200"`
+	Offset int    `form:"offset" binding:"min=0"`
+}
+
+// UserQuery — параметр user_id
+type UserQuery struct {
+	UserID string `form:"user_id" binding:"required"`
+}
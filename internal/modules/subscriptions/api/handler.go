@@ -0,0 +1,196 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pksep/comments/internal/modules/subscriptions/api/dto"
+	"github.com/pksep/comments/internal/modules/subscriptions/model"
+	"github.com/pksep/comments/internal/modules/subscriptions/repository"
+	"github.com/pksep/comments/internal/modules/subscriptions/service"
+)
+
+type SubscriptionHandler struct {
+	service *service.SubscriptionService
+}
+
+func NewSubscriptionHandler(service *service.SubscriptionService) *SubscriptionHandler {
+	return &SubscriptionHandler{service: service}
+}
+
+func (h *SubscriptionHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	subs := rg.Group("/subscriptions")
+	{
+		subs.GET("", h.List) // ?user_id=&state=following|muted&limit=&offset=
+		subs.POST("/follow", h.Follow)
+		subs.POST("/mute", h.Mute)
+		subs.POST("/unfollow", h.Unfollow)
+	}
+}
+
+// RegisterRoutesV2 регистрирует подписку как ресурс треда
+func (h *SubscriptionHandler) RegisterRoutesV2(rg *gin.RouterGroup) {
+	threads := rg.Group("/threads")
+	{
+		threads.GET("/:id/subscription", h.GetV2) // ?user_id=
+		threads.PUT("/:id/subscription", h.PutV2)
+		threads.DELETE("/:id/subscription", h.DeleteV2) // ?user_id=
+	}
+}
+
+// RegisterAdminRoutes регистрирует служебные маршруты для сервиса уведомлений
+func (h *SubscriptionHandler) RegisterAdminRoutes(rg *gin.RouterGroup) {
+	rg.GET("/subscriptions/recipients/:commentId", h.Recipients)
+}
+
+func (h *SubscriptionHandler) List(c *gin.Context) {
+	var query dto.ListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	subs, err := h.service.List(c, model.ListFilter{
+		UserID: query.UserID,
+		State:  model.State(query.State),
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, subs)
+}
+
+func (h *SubscriptionHandler) Follow(c *gin.Context) {
+	h.set(c, model.StateFollowing)
+}
+
+func (h *SubscriptionHandler) Mute(c *gin.Context) {
+	h.set(c, model.StateMuted)
+}
+
+func (h *SubscriptionHandler) set(c *gin.Context, state model.State) {
+	var body dto.SubscriptionDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sub, err := h.service.Set(c, body.ThreadID, body.UserID, state)
+	if err != nil {
+		c.JSON(setErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, sub)
+}
+
+func (h *SubscriptionHandler) Unfollow(c *gin.Context) {
+	var body dto.SubscriptionDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.Unfollow(c, body.ThreadID, body.UserID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *SubscriptionHandler) GetV2(c *gin.Context) {
+	var query dto.UserQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	threadID, ok := parseThreadID(c)
+	if !ok {
+		return
+	}
+
+	sub, err := h.service.Get(c, threadID, query.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if sub == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
+		return
+	}
+	c.JSON(http.StatusOK, sub)
+}
+
+func (h *SubscriptionHandler) PutV2(c *gin.Context) {
+	var body dto.PutSubscriptionDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	threadID, ok := parseThreadID(c)
+	if !ok {
+		return
+	}
+
+	sub, err := h.service.Set(c, threadID, body.UserID, model.State(body.State))
+	if err != nil {
+		c.JSON(setErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, sub)
+}
+
+func (h *SubscriptionHandler) DeleteV2(c *gin.Context) {
+	var query dto.UserQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	threadID, ok := parseThreadID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Unfollow(c, threadID, query.UserID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *SubscriptionHandler) Recipients(c *gin.Context) {
+	commentID := c.Param("commentId")
+	if _, err := uuid.Parse(commentID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid comment id"})
+		return
+	}
+
+	users, err := h.service.Subscribers(c, commentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"comment_id": commentID, "user_ids": users})
+}
+
+// parseThreadID проверяет id треда из пути: id тредов — UUID
+func parseThreadID(c *gin.Context) (string, bool) {
+	threadID := c.Param("id")
+	if _, err := uuid.Parse(threadID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid thread id"})
+		return "", false
+	}
+	return threadID, true
+}
+
+// setErrorStatus: тред другого тенанта для вызывающего не существует
+func setErrorStatus(err error) int {
+	if errors.Is(err, repository.ErrNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
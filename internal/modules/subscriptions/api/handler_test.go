@@ -0,0 +1,147 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pksep/comments/internal/modules/subscriptions/model"
+	"github.com/pksep/comments/internal/modules/subscriptions/repository"
+	"github.com/pksep/comments/internal/modules/subscriptions/service"
+)
+
+// memSubs хранит подписки в памяти; треды из foreign принадлежат другому тенанту
+type memSubs struct {
+	repository.SubscriptionRepoInterface
+	subs    map[[2]string]model.Subscription
+	foreign map[string]bool
+}
+
+func (m *memSubs) Set(ctx context.Context, threadID, userID string, state model.State) (*model.Subscription, error) {
+	if m.foreign[threadID] {
+		return nil, repository.ErrNotFound
+	}
+	s := model.Subscription{ThreadID: threadID, UserID: userID, State: state}
+	m.subs[[2]string{threadID, userID}] = s
+	return &s, nil
+}
+
+func (m *memSubs) Get(ctx context.Context, threadID, userID string) (*model.Subscription, error) {
+	s, ok := m.subs[[2]string{threadID, userID}]
+	if !ok {
+		return nil, nil
+	}
+	return &s, nil
+}
+
+func (m *memSubs) Delete(ctx context.Context, threadID, userID string) error {
+	delete(m.subs, [2]string{threadID, userID})
+	return nil
+}
+
+func (m *memSubs) Recipients(ctx context.Context, commentID string) ([]string, error) {
+	return []string{"u1"}, nil
+}
+
+func newTestRouter(repo *memSubs) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewSubscriptionHandler(service.NewSubscriptionService(repo))
+	h.RegisterRoutes(r.Group("/api"))
+	h.RegisterRoutesV2(r.Group("/api/v2"))
+	h.RegisterAdminRoutes(r.Group("/admin"))
+	return r
+}
+
+func request(r *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestFollowMuteUnfollow(t *testing.T) {
+	thread := uuid.NewString()
+	repo := &memSubs{subs: map[[2]string]model.Subscription{}}
+	r := newTestRouter(repo)
+	body := fmt.Sprintf(`{"thread_id":%q,"user_id":"u1"}`, thread)
+	key := [2]string{thread, "u1"}
+
+	if w := request(r, http.MethodPost, "/api/subscriptions/follow", body); w.Code != http.StatusOK {
+		t.Fatalf("follow: %d %s", w.Code, w.Body)
+	}
+	if repo.subs[key].State != model.StateFollowing {
+		t.Fatalf("state = %q, want following", repo.subs[key].State)
+	}
+	if w := request(r, http.MethodPost, "/api/subscriptions/mute", body); w.Code != http.StatusOK {
+		t.Fatalf("mute: %d %s", w.Code, w.Body)
+	}
+	if repo.subs[key].State != model.StateMuted {
+		t.Fatalf("state = %q, want muted", repo.subs[key].State)
+	}
+	if w := request(r, http.MethodPost, "/api/subscriptions/unfollow", body); w.Code != http.StatusNoContent {
+		t.Fatalf("unfollow: %d %s", w.Code, w.Body)
+	}
+	if _, ok := repo.subs[key]; ok {
+		t.Fatal("subscription survived unfollow")
+	}
+}
+
+func TestSubscriptionV2(t *testing.T) {
+	thread := uuid.NewString()
+	repo := &memSubs{subs: map[[2]string]model.Subscription{}}
+	r := newTestRouter(repo)
+	path := "/api/v2/threads/" + thread + "/subscription"
+
+	if w := request(r, http.MethodGet, path+"?user_id=u1", ""); w.Code != http.StatusNotFound {
+		t.Fatalf("GET before PUT: %d, want 404", w.Code)
+	}
+	if w := request(r, http.MethodPut, path, `{"user_id":"u1","state":"muted"}`); w.Code != http.StatusOK {
+		t.Fatalf("PUT: %d %s", w.Code, w.Body)
+	}
+	w := request(r, http.MethodGet, path+"?user_id=u1", "")
+	var sub model.Subscription
+	if err := json.Unmarshal(w.Body.Bytes(), &sub); err != nil || sub.State != model.StateMuted {
+		t.Fatalf("GET: %d %s", w.Code, w.Body)
+	}
+	if w := request(r, http.MethodDelete, path+"?user_id=u1", ""); w.Code != http.StatusNoContent {
+		t.Fatalf("DELETE: %d %s", w.Code, w.Body)
+	}
+	if w := request(r, http.MethodGet, path+"?user_id=u1", ""); w.Code != http.StatusNotFound {
+		t.Fatalf("GET after DELETE: %d, want 404", w.Code)
+	}
+}
+
+func TestSubscriptionErrors(t *testing.T) {
+	foreign := uuid.NewString()
+	repo := &memSubs{subs: map[[2]string]model.Subscription{}, foreign: map[string]bool{foreign: true}}
+	r := newTestRouter(repo)
+
+	cases := []struct {
+		name, method, path, body string
+		want                     int
+	}{
+		{"bad state", http.MethodPut, "/api/v2/threads/" + uuid.NewString() + "/subscription", `{"user_id":"u1","state":"watching"}`, http.StatusBadRequest},
+		{"bad thread id", http.MethodPut, "/api/v2/threads/nope/subscription", `{"user_id":"u1","state":"muted"}`, http.StatusBadRequest},
+		{"no user", http.MethodPost, "/api/subscriptions/follow", fmt.Sprintf(`{"thread_id":%q}`, uuid.NewString()), http.StatusBadRequest},
+		{"other tenant", http.MethodPost, "/api/subscriptions/follow", fmt.Sprintf(`{"thread_id":%q,"user_id":"u1"}`, foreign), http.StatusNotFound},
+		{"bad comment id", http.MethodGet, "/admin/subscriptions/recipients/nope", "", http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		if w := request(r, tc.method, tc.path, tc.body); w.Code != tc.want {
+			t.Errorf("%s: %d %s, want %d", tc.name, w.Code, w.Body, tc.want)
+		}
+	}
+
+	w := request(r, http.MethodGet, "/admin/subscriptions/recipients/"+uuid.NewString(), "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"user_ids":["u1"]`) {
+		t.Fatalf("recipients: %d %s", w.Code, w.Body)
+	}
+}
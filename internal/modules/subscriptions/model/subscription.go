@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+type State string
+
+const (
+	// StateFollowing — пользователь получает уведомления о новых комментариях треда
+	StateFollowing State = "following"
+	// StateMuted — тред заглушён: уведомлений нет, даже об ответах пользователю
+	StateMuted State = "muted"
+)
+
+// Subscription — отношение пользователя к треду. Отсутствие подписки означает,
+// что пользователь уведомляется только об ответах на свои комментарии
+type Subscription struct {
+	ThreadID  string    `json:"thread_id" db:"thread_id"`
+	UserID    string    `json:"user_id" db:"user_id"`
+	State     State     `json:"state" db:"state"`
+	Auto      bool      `json:"auto" db:"auto"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ListFilter — параметры выборки подписок пользователя; пустой State — все
+type ListFilter struct {
+	UserID string
+	State  State
+	Limit  int
+	Offset int
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+	"github.com/pksep/comments/internal/modules/subscriptions/model"
+)
+
+// ErrNotFound — тред принадлежит другому тенанту
+var ErrNotFound = errors.New("not found")
+
+type SubscriptionRepoInterface interface {
+	Set(ctx context.Context, threadID, userID string, state model.State) (*model.Subscription, error)
+	Get(ctx context.Context, threadID, userID string) (*model.Subscription, error)
+	Delete(ctx context.Context, threadID, userID string) error
+	List(ctx context.Context, filter model.ListFilter) ([]model.Subscription, error)
+	AutoFollow(ctx context.Context, threadID, userID string) error
+	Recipients(ctx context.Context, commentID string) ([]string, error)
+	DeleteByUser(ctx context.Context, userID string) error
+}
+
+type SubscriptionRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewSubscriptionRepo(db *pgxpool.Pool) *SubscriptionRepo {
+	return &SubscriptionRepo{db: db}
+}
+
+const subscriptionColumns = `thread_id, user_id, state, auto, created_at, updated_at`
+
+// threadOfTenant — условие принадлежности треда подписки тенанту из параметра $arg;
+// пустой тенант выборку не ограничивает
+func threadOfTenant(arg int) string {
+	return fmt.Sprintf(`($%d::text = '' OR EXISTS (SELECT 1 FROM threads t WHERE t.id = thread_id AND t.tenant_id = $%d))`, arg, arg)
+}
+
+func scanSubscription(row interface{ Scan(...any) error }, s *model.Subscription) error {
+	return row.Scan(&s.ThreadID, &s.UserID, &s.State, &s.Auto, &s.CreatedAt, &s.UpdatedAt)
+}
+
+// Set подписывает пользователя на тред или заглушает его. Явный выбор пользователя
+// заменяет автоматическую подписку. Тред создаётся в тенанте запроса, если его ещё нет
+func (r *SubscriptionRepo) Set(ctx context.Context, threadID, userID string, state model.State) (*model.Subscription, error) {
+	tenantID := tenant.IDOrDefault(ctx)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `INSERT INTO threads (id, tenant_id) VALUES ($1, $2) ON CONFLICT (id) DO NOTHING`, threadID, tenantID); err != nil {
+		return nil, err
+	}
+	var owner string
+	err = tx.QueryRow(ctx, `SELECT tenant_id FROM threads WHERE id = $1`, threadID).Scan(&owner)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if owner != tenantID {
+		return nil, fmt.Errorf("thread with ID %s not found: %w", threadID, ErrNotFound)
+	}
+
+	var s model.Subscription
+	err = scanSubscription(tx.QueryRow(ctx, `
+		INSERT INTO thread_subscriptions (`+subscriptionColumns+`)
+		VALUES ($1, $2, $3, FALSE, NOW(), NOW())
+		ON CONFLICT (thread_id, user_id) DO UPDATE SET state = EXCLUDED.state, auto = FALSE, updated_at = NOW()
+		RETURNING `+subscriptionColumns,
+		threadID, userID, state), &s)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Get возвращает подписку или nil, если её нет
+func (r *SubscriptionRepo) Get(ctx context.Context, threadID, userID string) (*model.Subscription, error) {
+	var s model.Subscription
+	err := scanSubscription(r.db.QueryRow(ctx, `
+		SELECT `+subscriptionColumns+`
+		FROM thread_subscriptions
+		WHERE thread_id = $1 AND user_id = $2 AND `+threadOfTenant(3)+`
+	`, threadID, userID, tenant.ID(ctx)), &s)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Delete отписывает пользователя от треда; отсутствие подписки ошибкой не считается
+func (r *SubscriptionRepo) Delete(ctx context.Context, threadID, userID string) error {
+	_, err := r.db.Exec(ctx, `
+		DELETE FROM thread_subscriptions WHERE thread_id = $1 AND user_id = $2 AND `+threadOfTenant(3),
+		threadID, userID, tenant.ID(ctx))
+	return err
+}
+
+// List возвращает подписки пользователя, недавно изменённые первыми
+func (r *SubscriptionRepo) List(ctx context.Context, filter model.ListFilter) ([]model.Subscription, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+subscriptionColumns+`
+		FROM thread_subscriptions
+		WHERE user_id = $1 AND ($2::text = '' OR state = $2) AND `+threadOfTenant(5)+`
+		ORDER BY updated_at DESC, thread_id
+		LIMIT $3 OFFSET $4
+	`, filter.UserID, string(filter.State), filter.Limit, filter.Offset, tenant.ID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subs := []model.Subscription{}
+	for rows.Next() {
+		var s model.Subscription
+		if err := scanSubscription(rows, &s); err != nil {
+			return nil, err
+		}
+		subs = append(subs, s)
+	}
+	return subs, rows.Err()
+}
+
+// AutoFollow подписывает пользователя на тред, если он ещё не выбрал подписку или
+// заглушение. Вызывается только для первого комментария автора в треде, поэтому
+// отписка после прежних комментариев сохраняется
+func (r *SubscriptionRepo) AutoFollow(ctx context.Context, threadID, userID string) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO thread_subscriptions (`+subscriptionColumns+`)
+		VALUES ($1, $2, 'following', TRUE, NOW(), NOW())
+		ON CONFLICT (thread_id, user_id) DO NOTHING
+	`, threadID, userID)
+	return err
+}
+
+// Recipients возвращает пользователей, которых нужно уведомить о комментарии:
+// подписчиков треда и автора комментария, на который он отвечает. Заглушившие
+// тред и сам автор комментария исключаются
+func (r *SubscriptionRepo) Recipients(ctx context.Context, commentID string) ([]string, error) {
+	rows, err := r.db.Query(ctx, `
+		WITH c AS (
+			SELECT thread_id, author_id, answer_comment_id FROM comments WHERE id = $1
+		)
+		SELECT s.user_id
+		FROM thread_subscriptions s, c
+		WHERE s.thread_id = c.thread_id AND s.state = 'following'
+		UNION
+		SELECT parent.author_id
+		FROM comments parent, c
+		WHERE parent.id = c.answer_comment_id
+		EXCEPT
+		SELECT s.user_id
+		FROM thread_subscriptions s, c
+		WHERE s.thread_id = c.thread_id AND s.state = 'muted'
+		EXCEPT
+		SELECT author_id FROM c
+		ORDER BY 1
+	`, commentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		users = append(users, id)
+	}
+	return users, rows.Err()
+}
+
+// DeleteByUser удаляет все подписки пользователя на треды тенанта
+func (r *SubscriptionRepo) DeleteByUser(ctx context.Context, userID string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM thread_subscriptions WHERE user_id = $1 AND `+threadOfTenant(2), userID, tenant.ID(ctx))
+	return err
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+
+	"github.com/pksep/comments/internal/modules/subscriptions/model"
+	"github.com/pksep/comments/internal/modules/subscriptions/repository"
+)
+
+type SubscriptionService struct {
+	repo repository.SubscriptionRepoInterface
+}
+
+func NewSubscriptionService(repo repository.SubscriptionRepoInterface) *SubscriptionService {
+	return &SubscriptionService{repo: repo}
+}
+
+// Set подписывает пользователя на тред (following) или заглушает его (muted)
+func (s *SubscriptionService) Set(ctx context.Context, threadID, userID string, state model.State) (*model.Subscription, error) {
+	return s.repo.Set(ctx, threadID, userID, state)
+}
+
+// Unfollow удаляет подписку или заглушение
+func (s *SubscriptionService) Unfollow(ctx context.Context, threadID, userID string) error {
+	return s.repo.Delete(ctx, threadID, userID)
+}
+
+// Get возвращает подписку пользователя на тред или nil
+func (s *SubscriptionService) Get(ctx context.Context, threadID, userID string) (*model.Subscription, error) {
+	return s.repo.Get(ctx, threadID, userID)
+}
+
+// List возвращает подписки пользователя
+func (s *SubscriptionService) List(ctx context.Context, filter model.ListFilter) ([]model.Subscription, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// AutoFollow подписывает автора на тред после первого комментария в нём
+func (s *SubscriptionService) AutoFollow(ctx context.Context, threadID, userID string) error {
+	return s.repo.AutoFollow(ctx, threadID, userID)
+}
+
+// Subscribers возвращает пользователей, которых нужно уведомить о новом комментарии
+func (s *SubscriptionService) Subscribers(ctx context.Context, commentID string) ([]string, error) {
+	return s.repo.Recipients(ctx, commentID)
+}
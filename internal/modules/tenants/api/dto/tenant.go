@@ -0,0 +1,9 @@
+package dto
+
+import "github.com/pksep/comments/internal/modules/shared/tenant"
+
+// PutTenantDTO — тело PUT /admin/tenants/:id
+type PutTenantDTO struct {
+	Name     string          `json:"name" binding:"required,max=200"`
+	Settings tenant.Settings `json:"settings"`
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pksep/comments/internal/modules/shared/auth"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+	"github.com/pksep/comments/internal/modules/tenants/api/dto"
+	"github.com/pksep/comments/internal/modules/tenants/service"
+)
+
+// Header — заголовок с id тенанта запроса
+const Header = "X-Tenant-ID"
+
+// tenantIDPattern — допустимый id тенанта: короткий slug
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// Middleware определяет тенант запроса по заголовку X-Tenant-ID, а без заголовка — по
+// API-ключу тенанта или тенант по умолчанию, и кладёт его в контекст. Неизвестный
+// тенант и тенант, в котором API-ключ не работает, получают 403
+func Middleware(s *service.TenantService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(Header)
+		if p, ok := auth.FromContext(c.Request.Context()); ok && p.TenantID != nil {
+			if id == "" {
+				id = *p.TenantID
+			} else if !p.AllowsTenant(id) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "api key is restricted to another tenant"})
+				return
+			}
+		}
+		if id == "" {
+			id = tenant.Default
+		}
+
+		t, err := s.Resolve(c, id)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, service.ErrUnknownTenant) {
+				status = http.StatusForbidden
+			}
+			c.AbortWithStatusJSON(status, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Request = c.Request.WithContext(tenant.WithTenant(c.Request.Context(), *t))
+		c.Next()
+	}
+}
+
+type TenantHandler struct {
+	service *service.TenantService
+}
+
+func NewTenantHandler(service *service.TenantService) *TenantHandler {
+	return &TenantHandler{service: service}
+}
+
+// RegisterRoutes регистрирует управление тенантами в админской группе
+func (h *TenantHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	tenants := rg.Group("/tenants")
+	{
+		tenants.GET("", h.List)
+		tenants.GET("/:id", h.Get)
+		tenants.PUT("/:id", h.Put)
+	}
+}
+
+// List отдаёт все тенанты, а клиенту с ключом тенанта — только его тенант
+func (h *TenantHandler) List(c *gin.Context) {
+	tenants, err := h.service.List(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if p, ok := auth.FromContext(c.Request.Context()); ok && p.TenantID != nil {
+		visible := []tenant.Tenant{}
+		for _, t := range tenants {
+			if p.AllowsTenant(t.ID) {
+				visible = append(visible, t)
+			}
+		}
+		tenants = visible
+	}
+	c.JSON(http.StatusOK, tenants)
+}
+
+func (h *TenantHandler) Get(c *gin.Context) {
+	if !allowed(c, c.Param("id")) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "tenant not found"})
+		return
+	}
+	t, err := h.service.Get(c, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if t == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "tenant not found"})
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+func (h *TenantHandler) Put(c *gin.Context) {
+	id := c.Param("id")
+	if !tenantIDPattern.MatchString(id) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tenant id"})
+		return
+	}
+	if !allowed(c, id) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "api key is restricted to another tenant"})
+		return
+	}
+	var body dto.PutTenantDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := body.Settings.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	t, err := h.service.Save(c, tenant.Tenant{ID: id, Name: body.Name, Settings: body.Settings})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// allowed: клиент с ключом тенанта управляет только своим тенантом
+func allowed(c *gin.Context, id string) bool {
+	p, ok := auth.FromContext(c.Request.Context())
+	return !ok || p.AllowsTenant(id)
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pksep/comments/internal/modules/shared/auth"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+	"github.com/pksep/comments/internal/modules/tenants/repository"
+	"github.com/pksep/comments/internal/modules/tenants/service"
+)
+
+type memTenants struct {
+	repository.TenantRepoInterface
+	tenants map[string]tenant.Tenant
+	reads   int
+}
+
+func (m *memTenants) Get(ctx context.Context, id string) (*tenant.Tenant, error) {
+	m.reads++
+	t, ok := m.tenants[id]
+	if !ok {
+		return nil, nil
+	}
+	return &t, nil
+}
+
+func (m *memTenants) Upsert(ctx context.Context, t *tenant.Tenant) (*tenant.Tenant, error) {
+	m.tenants[t.ID] = *t
+	return t, nil
+}
+
+func newTestRepo() *memTenants {
+	return &memTenants{tenants: map[string]tenant.Tenant{
+		tenant.Default: {ID: tenant.Default},
+		"acme":         {ID: "acme"},
+		"globex":       {ID: "globex"},
+	}}
+}
+
+// newTestRouter отдаёт id тенанта запроса; ключ клиента задаётся заголовком X-Test-Tenant-Key
+func newTestRouter(s *service.TenantService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if id := c.GetHeader("X-Test-Tenant-Key"); id != "" {
+			c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), auth.Principal{KeyID: "k1", TenantID: &id}))
+		}
+	})
+	r.Use(Middleware(s))
+	r.GET("/whoami", func(c *gin.Context) { c.String(http.StatusOK, tenant.ID(c.Request.Context())) })
+	NewTenantHandler(s).RegisterRoutes(r.Group("/admin"))
+	return r
+}
+
+func request(r *gin.Engine, method, path, body string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddlewareResolvesTenant(t *testing.T) {
+	r := newTestRouter(service.NewTenantService(newTestRepo(), time.Minute))
+
+	cases := []struct {
+		name    string
+		headers []string
+		code    int
+		tenant  string
+	}{
+		{"default", nil, http.StatusOK, tenant.Default},
+		{"header", []string{Header, "acme"}, http.StatusOK, "acme"},
+		{"key tenant", []string{"X-Test-Tenant-Key", "globex"}, http.StatusOK, "globex"},
+		{"key and own tenant", []string{"X-Test-Tenant-Key", "acme", Header, "acme"}, http.StatusOK, "acme"},
+		{"key and other tenant", []string{"X-Test-Tenant-Key", "acme", Header, "globex"}, http.StatusForbidden, ""},
+		{"unknown", []string{Header, "initech"}, http.StatusForbidden, ""},
+	}
+	for _, tc := range cases {
+		w := request(r, http.MethodGet, "/whoami", "", tc.headers...)
+		if w.Code != tc.code {
+			t.Errorf("%s: %d %s, want %d", tc.name, w.Code, w.Body, tc.code)
+			continue
+		}
+		if tc.code == http.StatusOK && w.Body.String() != tc.tenant {
+			t.Errorf("%s: tenant %q, want %q", tc.name, w.Body, tc.tenant)
+		}
+	}
+}
+
+func TestTenantSettingsCache(t *testing.T) {
+	repo := newTestRepo()
+	s := service.NewTenantService(repo, time.Minute)
+	r := newTestRouter(s)
+
+	request(r, http.MethodGet, "/whoami", "", Header, "acme")
+	request(r, http.MethodGet, "/whoami", "", Header, "acme")
+	if repo.reads != 1 {
+		t.Fatalf("tenant read %d times, want once while cached", repo.reads)
+	}
+
+	if w := request(r, http.MethodPut, "/admin/tenants/acme", `{"name":"Acme","settings":{"features":{"votes":false}}}`); w.Code != http.StatusOK {
+		t.Fatalf("PUT: %d %s", w.Code, w.Body)
+	}
+	got, err := s.Resolve(context.Background(), "acme")
+	if err != nil || got.Settings.Enabled(tenant.FeatureVotes) {
+		t.Fatalf("settings after save: %+v, %v; want votes disabled", got, err)
+	}
+}
+
+func TestPutTenantRejected(t *testing.T) {
+	r := newTestRouter(service.NewTenantService(newTestRepo(), time.Minute))
+
+	cases := []struct {
+		name, path, body string
+		headers          []string
+		want             int
+	}{
+		{"bad id", "/admin/tenants/Acme!", `{"name":"x"}`, nil, http.StatusBadRequest},
+		{"negative limit", "/admin/tenants/acme", `{"name":"x","settings":{"max_content_length":-1}}`, nil, http.StatusBadRequest},
+		{"other tenant key", "/admin/tenants/globex", `{"name":"x"}`, []string{"X-Test-Tenant-Key", "acme"}, http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		if w := request(r, http.MethodPut, tc.path, tc.body, tc.headers...); w.Code != tc.want {
+			t.Errorf("%s: %d %s, want %d", tc.name, w.Code, w.Body, tc.want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+)
+
+type TenantRepoInterface interface {
+	Get(ctx context.Context, id string) (*tenant.Tenant, error)
+	List(ctx context.Context) ([]tenant.Tenant, error)
+	Upsert(ctx context.Context, t *tenant.Tenant) (*tenant.Tenant, error)
+}
+
+type TenantRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewTenantRepo(db *pgxpool.Pool) *TenantRepo {
+	return &TenantRepo{db: db}
+}
+
+const tenantColumns = `id, name, settings, created_at, updated_at`
+
+func scanTenant(row interface{ Scan(...any) error }) (*tenant.Tenant, error) {
+	var t tenant.Tenant
+	if err := row.Scan(&t.ID, &t.Name, &t.Settings, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Get возвращает тенант или nil, если его нет
+func (r *TenantRepo) Get(ctx context.Context, id string) (*tenant.Tenant, error) {
+	t, err := scanTenant(r.db.QueryRow(ctx, `SELECT `+tenantColumns+` FROM tenants WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+// List возвращает все тенанты по id
+func (r *TenantRepo) List(ctx context.Context) ([]tenant.Tenant, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+tenantColumns+` FROM tenants ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tenants := []tenant.Tenant{}
+	for rows.Next() {
+		t, err := scanTenant(rows)
+		if err != nil {
+			return nil, err
+		}
+		tenants = append(tenants, *t)
+	}
+	return tenants, rows.Err()
+}
+
+// Upsert создаёт тенант или заменяет имя и настройки существующего
+func (r *TenantRepo) Upsert(ctx context.Context, t *tenant.Tenant) (*tenant.Tenant, error) {
+	return scanTenant(r.db.QueryRow(ctx, `
+		INSERT INTO tenants (id, name, settings, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, settings = EXCLUDED.settings, updated_at = NOW()
+		RETURNING `+tenantColumns,
+		t.ID, t.Name, t.Settings))
+}
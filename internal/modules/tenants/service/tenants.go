@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/pksep/comments/internal/modules/shared/tenant"
+	"github.com/pksep/comments/internal/modules/tenants/repository"
+)
+
+// ErrUnknownTenant — тенант из запроса не зарегистрирован
+var ErrUnknownTenant = errors.New("unknown tenant")
+
+type cachedTenant struct {
+	tenant    *tenant.Tenant
+	expiresAt time.Time
+}
+
+type TenantService struct {
+	repo     repository.TenantRepoInterface
+	cacheTTL time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedTenant
+}
+
+// NewTenantService создаёт сервис тенантов; cacheTTL — сколько тенант живёт в кэше
+// middleware, прежде чем его настройки будут перечитаны из базы
+func NewTenantService(repo repository.TenantRepoInterface, cacheTTL time.Duration) *TenantService {
+	return &TenantService{repo: repo, cacheTTL: cacheTTL, cache: map[string]cachedTenant{}}
+}
+
+// Resolve возвращает тенант для запроса; неизвестный тенант — ErrUnknownTenant.
+// В кэш попадают только существующие тенанты, чтобы перебор id не раздувал его
+func (s *TenantService) Resolve(ctx context.Context, id string) (*tenant.Tenant, error) {
+	now := time.Now()
+	s.mu.Lock()
+	cached, ok := s.cache[id]
+	s.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.tenant, nil
+	}
+
+	t, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	if t == nil {
+		delete(s.cache, id)
+	} else {
+		s.cache[id] = cachedTenant{tenant: t, expiresAt: now.Add(s.cacheTTL)}
+	}
+	s.mu.Unlock()
+	if t == nil {
+		return nil, ErrUnknownTenant
+	}
+	return t, nil
+}
+
+// Get возвращает тенант или nil, если его нет
+func (s *TenantService) Get(ctx context.Context, id string) (*tenant.Tenant, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// List возвращает все тенанты
+func (s *TenantService) List(ctx context.Context) ([]tenant.Tenant, error) {
+	return s.repo.List(ctx)
+}
+
+// Save создаёт или обновляет тенант и сбрасывает его из кэша этой реплики
+func (s *TenantService) Save(ctx context.Context, t tenant.Tenant) (*tenant.Tenant, error) {
+	saved, err := s.repo.Upsert(ctx, &t)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	delete(s.cache, t.ID)
+	s.mu.Unlock()
+	return saved, nil
+}
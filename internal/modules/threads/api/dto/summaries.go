@@ -0,0 +1,12 @@
+package dto
+
+// MaxSummaryIDs — максимум тредов в одном запросе сводки
+const MaxSummaryIDs = 1000
+
+type SummariesDTO struct {
+	ThreadIDs []string `json:"thread_ids" binding:"required,max=1000,dive,uuid"`
+}
+
+type SummariesQuery struct {
+	IDs string `form:"ids" binding:"required"`
+}
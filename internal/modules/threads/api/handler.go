@@ -1,6 +1,13 @@
 package api
 
 import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pksep/comments/internal/modules/threads/api/dto"
 	"github.com/pksep/comments/internal/modules/threads/service"
 )
 
@@ -11,3 +18,50 @@ type ThreadHandler struct {
 func NewThreadHandler(service *service.ThreadService) *ThreadHandler {
 	return &ThreadHandler{service: service}
 }
+
+func (h *ThreadHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	threads := rg.Group("/threads")
+	{
+		threads.GET("/summaries", h.SummariesByQuery) // ids=id1,id2
+		threads.POST("/summaries", h.Summaries)       // для сотен id, в теле
+	}
+}
+
+func (h *ThreadHandler) Summaries(c *gin.Context) {
+	var body dto.SummariesDTO
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.respondSummaries(c, body.ThreadIDs)
+}
+
+func (h *ThreadHandler) SummariesByQuery(c *gin.Context) {
+	var query dto.SummariesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ids := strings.Split(query.IDs, ",")
+	if len(ids) > dto.MaxSummaryIDs {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("too many ids, max %d", dto.MaxSummaryIDs)})
+		return
+	}
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid thread id %q", id)})
+			return
+		}
+	}
+	h.respondSummaries(c, ids)
+}
+
+func (h *ThreadHandler) respondSummaries(c *gin.Context, ids []string) {
+	summaries, err := h.service.Summaries(c, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, summaries)
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pksep/comments/internal/modules/threads/api/dto"
+	"github.com/pksep/comments/internal/modules/threads/model"
+	"github.com/pksep/comments/internal/modules/threads/repository"
+	"github.com/pksep/comments/internal/modules/threads/service"
+)
+
+type summariesRepo struct {
+	repository.ThreadRepoInterface
+	asked []string
+}
+
+func (r *summariesRepo) Summaries(ctx context.Context, ids []string) ([]model.ThreadSummary, error) {
+	r.asked = ids
+	out := make([]model.ThreadSummary, len(ids))
+	for i, id := range ids {
+		out[i] = model.ThreadSummary{ThreadID: id, CommentCount: 1}
+	}
+	return out, nil
+}
+
+func newTestRouter(repo *summariesRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	NewThreadHandler(service.NewThreadService(repo)).RegisterRoutes(r.Group("/api"))
+	return r
+}
+
+func request(r *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestSummaries(t *testing.T) {
+	a, b := uuid.NewString(), uuid.NewString()
+
+	repo := &summariesRepo{}
+	r := newTestRouter(repo)
+	if w := request(r, http.MethodGet, "/api/threads/summaries?ids="+a+","+b, ""); w.Code != http.StatusOK {
+		t.Fatalf("GET: %d %s", w.Code, w.Body)
+	}
+	if len(repo.asked) != 2 || repo.asked[0] != a || repo.asked[1] != b {
+		t.Fatalf("asked for %v", repo.asked)
+	}
+
+	if w := request(r, http.MethodPost, "/api/threads/summaries", fmt.Sprintf(`{"thread_ids":[%q]}`, a)); w.Code != http.StatusOK {
+		t.Fatalf("POST: %d %s", w.Code, w.Body)
+	}
+}
+
+func TestSummariesRejectsBadInput(t *testing.T) {
+	tooMany := make([]string, dto.MaxSummaryIDs+1)
+	for i := range tooMany {
+		tooMany[i] = uuid.NewString()
+	}
+
+	r := newTestRouter(&summariesRepo{})
+	cases := []struct{ method, path, body string }{
+		{http.MethodGet, "/api/threads/summaries", ""},
+		{http.MethodGet, "/api/threads/summaries?ids=not-a-uuid", ""},
+		{http.MethodGet, "/api/threads/summaries?ids=" + strings.Join(tooMany, ","), ""},
+		{http.MethodPost, "/api/threads/summaries", `{"thread_ids":["not-a-uuid"]}`},
+		{http.MethodPost, "/api/threads/summaries", `{"thread_ids":["` + strings.Join(tooMany, `","`) + `"]}`},
+	}
+	for _, tc := range cases {
+		if w := request(r, tc.method, tc.path, tc.body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s %.60s: %d, want 400", tc.method, tc.path+tc.body, w.Code)
+		}
+	}
+}
@@ -1,5 +1,18 @@
 package model
 
+import "time"
+
 type Thread struct {
 	ID string `json:"id" db:"id"`
 }
+
+// ThreadSummary — денормализованные счётчики треда из thread_stats.
+// Учитываются только опубликованные комментарии
+type ThreadSummary struct {
+	ThreadID          string     `json:"thread_id" db:"thread_id"`
+	CommentCount      int        `json:"comment_count" db:"comment_count"`
+	ParticipantsCount int        `json:"participants_count" db:"participants_count"`
+	LastCommentID     *string    `json:"last_comment_id,omitempty" db:"last_comment_id"`
+	LastCommentAt     *time.Time `json:"last_comment_at,omitempty" db:"last_comment_at"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+}
@@ -5,11 +5,13 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pksep/comments/internal/modules/shared/tenant"
 	"github.com/pksep/comments/internal/modules/threads/model"
 )
 
 type ThreadRepoInterface interface {
 	Create(ctx context.Context) (*model.Thread, error)
+	Summaries(ctx context.Context, ids []string) ([]model.ThreadSummary, error)
 }
 
 type ThreadRepo struct {
@@ -26,8 +28,8 @@ func (r *ThreadRepo) Create(ctx context.Context) (*model.Thread, error) {
 	}
 
 	_, err := r.db.Exec(ctx,
-		`INSERT INTO threads (id) VALUES ($1)`,
-		thread.ID,
+		`INSERT INTO threads (id, tenant_id) VALUES ($1, $2)`,
+		thread.ID, tenant.IDOrDefault(ctx),
 	)
 	if err != nil {
 		return nil, err
@@ -35,3 +37,32 @@ func (r *ThreadRepo) Create(ctx context.Context) (*model.Thread, error) {
 
 	return thread, nil
 }
+
+// Summaries возвращает счётчики для набора тредов одним запросом по первичному ключу
+// thread_stats. Треды без счётчиков и треды других тенантов в ответ не попадают
+func (r *ThreadRepo) Summaries(ctx context.Context, ids []string) ([]model.ThreadSummary, error) {
+	summaries := []model.ThreadSummary{}
+	if len(ids) == 0 {
+		return summaries, nil
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT ts.thread_id, ts.comment_count, ts.participants_count, ts.last_comment_id, ts.last_comment_at, ts.updated_at
+		FROM thread_stats ts
+		JOIN threads t ON t.id = ts.thread_id
+		WHERE ts.thread_id = ANY($1::uuid[]) AND ($2::text = '' OR t.tenant_id = $2)
+	`, ids, tenant.ID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s model.ThreadSummary
+		if err := rows.Scan(&s.ThreadID, &s.CommentCount, &s.ParticipantsCount, &s.LastCommentID, &s.LastCommentAt, &s.UpdatedAt); err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, s)
+	}
+	return summaries, rows.Err()
+}
@@ -1,6 +1,9 @@
 package service
 
 import (
+	"context"
+
+	"github.com/pksep/comments/internal/modules/threads/model"
 	"github.com/pksep/comments/internal/modules/threads/repository"
 )
 
@@ -10,4 +13,9 @@ type ThreadService struct {
 
 func NewThreadService(repo repository.ThreadRepoInterface) *ThreadService {
 	return &ThreadService{repo: repo}
-}
\ No newline at end of file
+}
+
+// Summaries возвращает счётчики тредов по списку id
+func (s *ThreadService) Summaries(ctx context.Context, ids []string) ([]model.ThreadSummary, error) {
+	return s.repo.Summaries(ctx, ids)
+}
@@ -1,26 +1,75 @@
 package services
 
 import (
+	"github.com/pksep/comments/internal/config"
+	apikeysRepo "github.com/pksep/comments/internal/modules/apikeys/repository"
+	auditRepo "github.com/pksep/comments/internal/modules/audit/repository"
+	"github.com/pksep/comments/internal/modules/comments/moderation"
 	commentsRepo "github.com/pksep/comments/internal/modules/comments/repository"
+	digestRepo "github.com/pksep/comments/internal/modules/digest/repository"
+	draftsRepo "github.com/pksep/comments/internal/modules/drafts/repository"
+	gdprRepo "github.com/pksep/comments/internal/modules/gdpr/repository"
+	idempotencyRepo "github.com/pksep/comments/internal/modules/idempotency/repository"
+	subscriptionsRepo "github.com/pksep/comments/internal/modules/subscriptions/repository"
+	tenantsRepo "github.com/pksep/comments/internal/modules/tenants/repository"
 	threadsRepo "github.com/pksep/comments/internal/modules/threads/repository"
 
+	apikeysSvc "github.com/pksep/comments/internal/modules/apikeys/service"
+	auditSvc "github.com/pksep/comments/internal/modules/audit/service"
 	commentsSvc "github.com/pksep/comments/internal/modules/comments/service"
-	threadsSvc  "github.com/pksep/comments/internal/modules/threads/service"
+	"github.com/pksep/comments/internal/modules/digest/notifier"
+	digestSvc "github.com/pksep/comments/internal/modules/digest/service"
+	draftsSvc "github.com/pksep/comments/internal/modules/drafts/service"
+	gdprSvc "github.com/pksep/comments/internal/modules/gdpr/service"
+	idempotencySvc "github.com/pksep/comments/internal/modules/idempotency/service"
+	importsSvc "github.com/pksep/comments/internal/modules/imports/service"
+	subscriptionsSvc "github.com/pksep/comments/internal/modules/subscriptions/service"
+	tenantsSvc "github.com/pksep/comments/internal/modules/tenants/service"
+	threadsSvc "github.com/pksep/comments/internal/modules/threads/service"
 )
 
 // Services объединяет все бизнес-сервисы
 type Services struct {
-	CommentService *commentsSvc.CommentService
-	ThreadService  *threadsSvc.ThreadService
+	CommentService      *commentsSvc.CommentService
+	ThreadService       *threadsSvc.ThreadService
+	AuditService        *auditSvc.AuditService
+	IdempotencyService  *idempotencySvc.IdempotencyService
+	GDPRService         *gdprSvc.GDPRService
+	ImportService       *importsSvc.ImportService
+	DraftService        *draftsSvc.DraftService
+	DigestService       *digestSvc.DigestService
+	SubscriptionService *subscriptionsSvc.SubscriptionService
+	TenantService       *tenantsSvc.TenantService
+	KeyService          *apikeysSvc.KeyService
 }
 
 // NewServices конструктор, принимает репозитории и возвращает набор сервисов
 func NewServices(
+	cfg *config.Config,
 	commentRepo commentsRepo.CommentRepoInterface,
 	threadRepo threadsRepo.ThreadRepoInterface,
+	auditRepo auditRepo.AuditRepoInterface,
+	idempotencyRepo idempotencyRepo.KeyRepoInterface,
+	gdprJobRepo gdprRepo.JobRepoInterface,
+	draftRepo draftsRepo.DraftRepoInterface,
+	digestRepo digestRepo.DigestRepoInterface,
+	subscriptionRepo subscriptionsRepo.SubscriptionRepoInterface,
+	tenantRepo tenantsRepo.TenantRepoInterface,
+	keyRepo apikeysRepo.KeyRepoInterface,
+	moderationPipeline *moderation.Pipeline,
+	digestNotifier notifier.Notifier,
 ) *Services {
 	return &Services{
-		CommentService: commentsSvc.NewCommentService(commentRepo),
-		ThreadService:  threadsSvc.NewThreadService(threadRepo),
+		CommentService:      commentsSvc.NewCommentService(commentRepo, moderationPipeline, draftRepo, subscriptionRepo, cfg.Moderation.AutoHideReports, cfg.MaxPinnedPerThread),
+		ThreadService:       threadsSvc.NewThreadService(threadRepo),
+		AuditService:        auditSvc.NewAuditService(auditRepo),
+		IdempotencyService:  idempotencySvc.NewIdempotencyService(idempotencyRepo, cfg.IdempotencyTTL),
+		GDPRService:         gdprSvc.NewGDPRService(gdprJobRepo, commentRepo, auditRepo, draftRepo, subscriptionRepo, idempotencyRepo),
+		ImportService:       importsSvc.NewImportService(commentRepo),
+		DraftService:        draftsSvc.NewDraftService(draftRepo, cfg.DraftTTL),
+		DigestService:       digestSvc.NewDigestService(digestRepo, digestNotifier, cfg.Digest.Interval),
+		SubscriptionService: subscriptionsSvc.NewSubscriptionService(subscriptionRepo),
+		TenantService:       tenantsSvc.NewTenantService(tenantRepo, cfg.TenantCacheTTL),
+		KeyService:          apikeysSvc.NewKeyService(keyRepo),
 	}
 }
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_comments_status_pending;
+
+ALTER TABLE comments
+DROP COLUMN IF EXISTS moderation_reason;
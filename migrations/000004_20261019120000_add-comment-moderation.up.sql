@@ -0,0 +1,6 @@
+ALTER TABLE comments
+ADD COLUMN IF NOT EXISTS moderation_reason TEXT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_comments_status_pending
+ON comments (created_at)
+WHERE status = 'pending';
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_comments_status_hidden;
+
+DROP TABLE IF EXISTS comment_reports;
+
+ALTER TABLE comments
+DROP COLUMN IF EXISTS moderated_at,
+DROP COLUMN IF EXISTS moderated_by;
@@ -0,0 +1,21 @@
+ALTER TABLE comments
+ADD COLUMN IF NOT EXISTS moderated_by TEXT NULL,
+ADD COLUMN IF NOT EXISTS moderated_at TIMESTAMPTZ NULL;
+
+CREATE TABLE IF NOT EXISTS comment_reports (
+    id UUID PRIMARY KEY,
+    comment_id UUID NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    reporter_id TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    resolved_at TIMESTAMPTZ NULL,
+    UNIQUE (comment_id, reporter_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_reports_unresolved
+ON comment_reports (comment_id)
+WHERE resolved_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_comments_status_hidden
+ON comments (created_at)
+WHERE status = 'hidden';
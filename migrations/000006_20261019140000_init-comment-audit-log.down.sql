@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS comment_audit_log;
+
+DROP FUNCTION IF EXISTS comment_audit_log_append_only();
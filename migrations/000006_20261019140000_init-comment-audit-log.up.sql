@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS comment_audit_log (
+    id UUID PRIMARY KEY,
+    comment_id UUID NOT NULL,
+    thread_id UUID NULL,
+    actor_id TEXT NOT NULL,
+    action TEXT NOT NULL,
+    before JSONB NULL,
+    after JSONB NULL,
+    request_id TEXT NOT NULL DEFAULT '',
+    ip TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_audit_log_created_at ON comment_audit_log (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_comment_audit_log_comment ON comment_audit_log (comment_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_comment_audit_log_actor ON comment_audit_log (actor_id, created_at);
+
+-- Журнал только дополняется: изменение и удаление записей запрещены
+CREATE OR REPLACE FUNCTION comment_audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'comment_audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_comment_audit_log_append_only ON comment_audit_log;
+CREATE TRIGGER trg_comment_audit_log_append_only
+BEFORE UPDATE OR DELETE ON comment_audit_log
+FOR EACH ROW EXECUTE FUNCTION comment_audit_log_append_only();
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS thread_stats;
+
+DROP INDEX IF EXISTS idx_comments_thread_created;
@@ -0,0 +1,26 @@
+CREATE INDEX IF NOT EXISTS idx_comments_thread_created ON comments (thread_id, created_at);
+
+CREATE TABLE IF NOT EXISTS thread_stats (
+    thread_id UUID PRIMARY KEY REFERENCES threads(id) ON DELETE CASCADE,
+    comment_count INT NOT NULL DEFAULT 0,
+    participants_count INT NOT NULL DEFAULT 0,
+    last_comment_id UUID NULL,
+    last_comment_at TIMESTAMPTZ NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+-- Заполняем счётчики для уже существующих тредов
+INSERT INTO thread_stats (thread_id, comment_count, participants_count, last_comment_id, last_comment_at, updated_at)
+SELECT t.id,
+       COUNT(c.id),
+       COUNT(DISTINCT c.author_id),
+       (SELECT l.id FROM comments l
+        WHERE l.thread_id = t.id AND l.deleted_at IS NULL AND l.status NOT IN ('pending', 'hidden')
+        ORDER BY l.created_at DESC, l.id DESC LIMIT 1),
+       MAX(c.created_at),
+       NOW()
+FROM threads t
+LEFT JOIN comments c
+       ON c.thread_id = t.id AND c.deleted_at IS NULL AND c.status NOT IN ('pending', 'hidden')
+GROUP BY t.id
+ON CONFLICT (thread_id) DO NOTHING;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_comments_author_created ON comments (author_id, created_at DESC);
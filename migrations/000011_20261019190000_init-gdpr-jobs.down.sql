@@ -0,0 +1,7 @@
+CREATE OR REPLACE FUNCTION comment_audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'comment_audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TABLE IF EXISTS gdpr_jobs;
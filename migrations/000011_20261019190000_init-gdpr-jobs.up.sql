@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS gdpr_jobs (
+    id UUID PRIMARY KEY,
+    author_id TEXT NOT NULL,
+    pseudonym TEXT NOT NULL,
+    requested_by TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'queued',
+    total INT NOT NULL DEFAULT 0,
+    processed INT NOT NULL DEFAULT 0,
+    attempts INT NOT NULL DEFAULT 0,
+    error TEXT NULL,
+    locked_until TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ NULL
+);
+
+-- Не больше одного активного задания на автора
+CREATE UNIQUE INDEX IF NOT EXISTS idx_gdpr_jobs_active_author
+ON gdpr_jobs (author_id)
+WHERE status IN ('queued', 'running');
+
+-- Журнал аудита остаётся append-only, кроме обезличивания по запросу на удаление данных:
+-- оно выполняется в транзакции с SET LOCAL comments.audit_redaction = 'on'
+CREATE OR REPLACE FUNCTION comment_audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND current_setting('comments.audit_redaction', true) = 'on' THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'comment_audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
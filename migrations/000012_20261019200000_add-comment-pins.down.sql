@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_comments_thread_pinned;
+
+ALTER TABLE comments
+DROP COLUMN IF EXISTS pinned_by,
+DROP COLUMN IF EXISTS pinned_at;
@@ -0,0 +1,7 @@
+ALTER TABLE comments
+ADD COLUMN IF NOT EXISTS pinned_at TIMESTAMPTZ NULL,
+ADD COLUMN IF NOT EXISTS pinned_by TEXT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_comments_thread_pinned
+ON comments (thread_id, pinned_at)
+WHERE pinned_at IS NOT NULL;
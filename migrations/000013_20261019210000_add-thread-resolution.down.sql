@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_threads_accepted_comment;
+
+ALTER TABLE threads
+DROP COLUMN IF EXISTS resolved_at,
+DROP COLUMN IF EXISTS resolved_by,
+DROP COLUMN IF EXISTS accepted_comment_id;
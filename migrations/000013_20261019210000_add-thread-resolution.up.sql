@@ -0,0 +1,8 @@
+ALTER TABLE threads
+ADD COLUMN IF NOT EXISTS accepted_comment_id UUID NULL REFERENCES comments(id) ON DELETE SET NULL,
+ADD COLUMN IF NOT EXISTS resolved_by TEXT NULL,
+ADD COLUMN IF NOT EXISTS resolved_at TIMESTAMPTZ NULL;
+
+CREATE INDEX IF NOT EXISTS idx_threads_accepted_comment
+ON threads (accepted_comment_id)
+WHERE accepted_comment_id IS NOT NULL;
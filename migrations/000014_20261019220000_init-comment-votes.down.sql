@@ -0,0 +1,6 @@
+ALTER TABLE comments
+DROP COLUMN IF EXISTS score,
+DROP COLUMN IF EXISTS downvotes,
+DROP COLUMN IF EXISTS upvotes;
+
+DROP TABLE IF EXISTS comment_votes;
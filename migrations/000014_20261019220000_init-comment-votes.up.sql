@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS comment_votes (
+    comment_id UUID NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    voter_id TEXT NOT NULL,
+    value SMALLINT NOT NULL CHECK (value IN (-1, 1)),
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (comment_id, voter_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_votes_voter ON comment_votes (voter_id);
+
+-- Счётчики голосов хранятся в строке комментария и меняются в транзакции голосования
+ALTER TABLE comments
+ADD COLUMN IF NOT EXISTS upvotes INT NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS downvotes INT NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS score INT NOT NULL DEFAULT 0;
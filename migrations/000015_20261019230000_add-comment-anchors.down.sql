@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_comments_thread_anchor_block;
+
+ALTER TABLE comments
+DROP COLUMN IF EXISTS anchor;
@@ -0,0 +1,7 @@
+ALTER TABLE comments
+ADD COLUMN IF NOT EXISTS anchor JSONB NULL
+    CHECK (anchor IS NULL OR (jsonb_typeof(anchor) = 'object' AND anchor ? 'block_id'));
+
+CREATE INDEX IF NOT EXISTS idx_comments_thread_anchor_block
+ON comments (thread_id, (anchor->>'block_id'))
+WHERE anchor IS NOT NULL;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS comment_drafts (
+    author_id TEXT NOT NULL,
+    thread_id UUID NOT NULL,
+    answer_comment_id UUID NULL,
+    content TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (author_id, thread_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_drafts_expires ON comment_drafts (expires_at);
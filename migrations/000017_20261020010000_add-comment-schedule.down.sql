@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_comments_scheduled;
+
+ALTER TABLE comments
+DROP COLUMN IF EXISTS publish_at;
@@ -0,0 +1,7 @@
+ALTER TABLE comments
+ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ NULL;
+
+-- Очередь планировщика публикации
+CREATE INDEX IF NOT EXISTS idx_comments_scheduled
+ON comments (publish_at)
+WHERE status = 'scheduled';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_comments_answer_comment;
+
+DROP TABLE IF EXISTS digest_state;
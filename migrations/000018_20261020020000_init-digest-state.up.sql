@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS digest_state (
+    user_id TEXT PRIMARY KEY,
+    -- Конец окна последней отправленной сводки
+    last_sent_at TIMESTAMPTZ NULL,
+    digests_sent INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+-- Поиск ответов на комментарии пользователя
+CREATE INDEX IF NOT EXISTS idx_comments_answer_comment ON comments (answer_comment_id);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS thread_subscriptions (
+    thread_id UUID NOT NULL REFERENCES threads(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,
+    state TEXT NOT NULL CHECK (state IN ('following', 'muted')),
+    -- Подписка создана автоматически при первом комментарии пользователя в треде
+    auto BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (thread_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_thread_subscriptions_user ON thread_subscriptions (user_id, updated_at DESC);
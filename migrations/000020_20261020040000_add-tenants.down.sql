@@ -0,0 +1,15 @@
+DROP POLICY IF EXISTS tenant_isolation ON comments;
+DROP POLICY IF EXISTS tenant_isolation ON threads;
+
+ALTER TABLE comments NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE comments DISABLE ROW LEVEL SECURITY;
+ALTER TABLE threads NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE threads DISABLE ROW LEVEL SECURITY;
+
+DROP INDEX IF EXISTS idx_comments_tenant_author;
+DROP INDEX IF EXISTS idx_threads_tenant;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE threads DROP COLUMN IF EXISTS tenant_id;
+
+DROP TABLE IF EXISTS tenants;
@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS tenants (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    settings JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO tenants (id, name) VALUES ('default', 'Default') ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE threads
+ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+
+ALTER TABLE comments
+ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+
+CREATE INDEX IF NOT EXISTS idx_threads_tenant ON threads (tenant_id);
+CREATE INDEX IF NOT EXISTS idx_comments_tenant_author ON comments (tenant_id, author_id, created_at DESC);
+
+-- Изоляция на уровне строк действует, когда сервис запущен с TENANT_RLS=true
+-- и выставляет app.tenant_id на соединении. Пустой app.tenant_id (фоновые задачи,
+-- миграции, сервис без TENANT_RLS) видит все строки, поэтому FORCE безопасен и для владельца
+ALTER TABLE threads ENABLE ROW LEVEL SECURITY;
+ALTER TABLE threads FORCE ROW LEVEL SECURITY;
+ALTER TABLE comments ENABLE ROW LEVEL SECURITY;
+ALTER TABLE comments FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON threads
+USING (COALESCE(current_setting('app.tenant_id', true), '') IN ('', tenant_id))
+WITH CHECK (COALESCE(current_setting('app.tenant_id', true), '') IN ('', tenant_id));
+
+CREATE POLICY tenant_isolation ON comments
+USING (COALESCE(current_setting('app.tenant_id', true), '') IN ('', tenant_id))
+WITH CHECK (COALESCE(current_setting('app.tenant_id', true), '') IN ('', tenant_id));
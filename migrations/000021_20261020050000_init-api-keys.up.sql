@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL,
+    -- Начало ключа для опознания в списках; сам ключ хранится только в виде SHA-256
+    prefix TEXT NOT NULL,
+    key_hash TEXT NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL CHECK (scopes <@ ARRAY['read', 'write', 'moderate', 'admin'] AND cardinality(scopes) > 0),
+    -- Ключ работает только в этом тенанте; NULL — в любом
+    tenant_id TEXT REFERENCES tenants(id),
+    -- Ключ, взамен которого выпущен этот при ротации
+    rotated_from UUID REFERENCES api_keys(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_tenant ON api_keys (tenant_id, created_at DESC);
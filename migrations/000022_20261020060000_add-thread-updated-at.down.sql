@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS trg_threads_touch_on_resolution ON threads;
+DROP FUNCTION IF EXISTS threads_touch_on_resolution();
+
+DROP TRIGGER IF EXISTS trg_comments_touch_thread_delete ON comments;
+DROP TRIGGER IF EXISTS trg_comments_touch_thread_update ON comments;
+DROP TRIGGER IF EXISTS trg_comments_touch_thread_insert ON comments;
+DROP FUNCTION IF EXISTS threads_touch_by_comments();
+
+ALTER TABLE threads DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,50 @@
+-- Время последнего изменения треда — основа ETag и Last-Modified для условных
+-- запросов. Меняется триггерами при любом изменении комментариев треда (включая
+-- голоса, закрепления и модерацию) и принятого ответа, поэтому чтению не нужно
+-- перебирать комментарии, чтобы понять, изменился ли тред.
+-- Значение строго растёт, даже если часы сервера совпали или ушли назад
+ALTER TABLE threads
+ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+CREATE OR REPLACE FUNCTION threads_touch_by_comments() RETURNS trigger AS $$
+BEGIN
+    UPDATE threads
+    SET updated_at = GREATEST(clock_timestamp(), updated_at + INTERVAL '1 microsecond')
+    WHERE id IN (SELECT DISTINCT thread_id FROM changed_comments WHERE thread_id IS NOT NULL);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Триггеры уровня оператора: пакетный импорт обновляет тред один раз, а не на каждую строку
+DROP TRIGGER IF EXISTS trg_comments_touch_thread_insert ON comments;
+CREATE TRIGGER trg_comments_touch_thread_insert
+AFTER INSERT ON comments
+REFERENCING NEW TABLE AS changed_comments
+FOR EACH STATEMENT EXECUTE FUNCTION threads_touch_by_comments();
+
+DROP TRIGGER IF EXISTS trg_comments_touch_thread_update ON comments;
+CREATE TRIGGER trg_comments_touch_thread_update
+AFTER UPDATE ON comments
+REFERENCING NEW TABLE AS changed_comments
+FOR EACH STATEMENT EXECUTE FUNCTION threads_touch_by_comments();
+
+DROP TRIGGER IF EXISTS trg_comments_touch_thread_delete ON comments;
+CREATE TRIGGER trg_comments_touch_thread_delete
+AFTER DELETE ON comments
+REFERENCING OLD TABLE AS changed_comments
+FOR EACH STATEMENT EXECUTE FUNCTION threads_touch_by_comments();
+
+CREATE OR REPLACE FUNCTION threads_touch_on_resolution() RETURNS trigger AS $$
+BEGIN
+    NEW.updated_at = GREATEST(clock_timestamp(), OLD.updated_at + INTERVAL '1 microsecond');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_threads_touch_on_resolution ON threads;
+CREATE TRIGGER trg_threads_touch_on_resolution
+BEFORE UPDATE OF accepted_comment_id, resolved_at ON threads
+FOR EACH ROW
+WHEN (OLD.accepted_comment_id IS DISTINCT FROM NEW.accepted_comment_id
+      OR OLD.resolved_at IS DISTINCT FROM NEW.resolved_at)
+EXECUTE FUNCTION threads_touch_on_resolution();
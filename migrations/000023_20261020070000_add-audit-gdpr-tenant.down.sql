@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_gdpr_jobs_active_author;
+ALTER TABLE gdpr_jobs DROP COLUMN IF EXISTS tenant_id;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_gdpr_jobs_active_author
+ON gdpr_jobs (author_id)
+WHERE status IN ('queued', 'running');
+
+DROP INDEX IF EXISTS idx_comment_audit_log_tenant;
+ALTER TABLE comment_audit_log DROP COLUMN IF EXISTS tenant_id;
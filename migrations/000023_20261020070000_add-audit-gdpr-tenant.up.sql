@@ -0,0 +1,23 @@
+-- Журнал аудита и задания на удаление данных принадлежат тенанту: клиент с ключом
+-- тенанта видит историю и удаляет данные автора только в своём тенанте
+ALTER TABLE comment_audit_log
+ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+
+-- Журнал append-only, поэтому триггер снимается только на время заполнения
+ALTER TABLE comment_audit_log DISABLE TRIGGER trg_comment_audit_log_append_only;
+UPDATE comment_audit_log a
+SET tenant_id = c.tenant_id
+FROM comments c
+WHERE c.id = a.comment_id AND c.tenant_id <> a.tenant_id;
+ALTER TABLE comment_audit_log ENABLE TRIGGER trg_comment_audit_log_append_only;
+
+CREATE INDEX IF NOT EXISTS idx_comment_audit_log_tenant ON comment_audit_log (tenant_id, created_at);
+
+ALTER TABLE gdpr_jobs
+ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+
+-- Не больше одного активного задания на автора в тенанте
+DROP INDEX IF EXISTS idx_gdpr_jobs_active_author;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_gdpr_jobs_active_author
+ON gdpr_jobs (tenant_id, author_id)
+WHERE status IN ('queued', 'running');
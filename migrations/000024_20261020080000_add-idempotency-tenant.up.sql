@@ -0,0 +1,4 @@
+-- Тенант ключа нужен, чтобы удаление данных автора вычищало сохранённые ответы
+-- только в его тенанте
+ALTER TABLE idempotency_keys
+ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default';
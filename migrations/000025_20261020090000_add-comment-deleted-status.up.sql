@@ -0,0 +1,15 @@
+-- Статус комментария до удаления: восстановление возвращает его, а не публикует
+-- комментарий, ожидавший модерации или планировщика
+ALTER TABLE comments
+ADD COLUMN IF NOT EXISTS deleted_status TEXT NULL;
+
+-- Для уже удалённых комментариев статус берётся из снимка аудита удаления
+UPDATE comments c
+SET deleted_status = (
+    SELECT a.before->>'status'
+    FROM comment_audit_log a
+    WHERE a.comment_id = c.id AND a.action = 'delete' AND a.before IS NOT NULL
+    ORDER BY a.created_at DESC
+    LIMIT 1
+)
+WHERE c.deleted_at IS NOT NULL AND c.deleted_status IS NULL;